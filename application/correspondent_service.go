@@ -0,0 +1,186 @@
+package application
+
+import (
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+// CorrespondentService defines an application service for managing correspondent use-cases.
+type CorrespondentService interface {
+	// GetUserCorrespondents returns the correspondents owned by the given user including their document
+	// statistics with respect to the given page request.
+	GetUserCorrespondents(username string, pr domain.PageRequest) ([]domain.Correspondent, int64, error)
+
+	// GetUserCorrespondentByID returns the correspondent with the given ID owned by the given user.
+	GetUserCorrespondentByID(username string, id uint) (*domain.Correspondent, error)
+
+	// CreateNewCorrespondent creates the given new correspondent owned by the given user.
+	CreateNewCorrespondent(username string, correspondent *domain.Correspondent) (*domain.Correspondent, error)
+
+	// UpdateUserCorrespondent updates the given correspondent owned by the given user.
+	UpdateUserCorrespondent(username string, correspondent *domain.Correspondent) (*domain.Correspondent, error)
+
+	// DeleteUserCorrespondent deletes the correspondent with the given ID owned by the given user, removing it
+	// from all documents.
+	DeleteUserCorrespondent(username string, id uint) error
+}
+
+type correspondentServiceImpl struct {
+	users          domain.Users
+	correspondents domain.Correspondents
+	documents      domain.Documents
+	documentIndex  domain.DocumentIndex
+}
+
+// NewCorrespondentService creates a new correspondent service.
+func NewCorrespondentService(
+	users domain.Users,
+	correspondents domain.Correspondents,
+	documents domain.Documents,
+	documentIndex domain.DocumentIndex,
+) CorrespondentService {
+	return &correspondentServiceImpl{
+		users:          users,
+		correspondents: correspondents,
+		documents:      documents,
+		documentIndex:  documentIndex,
+	}
+}
+
+func (s *correspondentServiceImpl) GetUserCorrespondents(
+	username string,
+	pr domain.PageRequest,
+) ([]domain.Correspondent, int64, error) {
+	correspondents, count, err := s.correspondents.FindByUsername(domain.Name(username), pr)
+	if err != nil {
+		return nil, -1, errors.Wrap(err, "Failed to retrieve correspondents")
+	}
+
+	return correspondents, int64(count), nil
+}
+
+func (s *correspondentServiceImpl) GetUserCorrespondentByID(username string, id uint) (*domain.Correspondent, error) {
+	return s.expectUserCorrespondentExists(domain.Name(username), domain.CorrespondentID(id))
+}
+
+func (s *correspondentServiceImpl) CreateNewCorrespondent(
+	username string,
+	correspondent *domain.Correspondent,
+) (*domain.Correspondent, error) {
+	owner, err := s.users.GetByUsername(domain.Name(username))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.expectCorrespondentNameNotAlreadyTaken(owner.Username, correspondent.Name); err != nil {
+		return nil, err
+	}
+
+	correspondent.ID = 0
+	correspondent.Owner = owner
+
+	newCorrespondent, err := s.correspondents.Add(correspondent)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create correspondent")
+	}
+
+	return newCorrespondent, nil
+}
+
+func (s *correspondentServiceImpl) UpdateUserCorrespondent(
+	username string,
+	correspondent *domain.Correspondent,
+) (*domain.Correspondent, error) {
+	originalCorrespondent, err := s.expectUserCorrespondentExists(domain.Name(username), correspondent.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if correspondent.Name == originalCorrespondent.Name {
+		return originalCorrespondent, nil
+	}
+
+	if err := s.expectCorrespondentNameNotAlreadyTaken(originalCorrespondent.Owner.Username, correspondent.Name); err != nil {
+		return nil, err
+	}
+
+	originalCorrespondent.Name = correspondent.Name
+	correspondent, err = s.correspondents.Update(originalCorrespondent)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to update correspondent")
+	}
+
+	documentNumbers, err := s.getCorrespondentDocumentNumbers(correspondent)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := indexDocuments(s.documentIndex, documentNumbers); err != nil {
+		return nil, err
+	}
+
+	return correspondent, nil
+}
+
+func (s *correspondentServiceImpl) DeleteUserCorrespondent(username string, id uint) error {
+	correspondent, err := s.expectUserCorrespondentExists(domain.Name(username), domain.CorrespondentID(id))
+	if err != nil {
+		return err
+	}
+
+	documentNumbers, err := s.getCorrespondentDocumentNumbers(correspondent)
+	if err != nil {
+		return err
+	}
+
+	if err := s.correspondents.Delete(correspondent); err != nil {
+		return err
+	}
+
+	return indexDocuments(s.documentIndex, documentNumbers)
+}
+
+/* Helper Methods */
+
+// getCorrespondentDocumentNumbers returns the document numbers of all
+// documents of the given correspondent.
+func (s *correspondentServiceImpl) getCorrespondentDocumentNumbers(
+	correspondent *domain.Correspondent,
+) ([]domain.DocumentNumber, error) {
+	filter := domain.DocumentFilter{CorrespondentID: &correspondent.ID}
+	return findAllDocumentNumbers(s.documents, correspondent.Owner.Username, filter)
+}
+
+func (s *correspondentServiceImpl) expectUserCorrespondentExists(
+	username domain.Name,
+	id domain.CorrespondentID,
+) (*domain.Correspondent, error) {
+	correspondent, err := s.correspondents.GetByID(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve correspondent")
+	}
+
+	if correspondent == nil {
+		return nil, NotFoundError.Newf("Correspondent %d does not exist", id)
+	}
+
+	if correspondent.Owner.Username != username {
+		return nil, ForbiddenError.Newf("Access to correspondent %d not permitted", id)
+	}
+
+	return correspondent, nil
+}
+
+func (s *correspondentServiceImpl) expectCorrespondentNameNotAlreadyTaken(username domain.Name, name domain.Name) error {
+	correspondent, err := s.correspondents.GetByUsernameAndName(username, name)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve correspondent")
+	}
+
+	if correspondent != nil {
+		err := ConflictError.Newf("Correspondent '%s' already exists", name)
+		return errors.AddContext(err, "name", "unique")
+	}
+
+	return nil
+}
@@ -0,0 +1,232 @@
+package application
+
+import (
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+// CustomFieldService defines an application service for managing custom field use-cases.
+type CustomFieldService interface {
+	// GetUserCustomFields returns the custom fields owned by the given user with respect to the given page
+	// request.
+	GetUserCustomFields(username string, pr domain.PageRequest) ([]domain.CustomField, int64, error)
+
+	// GetUserCustomFieldByID returns the custom field with the given ID owned by the given user.
+	GetUserCustomFieldByID(username string, id uint) (*domain.CustomField, error)
+
+	// GetUserCustomFieldsByIDs returns the custom fields with the given IDs, all of them being owned by the given
+	// user.
+	GetUserCustomFieldsByIDs(username string, ids []uint) ([]domain.CustomField, error)
+
+	// CreateNewCustomField creates the given new custom field owned by the given user.
+	CreateNewCustomField(username string, customField *domain.CustomField) (*domain.CustomField, error)
+
+	// UpdateUserCustomField updates the given custom field owned by the given user. The type of a custom field
+	// cannot be changed.
+	UpdateUserCustomField(username string, customField *domain.CustomField) (*domain.CustomField, error)
+
+	// DeleteUserCustomField deletes the custom field with the given ID owned by the given user including all
+	// of its values.
+	DeleteUserCustomField(username string, id uint) error
+}
+
+type customFieldServiceImpl struct {
+	users         domain.Users
+	customFields  domain.CustomFields
+	documents     domain.Documents
+	documentIndex domain.DocumentIndex
+}
+
+// NewCustomFieldService creates a new custom field service.
+func NewCustomFieldService(
+	users domain.Users,
+	customFields domain.CustomFields,
+	documents domain.Documents,
+	documentIndex domain.DocumentIndex,
+) CustomFieldService {
+	return &customFieldServiceImpl{
+		users:         users,
+		customFields:  customFields,
+		documents:     documents,
+		documentIndex: documentIndex,
+	}
+}
+
+func (s *customFieldServiceImpl) GetUserCustomFields(
+	username string,
+	pr domain.PageRequest,
+) ([]domain.CustomField, int64, error) {
+	customFields, count, err := s.customFields.FindByUsername(domain.Name(username), pr)
+	if err != nil {
+		return nil, -1, errors.Wrap(err, "Failed to retrieve custom fields")
+	}
+
+	return customFields, int64(count), nil
+}
+
+func (s *customFieldServiceImpl) GetUserCustomFieldByID(username string, id uint) (*domain.CustomField, error) {
+	return s.expectUserCustomFieldExists(domain.Name(username), domain.CustomFieldID(id))
+}
+
+func (s *customFieldServiceImpl) GetUserCustomFieldsByIDs(username string, ids []uint) ([]domain.CustomField, error) {
+	return expectUserCustomFieldsExist(s.customFields, domain.Name(username), ids)
+}
+
+func (s *customFieldServiceImpl) CreateNewCustomField(
+	username string,
+	customField *domain.CustomField,
+) (*domain.CustomField, error) {
+	owner, err := s.users.GetByUsername(domain.Name(username))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.expectCustomFieldNameNotAlreadyTaken(owner.Username, customField.Name); err != nil {
+		return nil, err
+	}
+
+	customField.ID = 0
+	customField.Owner = owner
+
+	newCustomField, err := s.customFields.Add(customField)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create custom field")
+	}
+
+	return newCustomField, nil
+}
+
+func (s *customFieldServiceImpl) UpdateUserCustomField(
+	username string,
+	customField *domain.CustomField,
+) (*domain.CustomField, error) {
+	originalCustomField, err := s.expectUserCustomFieldExists(domain.Name(username), customField.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if customField.Type != originalCustomField.Type {
+		err := BadRequestError.Newf("Type of custom field %d cannot be changed", customField.ID)
+		return nil, errors.AddContext(err, "type", "immutable")
+	}
+
+	if customField.Name == originalCustomField.Name {
+		return originalCustomField, nil
+	}
+
+	if err := s.expectCustomFieldNameNotAlreadyTaken(domain.Name(username), customField.Name); err != nil {
+		return nil, err
+	}
+
+	originalCustomField.Name = customField.Name
+	customField, err = s.customFields.Update(originalCustomField)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to update custom field")
+	}
+
+	if err := s.indexUserDocuments(customField.Owner.Username); err != nil {
+		return nil, err
+	}
+
+	return customField, nil
+}
+
+func (s *customFieldServiceImpl) DeleteUserCustomField(username string, id uint) error {
+	customField, err := s.expectUserCustomFieldExists(domain.Name(username), domain.CustomFieldID(id))
+	if err != nil {
+		return err
+	}
+
+	if err := s.customFields.Delete(customField); err != nil {
+		return err
+	}
+
+	return s.indexUserDocuments(customField.Owner.Username)
+}
+
+/* Helper Methods */
+
+// indexUserDocuments updates the index entries of all documents owned by the
+// user with the given username. Custom fields are indexed by their names, so
+// renaming or deleting them affects all documents having a value for them.
+func (s *customFieldServiceImpl) indexUserDocuments(username domain.Name) error {
+	documentNumbers, err := findAllDocumentNumbers(s.documents, username, domain.DocumentFilter{})
+	if err != nil {
+		return err
+	}
+
+	return indexDocuments(s.documentIndex, documentNumbers)
+}
+
+func (s *customFieldServiceImpl) expectUserCustomFieldExists(
+	username domain.Name,
+	id domain.CustomFieldID,
+) (*domain.CustomField, error) {
+	customField, err := s.customFields.GetByID(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve custom field")
+	}
+
+	if customField == nil {
+		return nil, NotFoundError.Newf("Custom field %d does not exist", id)
+	}
+
+	if customField.Owner.Username != username {
+		return nil, ForbiddenError.Newf("Access to custom field %d not permitted", id)
+	}
+
+	return customField, nil
+}
+
+func (s *customFieldServiceImpl) expectCustomFieldNameNotAlreadyTaken(username domain.Name, name domain.Name) error {
+	customField, err := s.customFields.GetByUsernameAndName(username, name)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve custom field")
+	}
+
+	if customField != nil {
+		err := ConflictError.Newf("Custom field '%s' already exists", name)
+		return errors.AddContext(err, "name", "unique")
+	}
+
+	return nil
+}
+
+/* Helper Functions */
+
+// expectUserCustomFieldsExist returns the custom fields with the given IDs,
+// expecting all of them to exist and being owned by the user with the given
+// username.
+func expectUserCustomFieldsExist(
+	customFields domain.CustomFields,
+	username domain.Name,
+	customFieldIDs []uint,
+) ([]domain.CustomField, error) {
+	ids := make([]domain.CustomFieldID, len(customFieldIDs))
+	for i, customFieldID := range customFieldIDs {
+		ids[i] = domain.CustomFieldID(customFieldID)
+	}
+
+	existingCustomFields, err := customFields.FindByIDs(ids...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to retrieve custom fields")
+	}
+
+	existingIDs := make(map[domain.CustomFieldID]bool, len(existingCustomFields))
+	for _, customField := range existingCustomFields {
+		if customField.Owner.Username != username {
+			return nil, ForbiddenError.Newf("Access to custom field %d not permitted", customField.ID)
+		}
+
+		existingIDs[customField.ID] = true
+	}
+
+	for _, id := range ids {
+		if !existingIDs[id] {
+			err := NotFoundError.Newf("Custom field %d does not exist", id)
+			return nil, errors.AddContext(err, "customFields", "exists")
+		}
+	}
+
+	return existingCustomFields, nil
+}
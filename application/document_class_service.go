@@ -0,0 +1,189 @@
+package application
+
+import (
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+// DocumentClassService defines an application service for managing document class use-cases.
+type DocumentClassService interface {
+	// GetUserDocumentClasses returns the document classes owned by the given user with respect to the given
+	// page request.
+	GetUserDocumentClasses(username string, pr domain.PageRequest) ([]domain.DocumentClass, int64, error)
+
+	// GetUserDocumentClassByID returns the document class with the given ID owned by the given user.
+	GetUserDocumentClassByID(username string, id uint) (*domain.DocumentClass, error)
+
+	// CreateNewDocumentClass creates the given new document class owned by the given user.
+	CreateNewDocumentClass(username string, documentClass *domain.DocumentClass) (*domain.DocumentClass, error)
+
+	// UpdateUserDocumentClass updates the given document class owned by the given user.
+	UpdateUserDocumentClass(username string, documentClass *domain.DocumentClass) (*domain.DocumentClass, error)
+
+	// DeleteUserDocumentClass deletes the document class with the given ID owned by the given user, removing it
+	// from all documents.
+	DeleteUserDocumentClass(username string, id uint) error
+}
+
+type documentClassServiceImpl struct {
+	users           domain.Users
+	documentClasses domain.DocumentClasses
+	documents       domain.Documents
+	documentIndex   domain.DocumentIndex
+}
+
+// NewDocumentClassService creates a new document class service.
+func NewDocumentClassService(
+	users domain.Users,
+	documentClasses domain.DocumentClasses,
+	documents domain.Documents,
+	documentIndex domain.DocumentIndex,
+) DocumentClassService {
+	return &documentClassServiceImpl{
+		users:           users,
+		documentClasses: documentClasses,
+		documents:       documents,
+		documentIndex:   documentIndex,
+	}
+}
+
+func (s *documentClassServiceImpl) GetUserDocumentClasses(
+	username string,
+	pr domain.PageRequest,
+) ([]domain.DocumentClass, int64, error) {
+	documentClasses, count, err := s.documentClasses.FindByUsername(domain.Name(username), pr)
+	if err != nil {
+		return nil, -1, errors.Wrap(err, "Failed to retrieve document classes")
+	}
+
+	return documentClasses, int64(count), nil
+}
+
+func (s *documentClassServiceImpl) GetUserDocumentClassByID(username string, id uint) (*domain.DocumentClass, error) {
+	return s.expectUserDocumentClassExists(domain.Name(username), domain.DocumentClassID(id))
+}
+
+func (s *documentClassServiceImpl) CreateNewDocumentClass(
+	username string,
+	documentClass *domain.DocumentClass,
+) (*domain.DocumentClass, error) {
+	owner, err := s.users.GetByUsername(domain.Name(username))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.expectDocumentClassNameNotAlreadyTaken(owner.Username, documentClass.Name); err != nil {
+		return nil, err
+	}
+
+	documentClass.ID = 0
+	documentClass.Owner = owner
+
+	newDocumentClass, err := s.documentClasses.Add(documentClass)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create document class")
+	}
+
+	return newDocumentClass, nil
+}
+
+func (s *documentClassServiceImpl) UpdateUserDocumentClass(
+	username string,
+	documentClass *domain.DocumentClass,
+) (*domain.DocumentClass, error) {
+	originalDocumentClass, err := s.expectUserDocumentClassExists(domain.Name(username), documentClass.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if documentClass.Name == originalDocumentClass.Name {
+		return originalDocumentClass, nil
+	}
+
+	if err := s.expectDocumentClassNameNotAlreadyTaken(domain.Name(username), documentClass.Name); err != nil {
+		return nil, err
+	}
+
+	originalDocumentClass.Name = documentClass.Name
+	documentClass, err = s.documentClasses.Update(originalDocumentClass)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to update document class")
+	}
+
+	documentNumbers, err := s.getDocumentClassDocumentNumbers(documentClass)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := indexDocuments(s.documentIndex, documentNumbers); err != nil {
+		return nil, err
+	}
+
+	return documentClass, nil
+}
+
+func (s *documentClassServiceImpl) DeleteUserDocumentClass(username string, id uint) error {
+	documentClass, err := s.expectUserDocumentClassExists(domain.Name(username), domain.DocumentClassID(id))
+	if err != nil {
+		return err
+	}
+
+	documentNumbers, err := s.getDocumentClassDocumentNumbers(documentClass)
+	if err != nil {
+		return err
+	}
+
+	if err := s.documentClasses.Delete(documentClass); err != nil {
+		return err
+	}
+
+	return indexDocuments(s.documentIndex, documentNumbers)
+}
+
+/* Helper Methods */
+
+// getDocumentClassDocumentNumbers returns the document numbers of all
+// documents of the given document class.
+func (s *documentClassServiceImpl) getDocumentClassDocumentNumbers(
+	documentClass *domain.DocumentClass,
+) ([]domain.DocumentNumber, error) {
+	filter := domain.DocumentFilter{ClassID: &documentClass.ID}
+	return findAllDocumentNumbers(s.documents, documentClass.Owner.Username, filter)
+}
+
+func (s *documentClassServiceImpl) expectUserDocumentClassExists(
+	username domain.Name,
+	id domain.DocumentClassID,
+) (*domain.DocumentClass, error) {
+	documentClass, err := s.documentClasses.GetByID(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve document class")
+	}
+
+	if documentClass == nil {
+		return nil, NotFoundError.Newf("Document class %d does not exist", id)
+	}
+
+	if documentClass.Owner.Username != username {
+		return nil, ForbiddenError.Newf("Access to document class %d not permitted", id)
+	}
+
+	return documentClass, nil
+}
+
+func (s *documentClassServiceImpl) expectDocumentClassNameNotAlreadyTaken(
+	username domain.Name,
+	name domain.Name,
+) error {
+	documentClass, err := s.documentClasses.GetByUsernameAndName(username, name)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve document class")
+	}
+
+	if documentClass != nil {
+		err := ConflictError.Newf("Document class '%s' already exists", name)
+		return errors.AddContext(err, "name", "unique")
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package application
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/concepts-system/go-paperless/common"
+	"github.com/concepts-system/go-paperless/config"
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+const (
+	consumedDirectory = ".consumed"
+	failedDirectory   = ".failed"
+)
+
+// DocumentConsumer defines a component automatically ingesting all files
+// placed into a watched directory as new documents.
+type DocumentConsumer interface {
+	// Start starts watching the directory in background.
+	Start() error
+}
+
+// consumerFileState describes the state of a file in the watched directory
+// as seen on the last poll.
+type consumerFileState struct {
+	size    int64
+	modTime time.Time
+}
+
+type documentConsumerImpl struct {
+	logger          *logrus.Entry
+	config          *config.StorageConfiguration
+	documentService DocumentService
+	pendingFiles    map[string]consumerFileState
+}
+
+// NewDocumentConsumer returns a new document consumer polling the configured
+// consume path and adding each new file as a document owned by the
+// configured user.
+func NewDocumentConsumer(config *config.Configuration, documentService DocumentService) DocumentConsumer {
+	return &documentConsumerImpl{
+		logger:          common.NewLogger("consumer"),
+		config:          &config.Storage,
+		documentService: documentService,
+		pendingFiles:    make(map[string]consumerFileState),
+	}
+}
+
+func (c *documentConsumerImpl) Start() error {
+	if c.config.ConsumeUsername == "" {
+		return errors.New("No user configured for consumed documents")
+	}
+
+	if c.config.ConsumeInterval <= 0 {
+		return errors.New("Consume interval has to be positive")
+	}
+
+	for _, directory := range []string{consumedDirectory, failedDirectory} {
+		if err := os.MkdirAll(filepath.Join(c.config.ConsumePath, directory), os.ModePerm); err != nil {
+			return errors.Wrapf(err, "Failed to setup consume directory")
+		}
+	}
+
+	c.logger.Infof("Consuming files from '%s' for user '%s'", c.config.ConsumePath, c.config.ConsumeUsername)
+	go func() {
+		for range time.Tick(c.config.ConsumeInterval) {
+			c.poll()
+		}
+	}()
+
+	return nil
+}
+
+/* Helper Methods */
+
+// poll consumes all files which did not change since the previous poll.
+// Files still being written are therefore ignored until they are stable.
+func (c *documentConsumerImpl) poll() {
+	files, err := ioutil.ReadDir(c.config.ConsumePath)
+	if err != nil {
+		c.logger.Errorf("Failed to read consume directory: %v", err)
+		return
+	}
+
+	present := make(map[string]bool, len(files))
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		present[file.Name()] = true
+		state := consumerFileState{file.Size(), file.ModTime()}
+		if previousState, ok := c.pendingFiles[file.Name()]; !ok || previousState != state {
+			c.pendingFiles[file.Name()] = state
+			continue
+		}
+
+		delete(c.pendingFiles, file.Name())
+		c.consume(file.Name())
+	}
+
+	for fileName := range c.pendingFiles {
+		if !present[fileName] {
+			delete(c.pendingFiles, fileName)
+		}
+	}
+}
+
+func (c *documentConsumerImpl) consume(fileName string) {
+	path := filepath.Join(c.config.ConsumePath, fileName)
+	c.logger.Infof("Consuming file '%s'", fileName)
+
+	if err := c.addDocument(path); err != nil {
+		c.logger.Errorf("Failed to consume file '%s': %v", fileName, err)
+		c.moveFile(path, failedDirectory)
+		return
+	}
+
+	if c.config.ConsumeKeepFiles {
+		c.moveFile(path, consumedDirectory)
+	} else if err := os.Remove(path); err != nil {
+		c.logger.Errorf("Failed to delete consumed file '%s': %v", fileName, err)
+	}
+}
+
+// addDocument adds the file at the given path as a new document. The document
+// is removed again in case no pages could be added.
+func (c *documentConsumerImpl) addDocument(path string) error {
+	upload, err := NewFileUpload(path)
+	if err != nil {
+		return err
+	}
+
+	username := c.config.ConsumeUsername
+	title := strings.TrimSuffix(upload.FileName, filepath.Ext(upload.FileName))
+	document, err := c.documentService.CreateNewDocument(username, &domain.Document{Title: domain.Text(title)})
+	if err != nil {
+		return err
+	}
+
+	documentNumber := uint(document.DocumentNumber)
+	pages, err := c.documentService.AddPagesToUserDocument(username, documentNumber, []Upload{upload})
+	if err == nil && len(pages) == 0 {
+		err = BadRequestError.Newf("File type '%s' is not supported", upload.ContentType)
+	}
+
+	if err != nil {
+		if err := c.documentService.DeleteUserDocument(username, documentNumber); err != nil {
+			c.logger.Errorf("Failed to delete document %d: %v", documentNumber, err)
+		} else if err := c.documentService.PurgeUserDocument(username, documentNumber); err != nil {
+			c.logger.Errorf("Failed to purge document %d: %v", documentNumber, err)
+		}
+
+		return err
+	}
+
+	c.logger.Infof("Added file '%s' as document %d", upload.FileName, documentNumber)
+	return nil
+}
+
+// moveFile moves the file at the given path into the given subdirectory of
+// the consume directory, prefixing it with the current time for uniqueness.
+func (c *documentConsumerImpl) moveFile(path string, directory string) {
+	fileName := fmt.Sprintf("%s-%s", time.Now().Format("20060102150405"), filepath.Base(path))
+	destination := filepath.Join(c.config.ConsumePath, directory, fileName)
+
+	if err := os.Rename(path, destination); err != nil {
+		c.logger.Errorf("Failed to move file '%s' to '%s': %v", path, destination, err)
+	}
+}
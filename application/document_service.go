@@ -1,9 +1,13 @@
 package application
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"io"
-	"mime/multipart"
 	"regexp"
+	"sort"
+	"time"
 
 	"github.com/concepts-system/go-paperless/domain"
 	"github.com/concepts-system/go-paperless/errors"
@@ -12,22 +16,28 @@ import (
 
 const (
 	mimeHeaderKeyContentType = "Content-Type"
+	contentTypePDF           = "application/pdf"
+	contentTypeTIFF          = "image/tiff"
+	documentBatchSize        = 100
 )
 
-var (
-	validContentTypes = regexp.MustCompile("^image/(bmp|gif|jpeg|png|tiff)$")
-	// validHighlightTypes = regexp.MustCompile("^html$")
-)
+var validContentTypes = regexp.MustCompile("^image/(bmp|gif|jpeg|png|tiff)$")
 
 // DocumentService defines an application service for managing document-related
 // use cases.
 type DocumentService interface {
-	// GetUserDocuments returns the given user's documents with respect to the
-	// given page request.
-	GetUserDocuments(username string, pr domain.PageRequest) ([]domain.Document, int64, error)
+	// GetUserDocuments returns the given user's documents matching the given
+	// filter with respect to the given page request.
+	GetUserDocuments(username string, filter domain.DocumentFilter, pr domain.PageRequest) ([]domain.Document, int64, error)
 
-	// SearchUserDocuments returns all documents matching the given query with respect to the given page request.
-	SearchUserDocuments(username, query string, pr domain.PageRequest) ([]domain.DocumentSearchResult, int64, error)
+	// SearchUserDocuments returns the given user's documents matching the given search request, alongside with
+	// the requested facets.
+	SearchUserDocuments(username string, request domain.DocumentSearchRequest) (*domain.DocumentSearchResponse, error)
+
+	// SuggestUserSearchTerms returns at most the given number of completions for the given partially typed query
+	// based on the given user's documents, as well as a corrected query if the given one does not match any of
+	// them.
+	SuggestUserSearchTerms(username, query string, size int) (*domain.DocumentSearchSuggestions, error)
 
 	// GetUserDocumentByDocumentNumber returns the document with the given document number owned by the given user.
 	GetUserDocumentByDocumentNumber(username string, documentNumber uint) (*domain.Document, error)
@@ -35,6 +45,68 @@ type DocumentService interface {
 	// CreateNewDocument creates the given new document owned by the user with the given username.
 	CreateNewDocument(username string, document *domain.Document) (*domain.Document, error)
 
+	// UpdateUserDocument updates all editable fields of the given document owned by the given user.
+	UpdateUserDocument(username string, document *domain.Document) (*domain.Document, error)
+
+	// DeleteUserDocument moves the document with the given document number owned by the given user
+	// to the trash, from where it may be restored until it gets purged.
+	DeleteUserDocument(username string, documentNumber uint) error
+
+	// SetUserDocumentTags replaces the tags assigned to the document with the given document number owned by
+	// the given user by the tags with the given IDs.
+	SetUserDocumentTags(username string, documentNumber uint, tagIDs []uint) (*domain.Document, error)
+
+	// SetUserDocumentCustomFields replaces the custom field values of the document with the given document
+	// number owned by the given user by the given values.
+	SetUserDocumentCustomFields(
+		username string,
+		documentNumber uint,
+		values []domain.CustomFieldValue,
+	) (*domain.Document, error)
+
+	// GetUserTrashedDocuments returns the given user's documents in the trash with respect to the
+	// given page request.
+	GetUserTrashedDocuments(username string, pr domain.PageRequest) ([]domain.Document, int64, error)
+
+	// RestoreUserDocument restores the document with the given document number owned by the given
+	// user from the trash.
+	RestoreUserDocument(username string, documentNumber uint) (*domain.Document, error)
+
+	// PurgeUserDocument permanently deletes the document in the trash with the given document number
+	// owned by the given user, including all its pages and stored content.
+	PurgeUserDocument(username string, documentNumber uint) error
+
+	// PurgeTrashedDocuments permanently deletes all documents which have been moved to the trash
+	// before the given point in time and returns the number of purged documents.
+	PurgeTrashedDocuments(deletedBefore time.Time) (int, error)
+
+	// ComputeMissingPerceptualHashes computes the perceptual hashes of all preprocessed pages lacking
+	// one, e.g. pages preprocessed before perceptual hashes have been introduced, and returns the
+	// number of updated pages.
+	ComputeMissingPerceptualHashes() (int, error)
+
+	// ComputeMissingOriginalFingerprints replaces the random fingerprints of all originals uploaded
+	// before content fingerprints have been introduced by the fingerprint of their content, so they
+	// are recognized as duplicates of further uploads, and returns the number of updated originals.
+	ComputeMissingOriginalFingerprints() (int, error)
+
+	// ExportUserDocuments returns a reader streaming an export of the documents with the given
+	// document numbers owned by the given user. All of the user's documents are exported in case
+	// no document numbers are given.
+	ExportUserDocuments(username string, documentNumbers []uint) (io.ReadCloser, error)
+
+	// GetUserDuplicatePages returns all sets of pages sharing the same content within the documents
+	// owned by the given user. Pages are compared by the fingerprint of their preprocessed content,
+	// regardless of when they have been uploaded; pages awaiting preprocessing are not compared yet.
+	GetUserDuplicatePages(username string) ([]domain.PageDuplicates, error)
+
+	// GetUserSimilarPages returns all pairs of pages and documents looking alike within the documents owned by
+	// the given user, having a similarity of at least the given threshold.
+	GetUserSimilarPages(username string, threshold float64) ([]domain.SimilarPages, []domain.SimilarDocuments, error)
+
+	// GetUserDocumentContent returns a reader to a document's generated content, if present.
+	GetUserDocumentContent(username string, documentNumber uint) (io.ReadCloser, error)
+
 	// GetUserDocumentPagesByDocumentNumber returns the document pages for the document with the given document number with respect to the given
 	// username and page request.
 	GetUserDocumentPagesByDocumentNumber(username string, documentNumber uint, pr domain.PageRequest) ([]domain.DocumentPage, int64, error)
@@ -44,42 +116,134 @@ type DocumentService interface {
 	GetUserDocumentPageByDocumentNumberAndPageNumber(username string, documentNumber uint, pageNumber uint) (*domain.DocumentPage, error)
 
 	// AddPagesToUserDocument adds the given pages to the document with the given ID.
-	AddPagesToUserDocument(username string, documentNumber uint, files []*multipart.FileHeader) ([]domain.DocumentPage, error)
+	// PDF files are rasterized and multi-page TIFF files are split, adding one page per contained page.
+	// Files whose content matches an original already uploaded by the user are rejected as conflict.
+	// Pages added before originals have been kept cannot be matched, as their uploaded file is unknown.
+	AddPagesToUserDocument(username string, documentNumber uint, files []Upload) ([]domain.DocumentPage, error)
+
+	// ReplaceUserDocumentPageContent replaces the content of the page with the given page number
+	// of the document with the given document number by the given file.
+	ReplaceUserDocumentPageContent(
+		username string,
+		documentNumber uint,
+		pageNumber uint,
+		file Upload,
+	) (*domain.DocumentPage, error)
+
+	// ReorderUserDocumentPages renumbers the pages of the document with the given document number
+	// according to the given page numbers, listing all current page numbers in their desired new order.
+	ReorderUserDocumentPages(username string, documentNumber uint, pageNumbers []uint) ([]domain.DocumentPage, error)
+
+	// RotateUserDocumentPage rotates the page with the given page number of the document with the given
+	// document number clockwise by the given number of degrees.
+	RotateUserDocumentPage(username string, documentNumber uint, pageNumber uint, degrees int) (*domain.DocumentPage, error)
+
+	// MoveUserDocumentPages moves the pages with the given page numbers of the document with the given
+	// document number to the end of the target document. Both documents have to be owned by the given user.
+	MoveUserDocumentPages(
+		username string,
+		documentNumber uint,
+		pageNumbers []uint,
+		targetDocumentNumber uint,
+	) ([]domain.DocumentPage, error)
+
+	// SplitUserDocument splits the document with the given document number at the given page number.
+	// The page and all subsequent pages are moved to a new document, which is returned.
+	SplitUserDocument(username string, documentNumber uint, pageNumber uint) (*domain.Document, error)
+
+	// MergeUserDocuments appends the pages of all source documents to the document with the given
+	// document number in the given order. The document additionally receives the tags of all source
+	// documents and the values of custom fields it has no value for yet. The then empty source
+	// documents are moved to the trash afterwards, so they may still be restored.
+	MergeUserDocuments(username string, documentNumber uint, sourceDocumentNumbers []uint) (*domain.Document, error)
+
+	// DeleteUserDocumentPage deletes the page with the given page number from the document with the given
+	// document number, accessible by the user with the given username.
+	DeleteUserDocumentPage(username string, documentNumber uint, pageNumber uint) error
 
 	// GetUserDocumentPageContent returns a reader to a document pages content, if present.
 	GetUserDocumentPageContent(username string, documentNumber uint, pageNumber uint) (io.ReadCloser, error)
+
+	// GetUserDocumentPageOriginal returns the original a document page originates from alongside a reader
+	// to the original's content, exactly as it has been uploaded.
+	GetUserDocumentPageOriginal(
+		username string,
+		documentNumber uint,
+		pageNumber uint,
+	) (*domain.PageOriginal, io.ReadCloser, error)
+
+	// GetUserDocumentPageRendition returns a reader to a scaled rendition of a document page's content
+	// in the given format. A width of zero retains the page's original width.
+	GetUserDocumentPageRendition(
+		username string,
+		documentNumber uint,
+		pageNumber uint,
+		width int,
+		format domain.RenditionFormat,
+	) (io.ReadCloser, error)
 }
 
 type documentServiceImpl struct {
-	users            domain.Users
-	documents        domain.Documents
-	documentArchive  domain.DocumentArchive
-	documentIndex    domain.DocumentIndex
-	documentRegistry domain.DocumentRegistry
+	users                   domain.Users
+	tags                    domain.Tags
+	documentClasses         domain.DocumentClasses
+	correspondents          domain.Correspondents
+	customFields            domain.CustomFields
+	documents               domain.Documents
+	documentArchive         domain.DocumentArchive
+	documentIndex           domain.DocumentIndex
+	documentRegistry        domain.DocumentRegistry
+	documentPreprocessor    domain.DocumentPreprocessor
+	documentPageTransformer domain.DocumentPageTransformer
+	documentRasterizer      domain.DocumentRasterizer
+	documentPageSplitter    domain.DocumentPageSplitter
+	documentPageRenderer    domain.DocumentPageRenderer
+	documentExporter        domain.DocumentExporter
 }
 
 // NewDocumentService creates a new document service.
 func NewDocumentService(
 	users domain.Users,
+	tags domain.Tags,
+	documentClasses domain.DocumentClasses,
+	correspondents domain.Correspondents,
+	customFields domain.CustomFields,
 	documents domain.Documents,
 	documentArchive domain.DocumentArchive,
 	documentIndex domain.DocumentIndex,
 	documentRegistry domain.DocumentRegistry,
+	documentPreprocessor domain.DocumentPreprocessor,
+	documentPageTransformer domain.DocumentPageTransformer,
+	documentRasterizer domain.DocumentRasterizer,
+	documentPageSplitter domain.DocumentPageSplitter,
+	documentPageRenderer domain.DocumentPageRenderer,
+	documentExporter domain.DocumentExporter,
 ) DocumentService {
 	return &documentServiceImpl{
-		users:            users,
-		documents:        documents,
-		documentArchive:  documentArchive,
-		documentIndex:    documentIndex,
-		documentRegistry: documentRegistry,
+		users:                   users,
+		tags:                    tags,
+		documentClasses:         documentClasses,
+		correspondents:          correspondents,
+		customFields:            customFields,
+		documents:               documents,
+		documentArchive:         documentArchive,
+		documentIndex:           documentIndex,
+		documentRegistry:        documentRegistry,
+		documentPreprocessor:    documentPreprocessor,
+		documentPageTransformer: documentPageTransformer,
+		documentRasterizer:      documentRasterizer,
+		documentPageSplitter:    documentPageSplitter,
+		documentPageRenderer:    documentPageRenderer,
+		documentExporter:        documentExporter,
 	}
 }
 
 func (s *documentServiceImpl) GetUserDocuments(
 	username string,
+	filter domain.DocumentFilter,
 	pr domain.PageRequest,
 ) ([]domain.Document, int64, error) {
-	documents, count, err := s.documents.FindByUsername(domain.Name(username), pr)
+	documents, count, err := s.documents.FindByUsername(domain.Name(username), filter, pr)
 
 	if err != nil {
 		return nil, -1, errors.Wrap(err, "Failed to retreive documents")
@@ -90,15 +254,26 @@ func (s *documentServiceImpl) GetUserDocuments(
 
 func (s *documentServiceImpl) SearchUserDocuments(
 	username string,
-	query string,
-	pr domain.PageRequest,
-) ([]domain.DocumentSearchResult, int64, error) {
-	results, totalCount, err := s.documentIndex.Search(query, pr)
+	request domain.DocumentSearchRequest,
+) (*domain.DocumentSearchResponse, error) {
+	response, err := s.documentIndex.Search(domain.DocumentAccess{Owner: domain.Name(username)}, request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to search documents")
+	}
+
+	return response, nil
+}
+
+func (s *documentServiceImpl) SuggestUserSearchTerms(
+	username, query string,
+	size int,
+) (*domain.DocumentSearchSuggestions, error) {
+	suggestions, err := s.documentIndex.Suggest(domain.Name(username), query, size)
 	if err != nil {
-		return nil, -1, errors.Wrapf(err, "Failed to search documents")
+		return nil, errors.Wrapf(err, "Failed to suggest search terms")
 	}
 
-	return results, int64(totalCount), nil
+	return suggestions, nil
 }
 
 func (s *documentServiceImpl) GetUserDocumentByDocumentNumber(
@@ -119,7 +294,19 @@ func (s *documentServiceImpl) CreateNewDocument(username string, document *domai
 		return nil, err
 	}
 
+	documentClass, err := s.expectUserDocumentClassExists(owner.Username, document.Class)
+	if err != nil {
+		return nil, err
+	}
+
+	correspondent, err := s.expectUserCorrespondentExists(owner.Username, document.Correspondent)
+	if err != nil {
+		return nil, err
+	}
+
 	document.Owner = owner
+	document.Class = documentClass
+	document.Correspondent = correspondent
 	document.State = domain.DocumentStateEmpty
 	document.Type = ""
 	document.Fingerprint = ""
@@ -134,198 +321,1685 @@ func (s *documentServiceImpl) CreateNewDocument(username string, document *domai
 	return newDocument, nil
 }
 
-func (s *documentServiceImpl) GetUserDocumentPagesByDocumentNumber(
+func (s *documentServiceImpl) UpdateUserDocument(
 	username string,
-	documentNumber uint,
-	pr domain.PageRequest,
-) ([]domain.DocumentPage, int64, error) {
-	_, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+	document *domain.Document,
+) (*domain.Document, error) {
+	originalDocument, err := s.expectUserDocumentExists(domain.Name(username), document.DocumentNumber)
 	if err != nil {
-		return nil, -1, err
+		return nil, err
 	}
 
-	pages, totalCount, err := s.documents.GetPagesByDocumentNumber(domain.DocumentNumber(documentNumber), pr)
+	documentClass, err := s.expectUserDocumentClassExists(domain.Name(username), document.Class)
 	if err != nil {
-		return nil, -1, err
+		return nil, err
 	}
 
-	return pages, int64(totalCount), nil
+	correspondent, err := s.expectUserCorrespondentExists(domain.Name(username), document.Correspondent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only write the edited metadata, as the registry may change the
+	// document's state concurrently.
+	originalDocument.Title = document.Title
+	originalDocument.Date = document.Date
+	originalDocument.Class = documentClass
+	originalDocument.Correspondent = correspondent
+
+	if err := s.documents.UpdateMetadata(originalDocument); err != nil {
+		return nil, errors.Wrap(err, "Failed to update document")
+	}
+
+	if err := s.documentIndex.IndexDocument(originalDocument.DocumentNumber); err != nil {
+		return nil, errors.Wrap(err, "Failed to index document")
+	}
+
+	return s.expectDocumentWithDocumentNumberExists(originalDocument.DocumentNumber)
 }
 
-func (s *documentServiceImpl) GetUserDocumentPageByDocumentNumberAndPageNumber(
+func (s *documentServiceImpl) DeleteUserDocument(username string, documentNumber uint) error {
+	document, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+	if err != nil {
+		return err
+	}
+
+	return s.trashDocument(document)
+}
+
+func (s *documentServiceImpl) SetUserDocumentTags(
 	username string,
 	documentNumber uint,
-	pageNumber uint,
-) (*domain.DocumentPage, error) {
-	_, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+	tagIDs []uint,
+) (*domain.Document, error) {
+	document, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
 	if err != nil {
 		return nil, err
 	}
 
-	page, err := s.documents.GetPageByDocumentNumberAndPageNumber(domain.DocumentNumber(documentNumber), domain.PageNumber(pageNumber))
+	tags, err := s.expectUserTagsExist(domain.Name(username), tagIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	if page == nil {
-		return nil, NotFoundError.Newf("Page %d of document %d does not exist", pageNumber, documentNumber)
+	document.Tags = tags
+	if err := s.documents.UpdateTags(document); err != nil {
+		return nil, err
 	}
 
-	return page, nil
+	if err := s.documentIndex.IndexDocument(document.DocumentNumber); err != nil {
+		return nil, errors.Wrap(err, "Failed to index document")
+	}
+
+	return s.expectDocumentWithDocumentNumberExists(document.DocumentNumber)
 }
 
-func (s *documentServiceImpl) AddPagesToUserDocument(
+func (s *documentServiceImpl) SetUserDocumentCustomFields(
 	username string,
 	documentNumber uint,
-	files []*multipart.FileHeader,
-) ([]domain.DocumentPage, error) {
-	document, err := s.expectUserDocumentExists(
-		domain.Name(username),
-		domain.DocumentNumber(documentNumber),
-	)
+	values []domain.CustomFieldValue,
+) (*domain.Document, error) {
+	document, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
 	if err != nil {
 		return nil, err
 	}
 
-	pages := make([]domain.DocumentPage, 0)
-	i := 0
-	for _, file := range files {
-		pageType, err := s.validatePageType(file)
-		if err != nil {
-			continue
-		}
+	customFields, err := s.expectUserCustomFieldValuesValid(domain.Name(username), values)
+	if err != nil {
+		return nil, err
+	}
 
-		fileContent, err := file.Open()
-		if err != nil {
-			return nil, errors.Wrapf(err, "Failed to process file")
+	document.CustomFields = make([]domain.CustomFieldValue, len(values))
+	for i, value := range values {
+		document.CustomFields[i] = domain.CustomFieldValue{
+			Field: customFields[value.Field.ID],
+			Value: value.Value,
 		}
+	}
 
-		page := &domain.DocumentPage{
-			PageNumber:  domain.PageNumber(len(document.Pages) + i + 1),
-			State:       domain.PageStateEdited,
-			Type:        pageType,
-			Fingerprint: domain.Fingerprint(uuid.New().String()),
-		}
+	if err := s.documents.UpdateCustomFields(document); err != nil {
+		return nil, err
+	}
 
-		err = s.documentArchive.StoreContent(
-			domain.DocumentNumber(documentNumber),
-			page.ContentKey(),
-			fileContent,
-		)
-		if err != nil {
-			return nil, err
-		}
+	if err := s.documentIndex.IndexDocument(document.DocumentNumber); err != nil {
+		return nil, errors.Wrap(err, "Failed to index document")
+	}
 
-		page, err = s.documents.AddPage(domain.DocumentNumber(documentNumber), page)
-		if err != nil {
-			return nil, err
-		}
+	return s.expectDocumentWithDocumentNumberExists(document.DocumentNumber)
+}
 
-		pages = append(pages, *page)
-		i++
+func (s *documentServiceImpl) GetUserTrashedDocuments(
+	username string,
+	pr domain.PageRequest,
+) ([]domain.Document, int64, error) {
+	documents, count, err := s.documents.FindTrashedByUsername(domain.Name(username), pr)
+
+	if err != nil {
+		return nil, -1, errors.Wrap(err, "Failed to retreive trashed documents")
 	}
 
-	document.State = domain.DocumentStateEdited
-	if _, err := s.documents.Update(document); err != nil {
+	return documents, int64(count), nil
+}
+
+func (s *documentServiceImpl) RestoreUserDocument(username string, documentNumber uint) (*domain.Document, error) {
+	document, err := s.expectUserTrashedDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+	if err != nil {
 		return nil, err
 	}
 
-	s.documentRegistry.Review(domain.DocumentNumber(documentNumber))
-	return pages, nil
+	if err := s.documents.Restore(document); err != nil {
+		return nil, err
+	}
+
+	if err := s.documentIndex.IndexDocument(document.DocumentNumber); err != nil {
+		return nil, errors.Wrap(err, "Failed to index document")
+	}
+
+	// Reviews are aborted for trashed documents, so continue where they left off.
+	s.documentRegistry.Review(document.DocumentNumber)
+	return s.expectDocumentWithDocumentNumberExists(document.DocumentNumber)
 }
 
-func (s *documentServiceImpl) GetUserDocumentPageContent(
-	username string,
-	documentNumber uint,
-	pageNumber uint,
-) (io.ReadCloser, error) {
-	_, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+func (s *documentServiceImpl) PurgeUserDocument(username string, documentNumber uint) error {
+	document, err := s.expectUserTrashedDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	page, err := s.documents.GetPageByDocumentNumberAndPageNumber(
-		domain.DocumentNumber(documentNumber),
-		domain.PageNumber(pageNumber),
-	)
+	return s.purgeDocument(document)
+}
 
+func (s *documentServiceImpl) PurgeTrashedDocuments(deletedBefore time.Time) (int, error) {
+	documents, err := s.documents.FindTrashedBefore(deletedBefore)
 	if err != nil {
-		return nil, err
+		return 0, errors.Wrap(err, "Failed to retreive trashed documents")
 	}
 
-	if page.State == domain.PageStateEdited {
-		return nil, NotFoundError.Newf(
-			"Page '%d' for document '%d' has no content available until preprocessing finished",
-			pageNumber,
-			documentNumber,
-		)
+	for i, document := range documents {
+		if err := s.purgeDocument(&document); err != nil {
+			return i, err
+		}
 	}
 
-	return s.documentArchive.ReadContent(page.Document.DocumentNumber, page.ContentKey())
+	return len(documents), nil
 }
 
-/* Helper Methods */
-
-func (s *documentServiceImpl) expectUserDocumentExists(
-	username domain.Name,
-	documentNumber domain.DocumentNumber,
-) (*domain.Document, error) {
-	document, err := s.expectDocumentWithDocumentNumberExists(documentNumber)
+func (s *documentServiceImpl) ComputeMissingPerceptualHashes() (int, error) {
+	pages, err := s.documents.FindPagesWithoutPerceptualHash()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	if err = s.expectUserMayAccessDocument(username, document); err != nil {
-		return nil, err
+	// Pages whose content cannot be read do not keep others from being updated.
+	count := 0
+	var pageErr error
+	for _, page := range pages {
+		if page.Document == nil {
+			continue
+		}
+
+		perceptualHash, err := s.documentPreprocessor.ComputePerceptualHash(page.Document.DocumentNumber, &page)
+		if err != nil {
+			if pageErr == nil {
+				pageErr = errors.Wrapf(
+					err,
+					"Failed to compute perceptual hash of page %d of document %d",
+					page.PageNumber,
+					page.Document.DocumentNumber,
+				)
+			}
+
+			continue
+		}
+
+		if err := s.documents.UpdatePagePerceptualHash(page.Document.DocumentNumber, &page, perceptualHash); err != nil {
+			return count, err
+		}
+
+		count++
 	}
 
-	return document, nil
+	return count, pageErr
 }
 
-func (s *documentServiceImpl) expectDocumentWithDocumentNumberExists(
-	documentNumber domain.DocumentNumber,
-) (*domain.Document, error) {
-	document, err := s.documents.GetByDocumentNumber(documentNumber)
+func (s *documentServiceImpl) ComputeMissingOriginalFingerprints() (int, error) {
+	pages, err := s.documents.FindPagesWithLegacyOriginalFingerprint()
 	if err != nil {
-		return nil, errors.Wrapf(err, "Failed to retrieve document")
+		return 0, err
 	}
 
-	if document == nil {
-		return nil, NotFoundError.Newf("Document %d does not exist", documentNumber)
+	// Originals whose content cannot be read do not keep others from being updated.
+	count := 0
+	var originalErr error
+	updated := make(map[domain.ContentKey]bool)
+	for _, page := range pages {
+		if page.Document == nil || page.Original == nil {
+			continue
+		}
+
+		documentNumber := page.Document.DocumentNumber
+		original := page.Original
+		key := domain.ContentKey(fmt.Sprintf("%d/%s", documentNumber, original.ContentKey()))
+		if updated[key] {
+			continue
+		}
+
+		updated[key] = true
+		fingerprint, err := s.fingerprintOriginal(documentNumber, original)
+		if err != nil {
+			if originalErr == nil {
+				originalErr = errors.Wrapf(
+					err,
+					"Failed to compute fingerprint of original '%s' of document %d",
+					original.FileName,
+					documentNumber,
+				)
+			}
+
+			continue
+		}
+
+		oldContentKey := original.ContentKey()
+		newOriginal := domain.PageOriginal{Fingerprint: fingerprint}
+		if err := s.documentArchive.MoveContent(documentNumber, oldContentKey, newOriginal.ContentKey()); err != nil {
+			return count, err
+		}
+
+		err = s.documents.UpdateOriginalFingerprint(documentNumber, original.Fingerprint, fingerprint)
+		if err != nil {
+			if moveErr := s.documentArchive.MoveContent(documentNumber, newOriginal.ContentKey(), oldContentKey); moveErr != nil {
+				return count, errors.Wrapf(
+					moveErr,
+					"Failed to restore original '%s' of document %d after failing to update it",
+					original.FileName,
+					documentNumber,
+				)
+			}
+
+			return count, err
+		}
+
+		count++
 	}
 
-	return document, nil
+	return count, originalErr
 }
 
-func (s *documentServiceImpl) expectUserMayAccessDocument(username domain.Name, document *domain.Document) error {
-	mayAccess, err := s.userMayAccessDocument(string(username), document)
+func (s *documentServiceImpl) ExportUserDocuments(username string, documentNumbers []uint) (io.ReadCloser, error) {
+	var documents []domain.Document
+
+	if len(documentNumbers) == 0 {
+		userDocuments, err := s.getAllUserDocuments(domain.Name(username))
+		if err != nil {
+			return nil, err
+		}
+
+		documents = userDocuments
+	} else {
+		documents = make([]domain.Document, 0, len(documentNumbers))
+		exported := make(map[uint]bool, len(documentNumbers))
+		for _, documentNumber := range documentNumbers {
+			if exported[documentNumber] {
+				continue
+			}
+
+			document, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+			if err != nil {
+				return nil, err
+			}
+
+			exported[documentNumber] = true
+			documents = append(documents, *document)
+		}
+	}
+
+	return s.documentExporter.Export(documents)
+}
+
+func (s *documentServiceImpl) GetUserDuplicatePages(username string) ([]domain.PageDuplicates, error) {
+	duplicates, err := s.documents.FindDuplicatePagesByUsername(domain.Name(username))
 	if err != nil {
-		return err
+		return nil, errors.Wrap(err, "Failed to retrieve duplicate pages")
 	}
 
-	if !mayAccess {
-		return ForbiddenError.Newf("Access to document %d not permitted", document.DocumentNumber)
+	return duplicates, nil
+}
+
+func (s *documentServiceImpl) GetUserSimilarPages(
+	username string,
+	threshold float64,
+) ([]domain.SimilarPages, []domain.SimilarDocuments, error) {
+	pages, err := s.documents.FindPagesWithPerceptualHashByUsername(domain.Name(username))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to retrieve similar pages")
 	}
 
-	return nil
+	pageCounts := make(map[domain.DocumentNumber]int)
+	for _, page := range pages {
+		if page.Document != nil {
+			pageCounts[page.Document.DocumentNumber]++
+		}
+	}
+
+	similarPages := domain.FindSimilarPages(pages, threshold)
+	return similarPages, domain.FindSimilarDocuments(similarPages, pageCounts), nil
 }
 
-func (s *documentServiceImpl) userMayAccessDocument(
+func (s *documentServiceImpl) GetUserDocumentContent(
 	username string,
-	document *domain.Document,
-) (bool, error) {
-	return document.Owner.Username == domain.Name(username), nil
+	documentNumber uint,
+) (io.ReadCloser, error) {
+	document, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	if document.Fingerprint == "" || document.Type == "" {
+		return nil, NotFoundError.Newf(
+			"Document '%d' has no content available until processing finished",
+			documentNumber,
+		)
+	}
+
+	return s.documentArchive.ReadContent(document.DocumentNumber, document.ContentKey())
 }
 
-func (s *documentServiceImpl) validatePageType(file *multipart.FileHeader) (domain.PageType, error) {
-	if file == nil {
-		return domain.PageTypeUnknown, errors.New("file may not be null")
+func (s *documentServiceImpl) GetUserDocumentPagesByDocumentNumber(
+	username string,
+	documentNumber uint,
+	pr domain.PageRequest,
+) ([]domain.DocumentPage, int64, error) {
+	_, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+	if err != nil {
+		return nil, -1, err
 	}
 
-	contentType := file.Header.Get(mimeHeaderKeyContentType)
-	if !validContentTypes.MatchString(contentType) {
-		return domain.PageTypeUnknown, BadRequestError.Newf("Page type '%s' is not supported", contentType)
+	pages, totalCount, err := s.documents.GetPagesByDocumentNumber(domain.DocumentNumber(documentNumber), pr)
+	if err != nil {
+		return nil, -1, err
 	}
 
-	return domain.PageTypeUnknown, nil
+	return pages, int64(totalCount), nil
+}
+
+func (s *documentServiceImpl) GetUserDocumentPageByDocumentNumberAndPageNumber(
+	username string,
+	documentNumber uint,
+	pageNumber uint,
+) (*domain.DocumentPage, error) {
+	_, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.documents.GetPageByDocumentNumberAndPageNumber(domain.DocumentNumber(documentNumber), domain.PageNumber(pageNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	if page == nil {
+		return nil, NotFoundError.Newf("Page %d of document %d does not exist", pageNumber, documentNumber)
+	}
+
+	return page, nil
+}
+
+func (s *documentServiceImpl) AddPagesToUserDocument(
+	username string,
+	documentNumber uint,
+	files []Upload,
+) ([]domain.DocumentPage, error) {
+	document, err := s.expectUserDocumentExists(
+		domain.Name(username),
+		domain.DocumentNumber(documentNumber),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprints, err := s.fingerprintUploads(domain.Name(username), files)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pages of files added before a file failed are kept, so they need to be
+	// reviewed as well.
+	pages, addErr := s.addUploadedPages(document, files, fingerprints)
+	if addErr != nil && len(pages) == 0 {
+		return nil, addErr
+	}
+
+	document.State = domain.DocumentStateEdited
+	if _, err := s.documents.Update(document); err != nil {
+		return nil, err
+	}
+
+	s.documentRegistry.Review(domain.DocumentNumber(documentNumber))
+	if addErr != nil {
+		return nil, addErr
+	}
+
+	return pages, nil
+}
+
+func (s *documentServiceImpl) ReplaceUserDocumentPageContent(
+	username string,
+	documentNumber uint,
+	pageNumber uint,
+	file Upload,
+) (*domain.DocumentPage, error) {
+	page, err := s.GetUserDocumentPageByDocumentNumberAndPageNumber(username, documentNumber, pageNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.IsInReview {
+		return nil, ConflictError.Newf("Page %d of document %d is currently being processed", pageNumber, documentNumber)
+	}
+
+	pageType, err := s.validatePageType(file)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprints, err := s.fingerprintUploads(domain.Name(username), []Upload{file})
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := s.storeOriginal(domain.DocumentNumber(documentNumber), file, fingerprints[0])
+	if err != nil {
+		return nil, err
+	}
+
+	fileContent, err := file.Open()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to process file")
+	}
+	defer fileContent.Close()
+
+	oldPage := *page
+	page.State = domain.PageStateEdited
+	page.Type = pageType
+	page.Fingerprint = domain.Fingerprint(uuid.New().String())
+	page.Text = ""
+	page.Original = original
+
+	err = s.documentArchive.StoreContent(domain.DocumentNumber(documentNumber), page.ContentKey(), fileContent)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err = s.documents.UpdatePage(domain.DocumentNumber(documentNumber), page)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deletePageContent(domain.DocumentNumber(documentNumber), &oldPage); err != nil {
+		return nil, err
+	}
+
+	document, err := s.expectDocumentWithDocumentNumberExists(domain.DocumentNumber(documentNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.markDocumentEdited(document); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (s *documentServiceImpl) ReorderUserDocumentPages(
+	username string,
+	documentNumber uint,
+	pageNumbers []uint,
+) ([]domain.DocumentPage, error) {
+	document, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.expectDocumentPagesNotInReview(document); err != nil {
+		return nil, err
+	}
+
+	if !isPagePermutation(pageNumbers, len(document.Pages)) {
+		err := BadRequestError.Newf("Page numbers have to contain each of the document's %d pages exactly once", len(document.Pages))
+		return nil, errors.AddContext(err, "pageNumbers", "permutation")
+	}
+
+	newOrder := make([]domain.PageNumber, len(pageNumbers))
+	for i, pageNumber := range pageNumbers {
+		newOrder[i] = domain.PageNumber(pageNumber)
+	}
+
+	if err := s.documents.ReorderPages(document.DocumentNumber, newOrder); err != nil {
+		return nil, err
+	}
+
+	document, err = s.expectDocumentWithDocumentNumberExists(document.DocumentNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.markDocumentEdited(document); err != nil {
+		return nil, err
+	}
+
+	pages, _, err := s.documents.GetPagesByDocumentNumber(document.DocumentNumber, domain.PageRequest{Size: len(pageNumbers)})
+	return pages, err
+}
+
+func (s *documentServiceImpl) RotateUserDocumentPage(
+	username string,
+	documentNumber uint,
+	pageNumber uint,
+	degrees int,
+) (*domain.DocumentPage, error) {
+	page, err := s.GetUserDocumentPageByDocumentNumberAndPageNumber(username, documentNumber, pageNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.IsInReview || page.Type != domain.PageTypeTIFF {
+		return nil, ConflictError.Newf("Page %d of document %d may not be rotated until preprocessing finished", pageNumber, documentNumber)
+	}
+
+	rotation := domain.PageRotation(degrees)
+	if rotation != domain.PageRotation90 && rotation != domain.PageRotation180 && rotation != domain.PageRotation270 {
+		err := BadRequestError.Newf("Rotation by %d degrees is not supported", degrees)
+		return nil, errors.AddContext(err, "degrees", "oneof")
+	}
+
+	if err := s.documentPageRenderer.Invalidate(page.Document.DocumentNumber, page); err != nil {
+		return nil, err
+	}
+
+	err = s.documentPageTransformer.RotatePage(page.Document.DocumentNumber, page.PageNumber, rotation)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to rotate page")
+	}
+
+	document, err := s.expectDocumentWithDocumentNumberExists(page.Document.DocumentNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.markDocumentEdited(document); err != nil {
+		return nil, err
+	}
+
+	return s.documents.GetPageByDocumentNumberAndPageNumber(document.DocumentNumber, page.PageNumber)
+}
+
+func (s *documentServiceImpl) MoveUserDocumentPages(
+	username string,
+	documentNumber uint,
+	pageNumbers []uint,
+	targetDocumentNumber uint,
+) ([]domain.DocumentPage, error) {
+	if documentNumber == targetDocumentNumber {
+		err := BadRequestError.New("Pages may not be moved to the document they are part of")
+		return nil, errors.AddContext(err, "targetDocumentNumber", "ne")
+	}
+
+	sourceDocument, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	targetDocument, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(targetDocumentNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.expectDocumentPagesNotInReview(sourceDocument); err != nil {
+		return nil, err
+	}
+
+	if err := s.expectDocumentPagesNotInReview(targetDocument); err != nil {
+		return nil, err
+	}
+
+	pagesToMove, err := s.selectDocumentPages(sourceDocument, pageNumbers)
+	if err != nil {
+		return nil, err
+	}
+
+	movedPages, err := s.movePages(sourceDocument.DocumentNumber, pagesToMove, targetDocument.DocumentNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.markDocumentsEdited(sourceDocument.DocumentNumber, targetDocument.DocumentNumber); err != nil {
+		return nil, err
+	}
+
+	return movedPages, nil
+}
+
+func (s *documentServiceImpl) SplitUserDocument(
+	username string,
+	documentNumber uint,
+	pageNumber uint,
+) (*domain.Document, error) {
+	document, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	if pageNumber < 2 || pageNumber > uint(len(document.Pages)) {
+		err := BadRequestError.Newf("Document %d can only be split at pages 2 to %d", documentNumber, len(document.Pages))
+		return nil, errors.AddContext(err, "pageNumber", "range")
+	}
+
+	if err := s.expectDocumentPagesNotInReview(document); err != nil {
+		return nil, err
+	}
+
+	newDocument, err := s.documents.Add(&domain.Document{
+		Title:         document.Title,
+		Date:          document.Date,
+		State:         domain.DocumentStateEmpty,
+		Owner:         document.Owner,
+		Class:         document.Class,
+		Correspondent: document.Correspondent,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create document")
+	}
+
+	newDocument.Tags = document.Tags
+	if err := s.documents.UpdateTags(newDocument); err != nil {
+		return nil, err
+	}
+
+	newDocument.CustomFields = document.CustomFields
+	if err := s.documents.UpdateCustomFields(newDocument); err != nil {
+		return nil, err
+	}
+
+	pagesToMove := document.Pages[pageNumber-1:]
+	if _, err := s.movePages(document.DocumentNumber, pagesToMove, newDocument.DocumentNumber); err != nil {
+		return nil, err
+	}
+
+	if err := s.markDocumentsEdited(document.DocumentNumber, newDocument.DocumentNumber); err != nil {
+		return nil, err
+	}
+
+	return s.expectDocumentWithDocumentNumberExists(newDocument.DocumentNumber)
+}
+
+func (s *documentServiceImpl) MergeUserDocuments(
+	username string,
+	documentNumber uint,
+	sourceDocumentNumbers []uint,
+) (*domain.Document, error) {
+	document, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.expectDocumentPagesNotInReview(document); err != nil {
+		return nil, err
+	}
+
+	sourceDocuments := make([]*domain.Document, 0, len(sourceDocumentNumbers))
+	merged := map[uint]bool{documentNumber: true}
+	for _, sourceDocumentNumber := range sourceDocumentNumbers {
+		if merged[sourceDocumentNumber] {
+			err := BadRequestError.Newf("Document %d may only be merged once", sourceDocumentNumber)
+			return nil, errors.AddContext(err, "documentNumbers", "unique")
+		}
+
+		sourceDocument, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(sourceDocumentNumber))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.expectDocumentPagesNotInReview(sourceDocument); err != nil {
+			return nil, err
+		}
+
+		merged[sourceDocumentNumber] = true
+		sourceDocuments = append(sourceDocuments, sourceDocument)
+	}
+
+	for _, sourceDocument := range sourceDocuments {
+		document.Tags = mergeTags(document.Tags, sourceDocument.Tags)
+		document.CustomFields = mergeCustomFieldValues(document.CustomFields, sourceDocument.CustomFields)
+	}
+
+	if err := s.documents.UpdateTags(document); err != nil {
+		return nil, err
+	}
+
+	if err := s.documents.UpdateCustomFields(document); err != nil {
+		return nil, err
+	}
+
+	for _, sourceDocument := range sourceDocuments {
+		if _, err := s.movePages(sourceDocument.DocumentNumber, sourceDocument.Pages, document.DocumentNumber); err != nil {
+			return nil, err
+		}
+
+		if err := s.markDocumentsEdited(sourceDocument.DocumentNumber); err != nil {
+			return nil, err
+		}
+
+		if err := s.trashDocument(sourceDocument); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.markDocumentsEdited(document.DocumentNumber); err != nil {
+		return nil, err
+	}
+
+	return s.expectDocumentWithDocumentNumberExists(document.DocumentNumber)
+}
+
+func (s *documentServiceImpl) DeleteUserDocumentPage(
+	username string,
+	documentNumber uint,
+	pageNumber uint,
+) error {
+	document, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+	if err != nil {
+		return err
+	}
+
+	if err := s.expectDocumentPagesNotInReview(document); err != nil {
+		return err
+	}
+
+	page, err := s.GetUserDocumentPageByDocumentNumberAndPageNumber(username, documentNumber, pageNumber)
+	if err != nil {
+		return err
+	}
+
+	if err := s.documents.DeletePage(domain.DocumentNumber(documentNumber), page.PageNumber); err != nil {
+		return err
+	}
+
+	if err := s.deletePageContent(domain.DocumentNumber(documentNumber), page); err != nil {
+		return err
+	}
+
+	document, err = s.expectDocumentWithDocumentNumberExists(domain.DocumentNumber(documentNumber))
+	if err != nil {
+		return err
+	}
+
+	return s.markDocumentEdited(document)
+}
+
+func (s *documentServiceImpl) GetUserDocumentPageContent(
+	username string,
+	documentNumber uint,
+	pageNumber uint,
+) (io.ReadCloser, error) {
+	_, err := s.expectUserDocumentExists(domain.Name(username), domain.DocumentNumber(documentNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.documents.GetPageByDocumentNumberAndPageNumber(
+		domain.DocumentNumber(documentNumber),
+		domain.PageNumber(pageNumber),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if page.State == domain.PageStateEdited {
+		return nil, NotFoundError.Newf(
+			"Page '%d' for document '%d' has no content available until preprocessing finished",
+			pageNumber,
+			documentNumber,
+		)
+	}
+
+	return s.documentArchive.ReadContent(page.Document.DocumentNumber, page.ContentKey())
+}
+
+func (s *documentServiceImpl) GetUserDocumentPageOriginal(
+	username string,
+	documentNumber uint,
+	pageNumber uint,
+) (*domain.PageOriginal, io.ReadCloser, error) {
+	page, err := s.GetUserDocumentPageByDocumentNumberAndPageNumber(username, documentNumber, pageNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if page.Original == nil {
+		return nil, nil, NotFoundError.Newf(
+			"Page '%d' for document '%d' has no original available",
+			pageNumber,
+			documentNumber,
+		)
+	}
+
+	content, err := s.documentArchive.ReadContent(domain.DocumentNumber(documentNumber), page.Original.ContentKey())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return page.Original, content, nil
+}
+
+func (s *documentServiceImpl) GetUserDocumentPageRendition(
+	username string,
+	documentNumber uint,
+	pageNumber uint,
+	width int,
+	format domain.RenditionFormat,
+) (io.ReadCloser, error) {
+	page, err := s.GetUserDocumentPageByDocumentNumberAndPageNumber(username, documentNumber, pageNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pages keep a temporary fingerprint until being preprocessed, so their
+	// renditions must not be cached before.
+	if page.Type != domain.PageTypeTIFF || page.State == domain.PageStateEdited {
+		return nil, NotFoundError.Newf(
+			"Page '%d' for document '%d' has no renditions available until preprocessing finished",
+			pageNumber,
+			documentNumber,
+		)
+	}
+
+	rendition := domain.PageRendition{Width: width, Format: format}
+	return s.documentPageRenderer.Render(domain.DocumentNumber(documentNumber), page, rendition)
+}
+
+/* Helper Methods */
+
+// fingerprintUploads computes the content fingerprint of each of the given
+// files. Files already uploaded by the given user, or contained more than
+// once, are rejected as duplicates.
+func (s *documentServiceImpl) fingerprintUploads(username domain.Name, files []Upload) ([]domain.Fingerprint, error) {
+	fingerprints := make([]domain.Fingerprint, len(files))
+	uploaded := make(map[domain.Fingerprint]string, len(files))
+
+	for i, file := range files {
+		fingerprint, err := s.fingerprintUpload(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if fileName, ok := uploaded[fingerprint]; ok {
+			err := ConflictError.Newf("Files '%s' and '%s' have the same content", fileName, file.FileName)
+			return nil, errors.AddContext(err, "fileName", file.FileName)
+		}
+
+		duplicates, err := s.documents.FindPagesByOriginalFingerprint(username, fingerprint)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(duplicates) > 0 {
+			documentNumber := duplicates[0].Document.DocumentNumber
+			err := ConflictError.Newf("File '%s' has already been uploaded to document %d", file.FileName, documentNumber)
+			return nil, errors.AddContext(err, "documentNumber", fmt.Sprint(documentNumber))
+		}
+
+		uploaded[fingerprint] = file.FileName
+		fingerprints[i] = fingerprint
+	}
+
+	return fingerprints, nil
+}
+
+func (s *documentServiceImpl) fingerprintUpload(file Upload) (domain.Fingerprint, error) {
+	fileContent, err := file.Open()
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to process file")
+	}
+	defer fileContent.Close()
+
+	fingerprint, err := s.computeFingerprint(fileContent)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to process file")
+	}
+
+	return fingerprint, nil
+}
+
+// fingerprintOriginal computes the content fingerprint of the given original
+// stored for the document with the given document number.
+func (s *documentServiceImpl) fingerprintOriginal(
+	documentNumber domain.DocumentNumber,
+	original *domain.PageOriginal,
+) (domain.Fingerprint, error) {
+	content, err := s.documentArchive.ReadContent(documentNumber, original.ContentKey())
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+
+	return s.computeFingerprint(content)
+}
+
+// computeFingerprint computes the fingerprint of the given content, which is
+// the same for equal content.
+func (s *documentServiceImpl) computeFingerprint(content io.Reader) (domain.Fingerprint, error) {
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, content); err != nil {
+		return "", err
+	}
+
+	return domain.Fingerprint(hex.EncodeToString(hasher.Sum(nil))), nil
+}
+
+// storeOriginal stores the given file exactly as uploaded for the document
+// with the given document number.
+func (s *documentServiceImpl) storeOriginal(
+	documentNumber domain.DocumentNumber,
+	file Upload,
+	fingerprint domain.Fingerprint,
+) (*domain.PageOriginal, error) {
+	fileContent, err := file.Open()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to process file")
+	}
+	defer fileContent.Close()
+
+	original := &domain.PageOriginal{
+		Fingerprint: fingerprint,
+		ContentType: file.ContentType,
+		FileName:    file.FileName,
+	}
+
+	if err := s.documentArchive.StoreContent(documentNumber, original.ContentKey(), fileContent); err != nil {
+		return nil, err
+	}
+
+	return original, nil
+}
+
+// addUploadedPages adds the pages contained in the given files with the given
+// fingerprints to the given document. The pages added are returned even if
+// adding the pages of a file fails.
+func (s *documentServiceImpl) addUploadedPages(
+	document *domain.Document,
+	files []Upload,
+	fingerprints []domain.Fingerprint,
+) ([]domain.DocumentPage, error) {
+	pages := make([]domain.DocumentPage, 0)
+	for i, file := range files {
+		nextPageNumber := domain.PageNumber(len(document.Pages) + len(pages) + 1)
+
+		if s.isPDF(file) || s.isTIFF(file) {
+			original, err := s.storeOriginal(document.DocumentNumber, file, fingerprints[i])
+			if err != nil {
+				return pages, err
+			}
+
+			extractedPages, err := s.addExtractedPages(document.DocumentNumber, nextPageNumber, original, file)
+			if err != nil {
+				return pages, err
+			}
+
+			pages = append(pages, extractedPages...)
+			continue
+		}
+
+		pageType, err := s.validatePageType(file)
+		if err != nil {
+			continue
+		}
+
+		original, err := s.storeOriginal(document.DocumentNumber, file, fingerprints[i])
+		if err != nil {
+			return pages, err
+		}
+
+		fileContent, err := file.Open()
+		if err != nil {
+			return pages, errors.Wrapf(err, "Failed to process file")
+		}
+
+		page, err := s.addPage(document.DocumentNumber, nextPageNumber, pageType, original, fileContent)
+		fileContent.Close()
+		if err != nil {
+			return pages, err
+		}
+
+		pages = append(pages, *page)
+	}
+
+	return pages, nil
+}
+
+// addPage stores the given content and adds it as a new, edited page to the
+// document with the given document number.
+func (s *documentServiceImpl) addPage(
+	documentNumber domain.DocumentNumber,
+	pageNumber domain.PageNumber,
+	pageType domain.PageType,
+	original *domain.PageOriginal,
+	content io.Reader,
+) (*domain.DocumentPage, error) {
+	page := &domain.DocumentPage{
+		PageNumber:  pageNumber,
+		State:       domain.PageStateEdited,
+		Type:        pageType,
+		Fingerprint: domain.Fingerprint(uuid.New().String()),
+		Original:    original,
+	}
+
+	if err := s.documentArchive.StoreContent(documentNumber, page.ContentKey(), content); err != nil {
+		return nil, err
+	}
+
+	return s.documents.AddPage(documentNumber, page)
+}
+
+// addExtractedPages extracts the single pages contained in the given PDF or
+// TIFF file and adds each of them as a new page to the document with the
+// given document number, starting with the given page number.
+func (s *documentServiceImpl) addExtractedPages(
+	documentNumber domain.DocumentNumber,
+	firstPageNumber domain.PageNumber,
+	original *domain.PageOriginal,
+	file Upload,
+) ([]domain.DocumentPage, error) {
+	fileContent, err := file.Open()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to process file")
+	}
+	defer fileContent.Close()
+
+	pages := make([]domain.DocumentPage, 0)
+	var pageErr error
+	handler := func(content io.Reader) error {
+		pageNumber := firstPageNumber + domain.PageNumber(len(pages))
+		page, err := s.addPage(documentNumber, pageNumber, domain.PageTypeTIFF, original, content)
+		if err != nil {
+			pageErr = err
+			return err
+		}
+
+		pages = append(pages, *page)
+		return nil
+	}
+
+	if s.isPDF(file) {
+		err = s.documentRasterizer.Rasterize(fileContent, handler)
+	} else {
+		err = s.documentPageSplitter.Split(fileContent, file.Size, handler)
+	}
+
+	if err == nil {
+		return pages, nil
+	}
+
+	// Do not keep a part of the file's pages.
+	if err := s.removeExtractedPages(documentNumber, original, pages); err != nil {
+		return nil, err
+	}
+
+	if pageErr != nil {
+		return nil, pageErr
+	}
+
+	var contentErr *domain.Error
+	if errors.As(err, &contentErr) {
+		return nil, BadRequestError.Newf("Failed to extract pages from file '%s': %s", file.FileName, contentErr.Error())
+	}
+
+	return nil, errors.Wrapf(err, "Failed to extract pages from file '%s'", file.FileName)
+}
+
+// removeExtractedPages removes the given pages just added to the document with
+// the given document number along with their content and original.
+func (s *documentServiceImpl) removeExtractedPages(
+	documentNumber domain.DocumentNumber,
+	original *domain.PageOriginal,
+	pages []domain.DocumentPage,
+) error {
+	for i := len(pages) - 1; i >= 0; i-- {
+		if err := s.documents.DeletePage(documentNumber, pages[i].PageNumber); err != nil {
+			return err
+		}
+
+		if err := s.documentArchive.DeleteContent(documentNumber, pages[i].ContentKey()); err != nil {
+			return err
+		}
+	}
+
+	return s.documentArchive.DeleteContent(documentNumber, original.ContentKey())
+}
+
+// markDocumentEdited marks the given document as edited after its pages
+// changed and sends it to review. Documents left without any pages are reset
+// to be empty, dropping their generated content.
+func (s *documentServiceImpl) markDocumentEdited(document *domain.Document) error {
+	if len(document.Pages) == 0 {
+		if err := s.deleteDocumentContent(document); err != nil {
+			return err
+		}
+
+		document.Fingerprint = ""
+		document.Type = ""
+		document.State = domain.DocumentStateEmpty
+	} else {
+		document.State = domain.DocumentStateEdited
+	}
+
+	if _, err := s.documents.Update(document); err != nil {
+		return err
+	}
+
+	s.documentRegistry.Review(document.DocumentNumber)
+	return nil
+}
+
+func (s *documentServiceImpl) expectDocumentPagesNotInReview(document *domain.Document) error {
+	for _, page := range document.Pages {
+		if page.IsInReview {
+			return ConflictError.Newf("Pages of document %d are currently being processed", document.DocumentNumber)
+		}
+	}
+
+	return nil
+}
+
+// selectDocumentPages returns the pages of the given document matching the
+// given page numbers ordered by their page number.
+func (s *documentServiceImpl) selectDocumentPages(
+	document *domain.Document,
+	pageNumbers []uint,
+) ([]domain.DocumentPage, error) {
+	pagesByNumber := make(map[uint]domain.DocumentPage, len(document.Pages))
+	for _, page := range document.Pages {
+		pagesByNumber[uint(page.PageNumber)] = page
+	}
+
+	selectedPages := make([]domain.DocumentPage, 0, len(pageNumbers))
+	selected := make(map[uint]bool, len(pageNumbers))
+	for _, pageNumber := range pageNumbers {
+		page, ok := pagesByNumber[pageNumber]
+		if !ok {
+			return nil, NotFoundError.Newf("Page %d of document %d does not exist", pageNumber, document.DocumentNumber)
+		}
+
+		if !selected[pageNumber] {
+			selected[pageNumber] = true
+			selectedPages = append(selectedPages, page)
+		}
+	}
+
+	sort.Slice(selectedPages, func(i, j int) bool {
+		return selectedPages[i].PageNumber < selectedPages[j].PageNumber
+	})
+
+	return selectedPages, nil
+}
+
+// movePages moves the given pages, ordered by page number, including their
+// content to the end of the target document.
+func (s *documentServiceImpl) movePages(
+	sourceDocumentNumber domain.DocumentNumber,
+	pages []domain.DocumentPage,
+	targetDocumentNumber domain.DocumentNumber,
+) ([]domain.DocumentPage, error) {
+	movedPages := make([]domain.DocumentPage, len(pages))
+	for i, page := range pages {
+		// Every page moved before closed a gap in front of the current one.
+		currentPageNumber := page.PageNumber - domain.PageNumber(i)
+		movedPage, err := s.movePage(sourceDocumentNumber, currentPageNumber, &page, targetDocumentNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		movedPages[i] = *movedPage
+	}
+
+	return movedPages, nil
+}
+
+// movePage moves a single page including its content to the end of the
+// target document.
+func (s *documentServiceImpl) movePage(
+	sourceDocumentNumber domain.DocumentNumber,
+	pageNumber domain.PageNumber,
+	page *domain.DocumentPage,
+	targetDocumentNumber domain.DocumentNumber,
+) (*domain.DocumentPage, error) {
+	if err := s.copyContent(sourceDocumentNumber, targetDocumentNumber, page.ContentKey()); err != nil {
+		return nil, err
+	}
+
+	if page.Original != nil {
+		if err := s.copyContent(sourceDocumentNumber, targetDocumentNumber, page.Original.ContentKey()); err != nil {
+			return nil, err
+		}
+	}
+
+	movedPage, err := s.documents.MovePage(sourceDocumentNumber, pageNumber, targetDocumentNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deletePageContent(sourceDocumentNumber, page); err != nil {
+		return nil, err
+	}
+
+	return movedPage, nil
+}
+
+func (s *documentServiceImpl) copyContent(
+	sourceDocumentNumber domain.DocumentNumber,
+	targetDocumentNumber domain.DocumentNumber,
+	contentKey domain.ContentKey,
+) error {
+	content, err := s.documentArchive.ReadContent(sourceDocumentNumber, contentKey)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	return s.documentArchive.StoreContent(targetDocumentNumber, contentKey, content)
+}
+
+// markDocumentsEdited reloads the documents with the given document numbers
+// and marks them as edited.
+func (s *documentServiceImpl) markDocumentsEdited(documentNumbers ...domain.DocumentNumber) error {
+	for _, documentNumber := range documentNumbers {
+		document, err := s.expectDocumentWithDocumentNumberExists(documentNumber)
+		if err != nil {
+			return err
+		}
+
+		if err := s.markDocumentEdited(document); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// trashDocument moves the given document to the trash and removes it from
+// the index.
+func (s *documentServiceImpl) trashDocument(document *domain.Document) error {
+	if err := s.documentIndex.DeleteDocument(document.DocumentNumber); err != nil {
+		return err
+	}
+
+	return s.documents.Delete(document)
+}
+
+// purgeDocument permanently deletes the given document alongside its index
+// entry and all of its stored content.
+func (s *documentServiceImpl) purgeDocument(document *domain.Document) error {
+	if err := s.documentIndex.DeleteDocument(document.DocumentNumber); err != nil {
+		return err
+	}
+
+	if err := s.documents.Purge(document); err != nil {
+		return err
+	}
+
+	for _, page := range document.Pages {
+		if err := s.deletePageContent(document.DocumentNumber, &page); err != nil {
+			return err
+		}
+	}
+
+	return s.deleteDocumentContent(document)
+}
+
+// deletePageContent deletes the given, already removed page's content and all
+// of its cached renditions. The page's original is deleted as well, unless
+// other pages of the document still originate from it.
+func (s *documentServiceImpl) deletePageContent(documentNumber domain.DocumentNumber, page *domain.DocumentPage) error {
+	if err := s.documentPageRenderer.Invalidate(documentNumber, page); err != nil {
+		return err
+	}
+
+	if err := s.documentArchive.DeleteContent(documentNumber, page.ContentKey()); err != nil {
+		return err
+	}
+
+	if page.Original == nil {
+		return nil
+	}
+
+	isReferenced, err := s.isOriginalReferenced(documentNumber, page.Original)
+	if err != nil || isReferenced {
+		return err
+	}
+
+	return s.documentArchive.DeleteContent(documentNumber, page.Original.ContentKey())
+}
+
+// isOriginalReferenced checks whether any page of the document with the given
+// document number originates from the given original.
+func (s *documentServiceImpl) isOriginalReferenced(
+	documentNumber domain.DocumentNumber,
+	original *domain.PageOriginal,
+) (bool, error) {
+	document, err := s.documents.GetByDocumentNumber(documentNumber)
+	if err != nil || document == nil {
+		return false, err
+	}
+
+	for _, page := range document.Pages {
+		if page.Original != nil && page.Original.Fingerprint == original.Fingerprint {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (s *documentServiceImpl) deleteDocumentContent(document *domain.Document) error {
+	if document.Fingerprint == "" || document.Type == "" {
+		return nil
+	}
+
+	return s.documentArchive.DeleteContent(document.DocumentNumber, document.ContentKey())
+}
+
+func (s *documentServiceImpl) expectUserDocumentExists(
+	username domain.Name,
+	documentNumber domain.DocumentNumber,
+) (*domain.Document, error) {
+	document, err := s.expectDocumentWithDocumentNumberExists(documentNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.expectUserMayAccessDocument(username, document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// getAllUserDocuments returns all documents owned by the user with the given
+// username, retrieving them batch by batch.
+func (s *documentServiceImpl) getAllUserDocuments(username domain.Name) ([]domain.Document, error) {
+	documents := make([]domain.Document, 0)
+	pr := domain.PageRequest{Size: documentBatchSize}
+
+	for {
+		batch, _, err := s.documents.FindByUsername(username, domain.DocumentFilter{}, pr)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to retreive documents")
+		}
+
+		documents = append(documents, batch...)
+		if len(batch) < documentBatchSize {
+			return documents, nil
+		}
+
+		pr.Offset += documentBatchSize
+	}
+}
+
+// expectUserTagsExist returns the tags with the given IDs, expecting all of
+// them to exist and being owned by the user with the given username.
+func (s *documentServiceImpl) expectUserTagsExist(username domain.Name, tagIDs []uint) ([]domain.Tag, error) {
+	ids := make([]domain.TagID, len(tagIDs))
+	for i, tagID := range tagIDs {
+		ids[i] = domain.TagID(tagID)
+	}
+
+	tags, err := s.tags.FindByIDs(ids...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to retrieve tags")
+	}
+
+	existingTags := make(map[domain.TagID]bool, len(tags))
+	for _, tag := range tags {
+		if tag.Owner.Username != username {
+			return nil, ForbiddenError.Newf("Access to tag %d not permitted", tag.ID)
+		}
+
+		existingTags[tag.ID] = true
+	}
+
+	for _, id := range ids {
+		if !existingTags[id] {
+			err := NotFoundError.Newf("Tag %d does not exist", id)
+			return nil, errors.AddContext(err, "tagIds", "exists")
+		}
+	}
+
+	return tags, nil
+}
+
+// expectUserCustomFieldValuesValid returns the custom fields of the given
+// values by their IDs, expecting all of them to exist, being owned by the user
+// with the given username and matching the type of the respective value.
+// Every custom field may only be given once.
+func (s *documentServiceImpl) expectUserCustomFieldValuesValid(
+	username domain.Name,
+	values []domain.CustomFieldValue,
+) (map[domain.CustomFieldID]domain.CustomField, error) {
+	ids := make([]uint, len(values))
+	given := make(map[domain.CustomFieldID]bool, len(values))
+	for i, value := range values {
+		if given[value.Field.ID] {
+			err := BadRequestError.Newf("Custom field %d may only be given once", value.Field.ID)
+			return nil, errors.AddContext(err, "customFields", "unique")
+		}
+
+		ids[i] = uint(value.Field.ID)
+		given[value.Field.ID] = true
+	}
+
+	customFields, err := expectUserCustomFieldsExist(s.customFields, username, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	customFieldsByID := make(map[domain.CustomFieldID]domain.CustomField, len(customFields))
+	for _, customField := range customFields {
+		customFieldsByID[customField.ID] = customField
+	}
+
+	for _, value := range values {
+		if customFieldsByID[value.Field.ID].Type != value.Field.Type {
+			err := BadRequestError.Newf("Value of custom field %d does not match its type", value.Field.ID)
+			return nil, errors.AddContext(err, "customFields", "type")
+		}
+	}
+
+	return customFieldsByID, nil
+}
+
+// expectUserDocumentClassExists returns the current state of the given
+// document class, expecting it to exist and being owned by the user with the
+// given username. No document class being given is valid, too.
+func (s *documentServiceImpl) expectUserDocumentClassExists(
+	username domain.Name,
+	documentClass *domain.DocumentClass,
+) (*domain.DocumentClass, error) {
+	if documentClass == nil {
+		return nil, nil
+	}
+
+	existingDocumentClass, err := s.documentClasses.GetByID(documentClass.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to retrieve document class")
+	}
+
+	if existingDocumentClass == nil {
+		err := NotFoundError.Newf("Document class %d does not exist", documentClass.ID)
+		return nil, errors.AddContext(err, "classId", "exists")
+	}
+
+	if existingDocumentClass.Owner.Username != username {
+		return nil, ForbiddenError.Newf("Access to document class %d not permitted", documentClass.ID)
+	}
+
+	return existingDocumentClass, nil
+}
+
+// expectUserCorrespondentExists returns the current state of the given
+// correspondent, expecting it to exist and being owned by the user with the
+// given username. No correspondent being given is valid, too.
+func (s *documentServiceImpl) expectUserCorrespondentExists(
+	username domain.Name,
+	correspondent *domain.Correspondent,
+) (*domain.Correspondent, error) {
+	if correspondent == nil {
+		return nil, nil
+	}
+
+	existingCorrespondent, err := s.correspondents.GetByID(correspondent.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to retrieve correspondent")
+	}
+
+	if existingCorrespondent == nil {
+		err := NotFoundError.Newf("Correspondent %d does not exist", correspondent.ID)
+		return nil, errors.AddContext(err, "correspondentId", "exists")
+	}
+
+	if existingCorrespondent.Owner.Username != username {
+		return nil, ForbiddenError.Newf("Access to correspondent %d not permitted", correspondent.ID)
+	}
+
+	return existingCorrespondent, nil
+}
+
+func (s *documentServiceImpl) expectUserTrashedDocumentExists(
+	username domain.Name,
+	documentNumber domain.DocumentNumber,
+) (*domain.Document, error) {
+	document, err := s.documents.GetTrashedByDocumentNumber(documentNumber)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve document")
+	}
+
+	if document == nil {
+		return nil, NotFoundError.Newf("Document %d does not exist in the trash", documentNumber)
+	}
+
+	if err = s.expectUserMayAccessDocument(username, document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+func (s *documentServiceImpl) expectDocumentWithDocumentNumberExists(
+	documentNumber domain.DocumentNumber,
+) (*domain.Document, error) {
+	document, err := s.documents.GetByDocumentNumber(documentNumber)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve document")
+	}
+
+	if document == nil {
+		return nil, NotFoundError.Newf("Document %d does not exist", documentNumber)
+	}
+
+	return document, nil
+}
+
+func (s *documentServiceImpl) expectUserMayAccessDocument(username domain.Name, document *domain.Document) error {
+	mayAccess, err := s.userMayAccessDocument(string(username), document)
+	if err != nil {
+		return err
+	}
+
+	if !mayAccess {
+		return ForbiddenError.Newf("Access to document %d not permitted", document.DocumentNumber)
+	}
+
+	return nil
+}
+
+func (s *documentServiceImpl) userMayAccessDocument(
+	username string,
+	document *domain.Document,
+) (bool, error) {
+	return document.Owner.Username == domain.Name(username), nil
+}
+
+func (s *documentServiceImpl) isPDF(file Upload) bool {
+	return file.ContentType == contentTypePDF
+}
+
+func (s *documentServiceImpl) isTIFF(file Upload) bool {
+	return file.ContentType == contentTypeTIFF
+}
+
+func (s *documentServiceImpl) validatePageType(file Upload) (domain.PageType, error) {
+	contentType := file.ContentType
+	if !validContentTypes.MatchString(contentType) {
+		return domain.PageTypeUnknown, BadRequestError.Newf("Page type '%s' is not supported", contentType)
+	}
+
+	return domain.PageTypeUnknown, nil
+}
+
+// isPagePermutation returns a boolean value indicating whether the given
+// page numbers contain each number from 1 to count exactly once.
+func isPagePermutation(pageNumbers []uint, count int) bool {
+	if len(pageNumbers) != count {
+		return false
+	}
+
+	seen := make([]bool, count)
+	for _, pageNumber := range pageNumbers {
+		if pageNumber < 1 || pageNumber > uint(count) || seen[pageNumber-1] {
+			return false
+		}
+
+		seen[pageNumber-1] = true
+	}
+
+	return true
+}
+
+// mergeTags returns the given tags followed by all additional tags not
+// contained in them yet.
+func mergeTags(tags []domain.Tag, additionalTags []domain.Tag) []domain.Tag {
+	merged := append([]domain.Tag{}, tags...)
+	for _, additionalTag := range additionalTags {
+		contained := false
+		for _, tag := range merged {
+			contained = contained || tag.ID == additionalTag.ID
+		}
+
+		if !contained {
+			merged = append(merged, additionalTag)
+		}
+	}
+
+	return merged
+}
+
+// mergeCustomFieldValues returns the given values followed by all additional
+// values of fields not having a value yet. Existing values take precedence.
+func mergeCustomFieldValues(
+	values []domain.CustomFieldValue,
+	additionalValues []domain.CustomFieldValue,
+) []domain.CustomFieldValue {
+	merged := append([]domain.CustomFieldValue{}, values...)
+	for _, additionalValue := range additionalValues {
+		contained := false
+		for _, value := range merged {
+			contained = contained || value.Field.ID == additionalValue.Field.ID
+		}
+
+		if !contained {
+			merged = append(merged, additionalValue)
+		}
+	}
+
+	return merged
+}
+
+// findAllDocumentNumbers returns the document numbers of all documents owned
+// by the user with the given username matching the given filter.
+func findAllDocumentNumbers(
+	documents domain.Documents,
+	username domain.Name,
+	filter domain.DocumentFilter,
+) ([]domain.DocumentNumber, error) {
+	documentNumbers := make([]domain.DocumentNumber, 0)
+	pr := domain.PageRequest{Size: documentBatchSize}
+
+	for {
+		batch, _, err := documents.FindByUsername(username, filter, pr)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to retreive documents")
+		}
+
+		for _, document := range batch {
+			documentNumbers = append(documentNumbers, document.DocumentNumber)
+		}
+
+		if len(batch) < documentBatchSize {
+			return documentNumbers, nil
+		}
+
+		pr.Offset += documentBatchSize
+	}
+}
+
+// indexDocuments updates the index entries of the documents with the given
+// document numbers, e.g. after metadata shared by them changed.
+func indexDocuments(documentIndex domain.DocumentIndex, documentNumbers []domain.DocumentNumber) error {
+	for _, documentNumber := range documentNumbers {
+		if err := documentIndex.IndexDocument(documentNumber); err != nil {
+			return errors.Wrap(err, "Failed to index document")
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,447 @@
+package application
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+// documentsStub keeps documents in memory. Like the database backed
+// implementation, it does not count the documents beyond the first page.
+type documentsStub struct {
+	domain.Documents
+	documents []domain.Document
+	trashed   []domain.Document
+}
+
+func (d *documentsStub) FindByUsername(
+	username domain.Name,
+	filter domain.DocumentFilter,
+	pr domain.PageRequest,
+) ([]domain.Document, domain.Count, error) {
+	documents := make([]domain.Document, 0)
+	for _, document := range d.documents {
+		if document.Owner != nil && document.Owner.Username == username {
+			documents = append(documents, document)
+		}
+	}
+
+	if pr.Offset > 0 {
+		return pageDocuments(documents, pr), 0, nil
+	}
+
+	return pageDocuments(documents, pr), domain.Count(len(documents)), nil
+}
+
+func (d *documentsStub) GetByDocumentNumber(documentNumber domain.DocumentNumber) (*domain.Document, error) {
+	document := d.find(documentNumber)
+	if document == nil {
+		return nil, nil
+	}
+
+	found := *document
+	found.Pages = append([]domain.DocumentPage{}, document.Pages...)
+	found.Tags = append([]domain.Tag{}, document.Tags...)
+	found.CustomFields = append([]domain.CustomFieldValue{}, document.CustomFields...)
+	return &found, nil
+}
+
+func (d *documentsStub) Add(document *domain.Document) (*domain.Document, error) {
+	added := *document
+	added.DocumentNumber = 1
+	for _, existing := range append(d.documents, d.trashed...) {
+		if existing.DocumentNumber >= added.DocumentNumber {
+			added.DocumentNumber = existing.DocumentNumber + 1
+		}
+	}
+
+	d.documents = append(d.documents, added)
+	return &added, nil
+}
+
+func (d *documentsStub) Update(document *domain.Document) (*domain.Document, error) {
+	existing := d.find(document.DocumentNumber)
+	existing.State = document.State
+	existing.Fingerprint = document.Fingerprint
+	existing.Type = document.Type
+
+	return d.GetByDocumentNumber(document.DocumentNumber)
+}
+
+func (d *documentsStub) UpdateTags(document *domain.Document) error {
+	d.find(document.DocumentNumber).Tags = document.Tags
+	return nil
+}
+
+func (d *documentsStub) UpdateCustomFields(document *domain.Document) error {
+	d.find(document.DocumentNumber).CustomFields = document.CustomFields
+	return nil
+}
+
+func (d *documentsStub) Delete(document *domain.Document) error {
+	for i, existing := range d.documents {
+		if existing.DocumentNumber == document.DocumentNumber {
+			d.trashed = append(d.trashed, existing)
+			d.documents = append(d.documents[:i], d.documents[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (d *documentsStub) MovePage(
+	sourceDocumentNumber domain.DocumentNumber,
+	pageNumber domain.PageNumber,
+	targetDocumentNumber domain.DocumentNumber,
+) (*domain.DocumentPage, error) {
+	source, target := d.find(sourceDocumentNumber), d.find(targetDocumentNumber)
+	pages := make([]domain.DocumentPage, 0, len(source.Pages))
+	var moved domain.DocumentPage
+	for _, page := range source.Pages {
+		if page.PageNumber == pageNumber {
+			moved = page
+			continue
+		}
+
+		if page.PageNumber > pageNumber {
+			page.PageNumber--
+		}
+
+		pages = append(pages, page)
+	}
+
+	source.Pages = pages
+	moved.PageNumber = domain.PageNumber(len(target.Pages) + 1)
+	target.Pages = append(target.Pages, moved)
+	return &moved, nil
+}
+
+func (d *documentsStub) find(documentNumber domain.DocumentNumber) *domain.Document {
+	for i := range d.documents {
+		if d.documents[i].DocumentNumber == documentNumber {
+			return &d.documents[i]
+		}
+	}
+
+	return nil
+}
+
+func (d *documentsStub) GetPageByDocumentNumberAndPageNumber(
+	documentNumber domain.DocumentNumber,
+	pageNumber domain.PageNumber,
+) (*domain.DocumentPage, error) {
+	document, _ := d.GetByDocumentNumber(documentNumber)
+	if document == nil {
+		return nil, nil
+	}
+
+	for _, page := range document.Pages {
+		if page.PageNumber == pageNumber {
+			page.Document = document
+			return &page, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func pageDocuments(documents []domain.Document, pr domain.PageRequest) []domain.Document {
+	if pr.Offset >= len(documents) {
+		return []domain.Document{}
+	}
+
+	end := pr.Offset + pr.Size
+	if end > len(documents) {
+		end = len(documents)
+	}
+
+	return documents[pr.Offset:end]
+}
+
+// documentArchiveStub keeps content in memory.
+type documentArchiveStub struct {
+	content map[string]string
+}
+
+func (a *documentArchiveStub) ReadContent(documentNumber domain.DocumentNumber, contentKey domain.ContentKey) (io.ReadCloser, error) {
+	content, ok := a.content[fmt.Sprintf("%d/%s", documentNumber, contentKey)]
+	if !ok {
+		return nil, errors.Newf("Content %s of document %d does not exist", contentKey, documentNumber)
+	}
+
+	return ioutil.NopCloser(strings.NewReader(content)), nil
+}
+
+func (a *documentArchiveStub) StoreContent(documentNumber domain.DocumentNumber, contentKey domain.ContentKey, content io.Reader) error {
+	data, err := ioutil.ReadAll(content)
+	a.content[fmt.Sprintf("%d/%s", documentNumber, contentKey)] = string(data)
+	return err
+}
+
+func (a *documentArchiveStub) MoveContent(domain.DocumentNumber, domain.ContentKey, domain.ContentKey) error {
+	return errors.New("Not supported")
+}
+
+func (a *documentArchiveStub) DeleteContent(documentNumber domain.DocumentNumber, contentKey domain.ContentKey) error {
+	delete(a.content, fmt.Sprintf("%d/%s", documentNumber, contentKey))
+	return nil
+}
+
+type documentIndexStub struct {
+	domain.DocumentIndex
+	deleted []domain.DocumentNumber
+}
+
+func (i *documentIndexStub) DeleteDocument(documentNumber domain.DocumentNumber) error {
+	i.deleted = append(i.deleted, documentNumber)
+	return nil
+}
+
+type documentRegistryStub struct {
+	reviewed []domain.DocumentNumber
+}
+
+func (r *documentRegistryStub) Review(documentNumber domain.DocumentNumber) {
+	r.reviewed = append(r.reviewed, documentNumber)
+}
+
+type documentPageRendererStub struct {
+	domain.DocumentPageRenderer
+}
+
+func (documentPageRendererStub) Invalidate(domain.DocumentNumber, *domain.DocumentPage) error {
+	return nil
+}
+
+type documentExporterStub struct {
+	documents []domain.Document
+}
+
+func (e *documentExporterStub) Export(documents []domain.Document) (io.ReadCloser, error) {
+	e.documents = documents
+	return ioutil.NopCloser(strings.NewReader("")), nil
+}
+
+func (s *serviceTestSuite) TestExportUserDocuments_WithoutDocumentNumbers() {
+	owner := &domain.User{Username: testUsername}
+	other := &domain.User{Username: wrongUsername}
+	documents := &documentsStub{}
+	for i := 1; i <= 2*documentBatchSize+1; i++ {
+		documents.documents = append(
+			documents.documents,
+			domain.Document{DocumentNumber: domain.DocumentNumber(2 * i), Owner: owner},
+			domain.Document{DocumentNumber: domain.DocumentNumber(2*i + 1), Owner: other},
+		)
+	}
+
+	exporter := &documentExporterStub{}
+	service := NewDocumentService(
+		s.UsersMock, nil, nil, nil, nil, documents, nil, nil, nil, nil, nil, nil, nil, nil, exporter,
+	)
+
+	export, err := service.ExportUserDocuments(testUsername, nil)
+	s.Require().Nil(err)
+	s.Assert().Nil(export.Close())
+
+	s.Require().Len(exporter.documents, 2*documentBatchSize+1)
+	for i, document := range exporter.documents {
+		s.Assert().Equal(domain.DocumentNumber(2*(i+1)), document.DocumentNumber)
+		s.Assert().Equal(owner, document.Owner)
+	}
+}
+
+func (s *serviceTestSuite) TestFindAllDocumentNumbers() {
+	owner := &domain.User{Username: testUsername}
+	documents := &documentsStub{}
+	expected := make([]domain.DocumentNumber, 0)
+	for i := 1; i <= 2*documentBatchSize+1; i++ {
+		documents.documents = append(documents.documents, domain.Document{DocumentNumber: domain.DocumentNumber(i), Owner: owner})
+		expected = append(expected, domain.DocumentNumber(i))
+	}
+
+	documentNumbers, err := findAllDocumentNumbers(documents, testUsername, domain.DocumentFilter{})
+	s.Require().Nil(err)
+	s.Assert().Equal(expected, documentNumbers)
+}
+
+func (s *serviceTestSuite) TestDeleteUserDocumentPage_WithPagesInReview() {
+	documents := &documentsStub{documents: []domain.Document{{
+		DocumentNumber: 1,
+		Owner:          &domain.User{Username: testUsername},
+		Pages: []domain.DocumentPage{
+			{PageNumber: 1, State: domain.PageStateAnalyzed},
+			{PageNumber: 2, State: domain.PageStateEdited, IsInReview: true},
+		},
+	}}}
+
+	service := NewDocumentService(
+		s.UsersMock, nil, nil, nil, nil, documents, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+	)
+
+	err := service.DeleteUserDocumentPage(testUsername, 1, 1)
+	s.Require().NotNil(err)
+	s.Assert().Equal(ConflictError, GetErrorType(err))
+}
+
+func (s *serviceTestSuite) TestGetUserDocumentPageRendition_WithEditedPage() {
+	documents := &documentsStub{documents: []domain.Document{{
+		DocumentNumber: 1,
+		Owner:          &domain.User{Username: testUsername},
+		Pages: []domain.DocumentPage{
+			{PageNumber: 1, State: domain.PageStateEdited, Type: domain.PageTypeTIFF, Fingerprint: "temporary"},
+		},
+	}}}
+
+	service := NewDocumentService(
+		s.UsersMock, nil, nil, nil, nil, documents, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+	)
+
+	_, err := service.GetUserDocumentPageRendition(testUsername, 1, 1, 128, domain.RenditionFormatPNG)
+	s.Require().NotNil(err)
+	s.Assert().Equal(NotFoundError, GetErrorType(err))
+}
+
+// newPagedDocument returns a new, edited document owned by the test user whose
+// pages are stored in the given archive.
+func newPagedDocument(
+	archive *documentArchiveStub,
+	documentNumber domain.DocumentNumber,
+	pageCount int,
+) domain.Document {
+	document := domain.Document{
+		DocumentNumber: documentNumber,
+		State:          domain.DocumentStateEdited,
+		Owner:          &domain.User{Username: testUsername},
+	}
+
+	for i := 1; i <= pageCount; i++ {
+		page := domain.DocumentPage{
+			PageNumber:  domain.PageNumber(i),
+			State:       domain.PageStateAnalyzed,
+			Type:        domain.PageTypeTIFF,
+			Fingerprint: domain.Fingerprint(fmt.Sprintf("%d-%d", documentNumber, i)),
+		}
+
+		archive.content[fmt.Sprintf("%d/%s", documentNumber, page.ContentKey())] = string(page.Fingerprint)
+		document.Pages = append(document.Pages, page)
+	}
+
+	return document
+}
+
+func (s *serviceTestSuite) TestSplitUserDocument() {
+	archive := &documentArchiveStub{content: map[string]string{}}
+	document := newPagedDocument(archive, 1, 3)
+	document.Title = "Letters"
+	document.Tags = []domain.Tag{{ID: 1, Name: "mail"}}
+	documents := &documentsStub{documents: []domain.Document{document}}
+	registry := &documentRegistryStub{}
+
+	service := NewDocumentService(
+		s.UsersMock, nil, nil, nil, nil, documents, archive, &documentIndexStub{}, registry,
+		nil, nil, nil, nil, documentPageRendererStub{}, nil,
+	)
+
+	newDocument, err := service.SplitUserDocument(testUsername, 1, 2)
+	s.Require().Nil(err)
+
+	s.Assert().Equal(domain.DocumentNumber(2), newDocument.DocumentNumber)
+	s.Assert().Equal(domain.Text("Letters"), newDocument.Title)
+	s.Assert().Equal(domain.Name(testUsername), newDocument.Owner.Username)
+	s.Assert().Equal(document.Tags, newDocument.Tags)
+	s.Assert().Equal(domain.DocumentStateEdited, newDocument.State)
+	s.Require().Len(newDocument.Pages, 2)
+	for i, page := range newDocument.Pages {
+		s.Assert().Equal(domain.PageNumber(i+1), page.PageNumber)
+		s.Assert().Equal(domain.Fingerprint(fmt.Sprintf("1-%d", i+2)), page.Fingerprint)
+		s.Assert().Equal(string(page.Fingerprint), archive.content["2/"+string(page.ContentKey())])
+		s.Assert().NotContains(archive.content, "1/"+string(page.ContentKey()))
+	}
+
+	remaining, _ := documents.GetByDocumentNumber(1)
+	s.Require().Len(remaining.Pages, 1)
+	s.Assert().Equal(domain.Fingerprint("1-1"), remaining.Pages[0].Fingerprint)
+	s.Assert().ElementsMatch([]domain.DocumentNumber{1, 2}, registry.reviewed)
+}
+
+func (s *serviceTestSuite) TestSplitUserDocument_AtFirstPage() {
+	archive := &documentArchiveStub{content: map[string]string{}}
+	documents := &documentsStub{documents: []domain.Document{newPagedDocument(archive, 1, 2)}}
+
+	service := NewDocumentService(
+		s.UsersMock, nil, nil, nil, nil, documents, archive, nil, nil, nil, nil, nil, nil, nil, nil,
+	)
+
+	_, err := service.SplitUserDocument(testUsername, 1, 1)
+	s.Require().NotNil(err)
+	s.Assert().Equal(BadRequestError, GetErrorType(err))
+}
+
+func (s *serviceTestSuite) TestMergeUserDocuments() {
+	archive := &documentArchiveStub{content: map[string]string{}}
+	invoice := domain.CustomField{ID: 1, Name: "invoice", Type: domain.CustomFieldTypeString}
+	amount := domain.CustomField{ID: 2, Name: "amount", Type: domain.CustomFieldTypeMonetary}
+
+	target := newPagedDocument(archive, 1, 1)
+	target.Tags = []domain.Tag{{ID: 1, Name: "mail"}}
+	target.CustomFields = []domain.CustomFieldValue{{Field: invoice, Value: "A-1"}}
+	first := newPagedDocument(archive, 2, 2)
+	first.Tags = []domain.Tag{{ID: 2, Name: "tax"}, {ID: 1, Name: "mail"}}
+	first.CustomFields = []domain.CustomFieldValue{{Field: invoice, Value: "B-2"}, {Field: amount, Value: "12.50"}}
+	second := newPagedDocument(archive, 3, 1)
+	second.Fingerprint = "generated"
+	second.Type = domain.DocumentTypePDF
+	archive.content["3/"+string(second.ContentKey())] = "generated"
+
+	documents := &documentsStub{documents: []domain.Document{target, first, second}}
+	index := &documentIndexStub{}
+	service := NewDocumentService(
+		s.UsersMock, nil, nil, nil, nil, documents, archive, index, &documentRegistryStub{},
+		nil, nil, nil, nil, documentPageRendererStub{}, nil,
+	)
+
+	merged, err := service.MergeUserDocuments(testUsername, 1, []uint{3, 2})
+	s.Require().Nil(err)
+
+	s.Require().Len(merged.Pages, 4)
+	for i, fingerprint := range []domain.Fingerprint{"1-1", "3-1", "2-1", "2-2"} {
+		page := merged.Pages[i]
+		s.Assert().Equal(domain.PageNumber(i+1), page.PageNumber)
+		s.Assert().Equal(fingerprint, page.Fingerprint)
+		s.Assert().Equal(string(fingerprint), archive.content["1/"+string(page.ContentKey())])
+	}
+
+	s.Assert().Equal([]domain.Tag{{ID: 1, Name: "mail"}, {ID: 2, Name: "tax"}}, merged.Tags)
+	s.Assert().Equal(
+		[]domain.CustomFieldValue{{Field: invoice, Value: "A-1"}, {Field: amount, Value: "12.50"}},
+		merged.CustomFields,
+	)
+
+	s.Assert().Equal([]domain.DocumentNumber{3, 2}, index.deleted)
+	s.Require().Len(documents.trashed, 2)
+	for _, trashed := range documents.trashed {
+		s.Assert().Empty(trashed.Pages)
+		s.Assert().Equal(domain.DocumentStateEmpty, trashed.State)
+	}
+	s.Assert().Equal(first.Tags, documents.trashed[1].Tags)
+	s.Assert().NotContains(archive.content, "3/"+string(second.ContentKey()))
+}
+
+func (s *serviceTestSuite) TestMergeUserDocuments_WithDuplicateDocument() {
+	archive := &documentArchiveStub{content: map[string]string{}}
+	documents := &documentsStub{documents: []domain.Document{newPagedDocument(archive, 1, 1), newPagedDocument(archive, 2, 1)}}
+
+	service := NewDocumentService(
+		s.UsersMock, nil, nil, nil, nil, documents, archive, nil, nil, nil, nil, nil, nil, nil, nil,
+	)
+
+	_, err := service.MergeUserDocuments(testUsername, 1, []uint{2, 2})
+	s.Require().NotNil(err)
+	s.Assert().Equal(BadRequestError, GetErrorType(err))
+	s.Assert().Len(documents.documents, 2)
+}
@@ -0,0 +1,170 @@
+package application
+
+import (
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+// TagService defines an application service for managing tag use-cases.
+type TagService interface {
+	// GetUserTags returns the tags owned by the given user with respect to the given page request.
+	GetUserTags(username string, pr domain.PageRequest) ([]domain.Tag, int64, error)
+
+	// GetUserTagByID returns the tag with the given ID owned by the given user.
+	GetUserTagByID(username string, id uint) (*domain.Tag, error)
+
+	// CreateNewTag creates the given new tag owned by the given user.
+	CreateNewTag(username string, tag *domain.Tag) (*domain.Tag, error)
+
+	// UpdateUserTag updates the given tag owned by the given user.
+	UpdateUserTag(username string, tag *domain.Tag) (*domain.Tag, error)
+
+	// DeleteUserTag deletes the tag with the given ID owned by the given user, removing it from all documents.
+	DeleteUserTag(username string, id uint) error
+}
+
+type tagServiceImpl struct {
+	users         domain.Users
+	tags          domain.Tags
+	documents     domain.Documents
+	documentIndex domain.DocumentIndex
+}
+
+// NewTagService creates a new tag service.
+func NewTagService(
+	users domain.Users,
+	tags domain.Tags,
+	documents domain.Documents,
+	documentIndex domain.DocumentIndex,
+) TagService {
+	return &tagServiceImpl{
+		users:         users,
+		tags:          tags,
+		documents:     documents,
+		documentIndex: documentIndex,
+	}
+}
+
+func (s *tagServiceImpl) GetUserTags(username string, pr domain.PageRequest) ([]domain.Tag, int64, error) {
+	tags, count, err := s.tags.FindByUsername(domain.Name(username), pr)
+	if err != nil {
+		return nil, -1, errors.Wrap(err, "Failed to retrieve tags")
+	}
+
+	return tags, int64(count), nil
+}
+
+func (s *tagServiceImpl) GetUserTagByID(username string, id uint) (*domain.Tag, error) {
+	return s.expectUserTagExists(domain.Name(username), domain.TagID(id))
+}
+
+func (s *tagServiceImpl) CreateNewTag(username string, tag *domain.Tag) (*domain.Tag, error) {
+	owner, err := s.users.GetByUsername(domain.Name(username))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.expectTagNameNotAlreadyTaken(owner.Username, tag.Name); err != nil {
+		return nil, err
+	}
+
+	tag.ID = 0
+	tag.Owner = owner
+
+	newTag, err := s.tags.Add(tag)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create tag")
+	}
+
+	return newTag, nil
+}
+
+func (s *tagServiceImpl) UpdateUserTag(username string, tag *domain.Tag) (*domain.Tag, error) {
+	originalTag, err := s.expectUserTagExists(domain.Name(username), tag.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tag.Name != originalTag.Name {
+		if err := s.expectTagNameNotAlreadyTaken(originalTag.Owner.Username, tag.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	documentNumbers, err := s.getTaggedDocumentNumbers(originalTag)
+	if err != nil {
+		return nil, err
+	}
+
+	originalTag.Name = tag.Name
+	originalTag.Color = tag.Color
+
+	tag, err = s.tags.Update(originalTag)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to update tag")
+	}
+
+	if err := indexDocuments(s.documentIndex, documentNumbers); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (s *tagServiceImpl) DeleteUserTag(username string, id uint) error {
+	tag, err := s.expectUserTagExists(domain.Name(username), domain.TagID(id))
+	if err != nil {
+		return err
+	}
+
+	documentNumbers, err := s.getTaggedDocumentNumbers(tag)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tags.Delete(tag); err != nil {
+		return err
+	}
+
+	return indexDocuments(s.documentIndex, documentNumbers)
+}
+
+/* Helper Methods */
+
+// getTaggedDocumentNumbers returns the document numbers of all documents the
+// given tag is assigned to.
+func (s *tagServiceImpl) getTaggedDocumentNumbers(tag *domain.Tag) ([]domain.DocumentNumber, error) {
+	filter := domain.DocumentFilter{TagIDs: []domain.TagID{tag.ID}}
+	return findAllDocumentNumbers(s.documents, tag.Owner.Username, filter)
+}
+
+func (s *tagServiceImpl) expectUserTagExists(username domain.Name, id domain.TagID) (*domain.Tag, error) {
+	tag, err := s.tags.GetByID(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve tag")
+	}
+
+	if tag == nil {
+		return nil, NotFoundError.Newf("Tag %d does not exist", id)
+	}
+
+	if tag.Owner.Username != username {
+		return nil, ForbiddenError.Newf("Access to tag %d not permitted", id)
+	}
+
+	return tag, nil
+}
+
+func (s *tagServiceImpl) expectTagNameNotAlreadyTaken(username domain.Name, name domain.Name) error {
+	tag, err := s.tags.GetByUsernameAndName(username, name)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve tag")
+	}
+
+	if tag != nil {
+		err := ConflictError.Newf("Tag '%s' already exists", name)
+		return errors.AddContext(err, "name", "unique")
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package application
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+const contentSniffLength = 512
+
+var (
+	tiffLittleEndianHeader = []byte("II*\x00")
+	tiffBigEndianHeader    = []byte("MM\x00*")
+)
+
+// UploadContent represents the readable content of an uploaded file.
+type UploadContent interface {
+	io.Reader
+	io.ReaderAt
+	io.Closer
+}
+
+// Upload represents a file uploaded for being added to a document, regardless
+// of where it originates from.
+type Upload struct {
+	FileName    string
+	ContentType string
+	Size        int64
+
+	open func() (UploadContent, error)
+}
+
+// Open opens the uploaded file's content for reading.
+func (u Upload) Open() (UploadContent, error) {
+	return u.open()
+}
+
+// NewMultipartUpload returns a new upload for the given file of a multipart form.
+func NewMultipartUpload(file *multipart.FileHeader) Upload {
+	return Upload{
+		FileName:    file.Filename,
+		ContentType: file.Header.Get(mimeHeaderKeyContentType),
+		Size:        file.Size,
+		open: func() (UploadContent, error) {
+			return file.Open()
+		},
+	}
+}
+
+// NewMultipartUploads returns a new upload for each of the given files of a multipart form.
+func NewMultipartUploads(files []*multipart.FileHeader) []Upload {
+	uploads := make([]Upload, len(files))
+	for i, file := range files {
+		uploads[i] = NewMultipartUpload(file)
+	}
+
+	return uploads
+}
+
+// NewFileUpload returns a new upload for the file at the given path. The
+// content type is detected based on the file's content.
+func NewFileUpload(path string) (Upload, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Upload{}, errors.Wrapf(err, "Failed to open file '%s'", path)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return Upload{}, errors.Wrapf(err, "Failed to open file '%s'", path)
+	}
+
+	head := make([]byte, contentSniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return Upload{}, errors.Wrapf(err, "Failed to read file '%s'", path)
+	}
+
+	return Upload{
+		FileName:    filepath.Base(path),
+		ContentType: detectContentType(head[:n]),
+		Size:        info.Size(),
+		open: func() (UploadContent, error) {
+			return os.Open(path)
+		},
+	}, nil
+}
+
+// detectContentType detects the content type of the given leading bytes of a
+// file, including TIFF images not covered by the standard library.
+func detectContentType(head []byte) string {
+	if bytes.HasPrefix(head, tiffLittleEndianHeader) || bytes.HasPrefix(head, tiffBigEndianHeader) {
+		return contentTypeTIFF
+	}
+
+	return http.DetectContentType(head)
+}
@@ -0,0 +1,48 @@
+package application
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectContentType(t *testing.T) {
+	cases := []struct {
+		name     string
+		head     []byte
+		expected string
+	}{
+		{"tiff little endian", []byte("II*\x00\x08\x00\x00\x00"), contentTypeTIFF},
+		{"tiff big endian", []byte("MM\x00*\x00\x00\x00\x08"), contentTypeTIFF},
+		{"pdf", []byte("%PDF-1.4\n"), contentTypePDF},
+		{"png", []byte("\x89PNG\r\n\x1a\n"), "image/png"},
+		{"unknown", []byte{0x00, 0x01, 0x02}, "application/octet-stream"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, detectContentType(c.head))
+		})
+	}
+}
+
+func TestNewFileUpload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.pdf")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("%PDF-1.4\n"), 0600))
+
+	upload, err := NewFileUpload(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "scan.pdf", upload.FileName)
+	assert.Equal(t, contentTypePDF, upload.ContentType)
+	assert.Equal(t, int64(9), upload.Size)
+
+	content, err := upload.Open()
+	assert.Nil(t, err)
+	defer content.Close()
+
+	data, err := ioutil.ReadAll(content)
+	assert.Nil(t, err)
+	assert.Equal(t, "%PDF-1.4\n", string(data))
+}
@@ -51,7 +51,13 @@ type SecurityConfiguration struct {
 
 // StorageConfiguration holds all configuration values regarding file storage.
 type StorageConfiguration struct {
-	DataPath string `default:"data" split_words:"true"`
+	DataPath             string        `default:"data" split_words:"true"`
+	TrashRetentionPeriod time.Duration `default:"720h" split_words:"true"`
+	TrashPurgeInterval   time.Duration `default:"1h" split_words:"true"`
+	ConsumePath          string        `split_words:"true"`
+	ConsumeUsername      string        `split_words:"true"`
+	ConsumeInterval      time.Duration `default:"10s" split_words:"true"`
+	ConsumeKeepFiles     bool          `split_words:"true"`
 }
 
 type IndexConfiguration struct {
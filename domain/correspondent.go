@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// CorrespondentID represents the type of a correspondent's unique identifier.
+type CorrespondentID uint
+
+// Correspondent represents the sender or recipient of documents, e.g. a
+// company or an authority.
+type Correspondent struct {
+	ID   CorrespondentID
+	Name Name
+
+	// DocumentCount and LastCorrespondenceDate are derived from the
+	// correspondent's documents, excluding trashed ones.
+	DocumentCount          Count
+	LastCorrespondenceDate *time.Time
+
+	Owner *User
+}
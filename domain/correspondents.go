@@ -0,0 +1,28 @@
+package domain
+
+// Correspondents defines an interface for managing the collection of all
+// correspondents.
+type Correspondents interface {
+	// FindByUsername returns the set of correspondents owned by the user with
+	// the given username including their document statistics, alongside with
+	// the total count with respect to the given page request.
+	FindByUsername(username Name, pr PageRequest) ([]Correspondent, Count, error)
+
+	// GetByID returns the correspondent with the given ID including its
+	// document statistics or nil in case no such correspondent exists.
+	GetByID(id CorrespondentID) (*Correspondent, error)
+
+	// GetByUsernameAndName returns the correspondent with the given name
+	// owned by the user with the given username or nil in case no such
+	// correspondent exists.
+	GetByUsernameAndName(username Name, name Name) (*Correspondent, error)
+
+	// Add adds the given correspondent.
+	Add(correspondent *Correspondent) (*Correspondent, error)
+
+	// Update updates the given correspondent.
+	Update(correspondent *Correspondent) (*Correspondent, error)
+
+	// Delete deletes the given correspondent, removing it from all documents.
+	Delete(correspondent *Correspondent) error
+}
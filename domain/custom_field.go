@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+type (
+	// CustomFieldID represents the type of a custom field's unique identifier.
+	CustomFieldID uint
+
+	// CustomFieldType represents the type of the values of a custom field.
+	CustomFieldType string
+)
+
+const (
+	// CustomFieldTypeString represents custom fields holding arbitrary text.
+	CustomFieldTypeString = CustomFieldType("STRING")
+
+	// CustomFieldTypeInteger represents custom fields holding integers.
+	CustomFieldTypeInteger = CustomFieldType("INTEGER")
+
+	// CustomFieldTypeMonetary represents custom fields holding monetary
+	// amounts with two decimal places, e.g. 1234.50.
+	CustomFieldTypeMonetary = CustomFieldType("MONETARY")
+
+	// CustomFieldTypeDate represents custom fields holding dates without a
+	// time of day.
+	CustomFieldTypeDate = CustomFieldType("DATE")
+
+	// CustomFieldTypeBoolean represents custom fields holding either true or
+	// false.
+	CustomFieldTypeBoolean = CustomFieldType("BOOLEAN")
+
+	// CustomFieldTypeURL represents custom fields holding absolute URLs.
+	CustomFieldTypeURL = CustomFieldType("URL")
+)
+
+// CustomFieldDateFormat defines the format of date values of custom fields.
+const CustomFieldDateFormat = "2006-01-02"
+
+// CustomField represents the definition of a typed metadata field users may
+// fill in for their documents, e.g. an invoice amount or a policy number.
+type CustomField struct {
+	ID   CustomFieldID
+	Name Name
+	Type CustomFieldType
+
+	Owner *User
+}
+
+// CustomFieldValue represents the value of a custom field for a document.
+// Values are kept in their canonical text representation, which is the
+// decimal notation for integers and monetary amounts, CustomFieldDateFormat
+// for dates and either true or false for booleans.
+type CustomFieldValue struct {
+	Field CustomField
+	Value string
+}
+
+// TypedValue returns the value converted to the Go type matching the type of
+// its field: int64 for integers, float64 for monetary amounts, time.Time for
+// dates, bool for booleans and string otherwise.
+func (v CustomFieldValue) TypedValue() (interface{}, error) {
+	switch v.Field.Type {
+	case CustomFieldTypeInteger:
+		return strconv.ParseInt(v.Value, 10, 64)
+	case CustomFieldTypeMonetary:
+		return strconv.ParseFloat(v.Value, 64)
+	case CustomFieldTypeDate:
+		return time.Parse(CustomFieldDateFormat, v.Value)
+	case CustomFieldTypeBoolean:
+		return strconv.ParseBool(v.Value)
+	default:
+		return v.Value, nil
+	}
+}
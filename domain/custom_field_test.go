@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomFieldValueTypedValue(t *testing.T) {
+	testCases := []struct {
+		fieldType CustomFieldType
+		value     string
+		expected  interface{}
+	}{
+		{CustomFieldTypeString, "AB-123", "AB-123"},
+		{CustomFieldTypeInteger, "-42", int64(-42)},
+		{CustomFieldTypeMonetary, "1234.50", 1234.5},
+		{CustomFieldTypeDate, "2021-03-14", time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{CustomFieldTypeBoolean, "true", true},
+		{CustomFieldTypeURL, "https://example.com/", "https://example.com/"},
+	}
+
+	for _, testCase := range testCases {
+		value := CustomFieldValue{
+			Field: CustomField{Type: testCase.fieldType},
+			Value: testCase.value,
+		}
+
+		typedValue, err := value.TypedValue()
+
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expected, typedValue)
+	}
+}
+
+func TestCustomFieldValueTypedValueInvalid(t *testing.T) {
+	value := CustomFieldValue{
+		Field: CustomField{Type: CustomFieldTypeInteger},
+		Value: "forty-two",
+	}
+
+	_, err := value.TypedValue()
+
+	assert.NotNil(t, err)
+}
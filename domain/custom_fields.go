@@ -0,0 +1,32 @@
+package domain
+
+// CustomFields defines an interface for managing the collection of all custom
+// field definitions.
+type CustomFields interface {
+	// FindByUsername returns the set of custom fields owned by the user with
+	// the given username, alongside with the total count with respect to the
+	// given page request.
+	FindByUsername(username Name, pr PageRequest) ([]CustomField, Count, error)
+
+	// FindByIDs returns the subset of custom fields matching the given set of
+	// custom field IDs.
+	FindByIDs(ids ...CustomFieldID) ([]CustomField, error)
+
+	// GetByID returns the custom field with the given ID or nil in case no
+	// such custom field exists.
+	GetByID(id CustomFieldID) (*CustomField, error)
+
+	// GetByUsernameAndName returns the custom field with the given name owned
+	// by the user with the given username or nil in case no such custom field
+	// exists.
+	GetByUsernameAndName(username Name, name Name) (*CustomField, error)
+
+	// Add adds the given custom field.
+	Add(customField *CustomField) (*CustomField, error)
+
+	// Update updates the given custom field.
+	Update(customField *CustomField) (*CustomField, error)
+
+	// Delete deletes the given custom field including all of its values.
+	Delete(customField *CustomField) error
+}
@@ -7,12 +7,17 @@ import (
 )
 
 type (
-	// DocumentType represents the type of a document.
+	// DocumentType represents the file format of a document's artifact. See
+	// DocumentClass for classifying documents by their kind.
 	DocumentType string
 
 	// DocumentState represents the state of a document.
 	DocumentState string
 
+	// DocumentLanguage represents the ISO 639-1 code of the language a
+	// document's text is written in.
+	DocumentLanguage string
+
 	// DocumentNumber represents the of a document's unique identifier.
 	DocumentNumber uint
 
@@ -44,6 +49,18 @@ const (
 	DocumentStateArchived = DocumentState("ARCHIVED")
 )
 
+const (
+	// DocumentLanguageUnknown marks documents whose language could not be
+	// detected or is not supported.
+	DocumentLanguageUnknown = DocumentLanguage("")
+
+	// DocumentLanguageGerman marks documents written in German.
+	DocumentLanguageGerman = DocumentLanguage("de")
+
+	// DocumentLanguageEnglish marks documents written in English.
+	DocumentLanguageEnglish = DocumentLanguage("en")
+)
+
 // Document represents a document managed by the system.
 type Document struct {
 	DocumentNumber DocumentNumber
@@ -52,12 +69,26 @@ type Document struct {
 	State          DocumentState
 	Fingerprint    Fingerprint
 	Type           DocumentType
+	Language       DocumentLanguage
 	IsInReview     bool
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+	DeletedAt      *time.Time
+
+	Owner         *User
+	Class         *DocumentClass
+	Correspondent *Correspondent
+	Pages         []DocumentPage
+	Tags          []Tag
+	CustomFields  []CustomFieldValue
+}
 
-	Owner *User
-	Pages []DocumentPage
+// DocumentFilter restricts a set of documents to those matching all of the
+// given criteria. Empty criteria match any document.
+type DocumentFilter struct {
+	TagIDs          []TagID
+	ClassID         *DocumentClassID
+	CorrespondentID *CorrespondentID
 }
 
 // ContentKey returns the content key for the document.
@@ -69,6 +100,12 @@ func (d Document) ContentKey() ContentKey {
 	))
 }
 
+// IsTrashed returns a boolean value indicating whether the document has been
+// moved to the trash.
+func (d Document) IsTrashed() bool {
+	return d.DeletedAt != nil
+}
+
 // AreAllPagesInState returns a boolean value indicating whether all the
 // document's pages are in the given page state.
 func (d Document) AreAllPagesInState(state PageState) bool {
@@ -17,5 +17,6 @@ type DocumentArchive interface {
 	MoveContent(documentNumber DocumentNumber, sourceContentKey ContentKey, destinationContentKey ContentKey) error
 
 	// DeleteContent deletes the content for a document or page from the store.
+	// Deleting content which does not exist is not considered an error.
 	DeleteContent(documentNumber DocumentNumber, contentKey ContentKey) error
 }
@@ -0,0 +1,14 @@
+package domain
+
+// DocumentClassID represents the type of a document class' unique identifier.
+type DocumentClassID uint
+
+// DocumentClass represents a user-defined classification of documents by
+// their kind, e.g. invoice, contract or payslip. Other than DocumentType, it
+// does not relate to the document's file format.
+type DocumentClass struct {
+	ID   DocumentClassID
+	Name Name
+
+	Owner *User
+}
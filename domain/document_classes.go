@@ -0,0 +1,28 @@
+package domain
+
+// DocumentClasses defines an interface for managing the collection of all
+// document classes.
+type DocumentClasses interface {
+	// FindByUsername returns the set of document classes owned by the user
+	// with the given username, alongside with the total count with respect
+	// to the given page request.
+	FindByUsername(username Name, pr PageRequest) ([]DocumentClass, Count, error)
+
+	// GetByID returns the document class with the given ID or nil in case no
+	// such document class exists.
+	GetByID(id DocumentClassID) (*DocumentClass, error)
+
+	// GetByUsernameAndName returns the document class with the given name
+	// owned by the user with the given username or nil in case no such
+	// document class exists.
+	GetByUsernameAndName(username Name, name Name) (*DocumentClass, error)
+
+	// Add adds the given document class.
+	Add(documentClass *DocumentClass) (*DocumentClass, error)
+
+	// Update updates the given document class.
+	Update(documentClass *DocumentClass) (*DocumentClass, error)
+
+	// Delete deletes the given document class, removing it from all documents.
+	Delete(documentClass *DocumentClass) error
+}
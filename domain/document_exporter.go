@@ -0,0 +1,12 @@
+package domain
+
+import "io"
+
+// DocumentExporter defines an interface for exporting documents including all
+// of their stored content into a single, self-describing archive.
+type DocumentExporter interface {
+	// Export returns a reader streaming the export of the given documents.
+	// The export is created while reading, so content is never buffered
+	// as a whole.
+	Export(documents []Document) (io.ReadCloser, error)
+}
@@ -6,6 +6,6 @@ import "io"
 // actual, human-readable documents.
 type DocumentGenerator interface {
 	// Generate generates the given document and returns a reader
-	// for the generated content.
-	Generate(document *Document) (io.Reader, error)
+	// for the generated content, which has to be closed by the caller.
+	Generate(document *Document) (io.ReadCloser, error)
 }
@@ -43,7 +43,24 @@ type DocumentPage struct {
 	Type        PageType
 	Fingerprint Fingerprint
 	IsInReview  bool
+	Original    *PageOriginal
 	Document    *Document
+
+	PerceptualHash *PerceptualHash
+}
+
+// PageDuplicates represents a set of pages sharing the very same content.
+type PageDuplicates struct {
+	Fingerprint Fingerprint
+	Pages       []DocumentPage
+}
+
+// PageOriginal describes the file a page originates from, exactly as it has
+// been uploaded. Multiple pages may originate from the same file.
+type PageOriginal struct {
+	Fingerprint Fingerprint
+	ContentType string
+	FileName    string
 }
 
 // ContentKey returns the content key for the document.
@@ -54,3 +71,8 @@ func (d DocumentPage) ContentKey() ContentKey {
 		strings.ToLower(string(d.Type)),
 	))
 }
+
+// ContentKey returns the content key for the original file.
+func (o PageOriginal) ContentKey() ContentKey {
+	return ContentKey(fmt.Sprintf("%s.original", o.Fingerprint))
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RenditionFormat represents the image format of a page rendition.
+type RenditionFormat string
+
+const (
+	// RenditionFormatPNG represents renditions encoded as PNG.
+	RenditionFormatPNG = RenditionFormat("PNG")
+
+	// RenditionFormatJPEG represents renditions encoded as JPEG.
+	RenditionFormatJPEG = RenditionFormat("JPEG")
+)
+
+// PageRendition describes a scaled rendition of a page, like a thumbnail or a
+// preview. A width of zero denotes the page's original width.
+type PageRendition struct {
+	Width  int
+	Format RenditionFormat
+}
+
+// ContentKey returns the content key for the rendition of the given page.
+func (r PageRendition) ContentKey(page DocumentPage) ContentKey {
+	return ContentKey(fmt.Sprintf(
+		"%s.w%d.%s",
+		page.Fingerprint,
+		r.Width,
+		strings.ToLower(string(r.Format)),
+	))
+}
+
+// DocumentPageRenderer defines the signature of a component being capable of
+// rendering scaled renditions of preprocessed document pages.
+type DocumentPageRenderer interface {
+	// Render returns the given rendition of a document's page. Renditions
+	// are rendered on first access and cached afterwards. Pages which have
+	// not been preprocessed yet are refused, as their content key is only
+	// temporary.
+	Render(documentNumber DocumentNumber, page *DocumentPage, rendition PageRendition) (io.ReadCloser, error)
+
+	// Invalidate removes all cached renditions of a document's page.
+	Invalidate(documentNumber DocumentNumber, page *DocumentPage) error
+}
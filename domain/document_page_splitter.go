@@ -0,0 +1,13 @@
+package domain
+
+import "io"
+
+// DocumentPageSplitter defines the signature of a component being capable of
+// splitting image content containing multiple pages, like multi-page TIFFs
+// produced by feeder scanners, into single pages.
+type DocumentPageSplitter interface {
+	// Split passes each page of the given content of the given size as a
+	// single page TIFF image to the given handler in page order. Content not
+	// being a valid image is reported as Error.
+	Split(content io.ReaderAt, size int64, handler func(page io.Reader) error) error
+}
@@ -14,3 +14,27 @@ func TestDocumentPageContentKey(t *testing.T) {
 
 	assert.Equal(t, page.ContentKey(), ContentKey("fingerprint.tiff"))
 }
+
+func TestPageRenditionContentKey(t *testing.T) {
+	page := DocumentPage{
+		Fingerprint: Fingerprint("fingerprint"),
+		Type:        PageTypeTIFF,
+	}
+
+	rendition := PageRendition{
+		Width:  256,
+		Format: RenditionFormatPNG,
+	}
+
+	assert.Equal(t, rendition.ContentKey(page), ContentKey("fingerprint.w256.png"))
+}
+
+func TestPageOriginalContentKey(t *testing.T) {
+	original := PageOriginal{
+		Fingerprint: Fingerprint("fingerprint"),
+		ContentType: "image/png",
+		FileName:    "scan.png",
+	}
+
+	assert.Equal(t, original.ContentKey(), ContentKey("fingerprint.original"))
+}
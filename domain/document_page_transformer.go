@@ -0,0 +1,23 @@
+package domain
+
+// PageRotation represents the clockwise rotation of a page in degrees.
+type PageRotation int
+
+const (
+	// PageRotation90 rotates a page by 90 degrees clockwise.
+	PageRotation90 = PageRotation(90)
+
+	// PageRotation180 rotates a page by 180 degrees.
+	PageRotation180 = PageRotation(180)
+
+	// PageRotation270 rotates a page by 270 degrees clockwise.
+	PageRotation270 = PageRotation(270)
+)
+
+// DocumentPageTransformer defines the signature of a component being capable
+// of transforming the content of already preprocessed document pages.
+type DocumentPageTransformer interface {
+	// RotatePage rotates the content of a document's page clockwise by the
+	// given rotation.
+	RotatePage(documentNumber DocumentNumber, pageNumber PageNumber, rotation PageRotation) error
+}
@@ -5,4 +5,8 @@ package domain
 type DocumentPreprocessor interface {
 	// PreprocessPage applies preprocessing to a document's page.
 	PreprocessPage(documentNumber DocumentNumber, pageNumber PageNumber) error
+
+	// ComputePerceptualHash computes the perceptual hash of an already
+	// preprocessed document's page.
+	ComputePerceptualHash(documentNumber DocumentNumber, page *DocumentPage) (PerceptualHash, error)
 }
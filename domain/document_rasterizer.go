@@ -0,0 +1,12 @@
+package domain
+
+import "io"
+
+// DocumentRasterizer defines the signature of a component being capable of
+// rendering multi-page documents like PDFs into single page images.
+type DocumentRasterizer interface {
+	// Rasterize renders each page of the given document content as a TIFF
+	// image and passes it to the given handler in page order. Content not
+	// being a valid document is reported as Error.
+	Rasterize(content io.Reader, handler func(page io.Reader) error) error
+}
@@ -1,18 +1,27 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"io"
+	"strings"
 
 	"github.com/concepts-system/go-paperless/common"
 )
 
 const (
-	mailboxDocumentIndex = Mailbox("document.index")
+	mailboxDocumentIndex    = Mailbox("document.index")
+	mailboxDocumentGenerate = Mailbox("document.generate")
 
 	mailboxPagePreprocess = Mailbox("document.page.preprocess")
 	malboxPageAnalyze     = Mailbox("document.page.analyze")
 )
 
+// generatedContentKey defines the content key used for storing generated
+// content until its fingerprint is known.
+const generatedContentKey = ContentKey("generated.tmp")
+
 var log = common.NewLogger("registry")
 
 // DocumentRegistry provides an abstraction for document components taking
@@ -30,6 +39,9 @@ type documentRegistryImpl struct {
 	preprocessor DocumentPreprocessor
 	index        DocumentIndex
 	analyzer     DocumentAnalyzer
+	detector     LanguageDetector
+	generator    DocumentGenerator
+	archive      DocumentArchive
 }
 
 func NewDocumentRegistry(
@@ -37,7 +49,10 @@ func NewDocumentRegistry(
 	documents Documents,
 	preprocessor DocumentPreprocessor,
 	analyzer DocumentAnalyzer,
+	detector LanguageDetector,
 	index DocumentIndex,
+	generator DocumentGenerator,
+	archive DocumentArchive,
 ) DocumentRegistry {
 	registry := &documentRegistryImpl{
 		tubeMail,
@@ -45,6 +60,9 @@ func NewDocumentRegistry(
 		preprocessor,
 		index,
 		analyzer,
+		detector,
+		generator,
+		archive,
 	}
 
 	registry.setupTubeMail()
@@ -59,6 +77,11 @@ func (d documentRegistryImpl) Review(documentNumber DocumentNumber) {
 		return
 	}
 
+	if document == nil {
+		log.Infof("Document %d does not exist or has been moved to the trash; skipping", documentNumber)
+		return
+	}
+
 	document, err = d.startDocumentReview(document)
 	if err != nil {
 		log.Error(err)
@@ -78,6 +101,17 @@ func (d documentRegistryImpl) Review(documentNumber DocumentNumber) {
 		log.Debug("Document has been edited since last review; reviewing pages")
 		d.reviewDocumentPages(document)
 		_, err = d.finishDocumentReview(documentNumber, DocumentStateEdited)
+	case DocumentStateIndexed:
+		if len(document.Pages) == 0 {
+			log.Debug("Document is indexed but has no pages; nothing to generate")
+			_, err = d.finishDocumentReview(documentNumber, DocumentStateEmpty)
+		} else {
+			log.Debug("Document is indexed; sending to generation")
+			err = d.tubeMail.SendMessage(mailboxDocumentGenerate, documentNumber)
+		}
+	case DocumentStateProcessed:
+		log.Debug("Document is already processed; nothing to do")
+		_, err = d.finishDocumentReview(documentNumber, DocumentStateProcessed)
 	case DocumentStateArchived:
 		log.Debug("Document is already archived; nothing to do")
 		_, err = d.finishDocumentReview(documentNumber, DocumentStateArchived)
@@ -129,6 +163,9 @@ func (d documentRegistryImpl) reviewDocumentPage(documentNumber DocumentNumber,
 	case PageStatePreprocessed:
 		log.Debug("Page is preprocessed; sending to scanning")
 		err = d.tubeMail.SendMessage(malboxPageAnalyze, documentNumber, page.PageNumber)
+	case PageStateAnalyzed:
+		log.Debug("Page is already analyzed; nothing to do")
+		_, err = d.finishPageReview(documentNumber, pageNumber, page.State)
 	default:
 		log.Warnf("Document pages in state %s are not handled yet!", page.State)
 		_, err = d.finishPageReview(documentNumber, pageNumber, page.State)
@@ -142,6 +179,7 @@ func (d documentRegistryImpl) reviewDocumentPage(documentNumber DocumentNumber,
 func (d documentRegistryImpl) setupTubeMail() {
 	// Document-specific receivers
 	d.registerDocumentReceiver(mailboxDocumentIndex, d.indexDocument)
+	d.registerDocumentReceiver(mailboxDocumentGenerate, d.generateDocument)
 
 	// Page-specific receivers
 	d.registerDocumentPageReceiver(mailboxPagePreprocess, d.preprocessPage)
@@ -151,6 +189,10 @@ func (d documentRegistryImpl) setupTubeMail() {
 /* Handlers */
 
 func (d documentRegistryImpl) indexDocument(documentNumber DocumentNumber) error {
+	if err := d.detectDocumentLanguage(documentNumber); err != nil {
+		return err
+	}
+
 	if err := d.index.IndexDocument(documentNumber); err != nil {
 		return err
 	}
@@ -163,6 +205,67 @@ func (d documentRegistryImpl) indexDocument(documentNumber DocumentNumber) error
 	return nil
 }
 
+func (d documentRegistryImpl) generateDocument(documentNumber DocumentNumber) error {
+	document, err := d.documents.GetByDocumentNumber(documentNumber)
+	if err != nil {
+		return err
+	}
+
+	if document == nil {
+		log.Infof("Document %d has been moved to the trash; skipping generation", documentNumber)
+		return nil
+	}
+
+	content, err := d.generator.Generate(document)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	hasher := sha256.New()
+	err = d.archive.StoreContent(documentNumber, generatedContentKey, io.TeeReader(content, hasher))
+	if err != nil {
+		return err
+	}
+
+	var oldContentKey ContentKey
+	if document.Fingerprint != "" {
+		oldContentKey = document.ContentKey()
+	}
+
+	document.Fingerprint = Fingerprint(hex.EncodeToString(hasher.Sum(nil)))
+	document.Type = DocumentTypePDF
+	if err := d.archive.MoveContent(documentNumber, generatedContentKey, document.ContentKey()); err != nil {
+		return err
+	}
+
+	// Only the content is updated, as the document may have been edited or
+	// moved to the trash while being generated.
+	if err := d.documents.UpdateContent(document); err != nil {
+		if oldContentKey != document.ContentKey() {
+			if err := d.archive.DeleteContent(documentNumber, document.ContentKey()); err != nil {
+				log.Warnf("Failed to delete generated content of document %d: %v", documentNumber, err)
+			}
+		}
+
+		if current, _ := d.documents.GetByDocumentNumber(documentNumber); current == nil {
+			log.Infof("Document %d has been moved to the trash during generation", documentNumber)
+			return nil
+		}
+
+		return err
+	}
+
+	if oldContentKey != "" && oldContentKey != document.ContentKey() {
+		if err := d.archive.DeleteContent(documentNumber, oldContentKey); err != nil {
+			log.Warnf("Failed to delete outdated content of document %d: %v", documentNumber, err)
+		}
+	}
+
+	_, err = d.finishDocumentReview(documentNumber, DocumentStateProcessed)
+	return err
+}
+
 func (d documentRegistryImpl) preprocessPage(
 	documentNumber DocumentNumber,
 	pageNumber PageNumber,
@@ -197,6 +300,34 @@ func (d documentRegistryImpl) analyzePage(
 
 /* Helper Methods */
 
+// detectDocumentLanguage detects the language of the document with the given
+// document number based on its title and the text of its pages, as the index
+// analyzes documents depending on their language.
+func (d documentRegistryImpl) detectDocumentLanguage(documentNumber DocumentNumber) error {
+	document, err := d.documents.GetByDocumentNumber(documentNumber)
+	if err != nil {
+		return err
+	}
+
+	if document == nil {
+		return nil
+	}
+
+	texts := []string{string(document.Title)}
+	for _, page := range document.Pages {
+		texts = append(texts, string(page.Text))
+	}
+
+	language := d.detector.DetectLanguage(Text(strings.Join(texts, "\n")))
+	if language == document.Language {
+		return nil
+	}
+
+	log.Debugf("Detected language '%s' for document %d", language, documentNumber)
+	document.Language = language
+	return d.documents.UpdateLanguage(document)
+}
+
 func (d documentRegistryImpl) registerDocumentReceiver(
 	mailbox Mailbox,
 	handler func(DocumentNumber) error,
@@ -275,9 +406,15 @@ func (d documentRegistryImpl) startDocumentReview(document *Document) (*Document
 func (d documentRegistryImpl) finishDocumentReview(documentNumber DocumentNumber, state DocumentState) (*Document, error) {
 	document, err := d.documents.GetByDocumentNumber(documentNumber)
 	if err != nil {
-		log.Error(err)
+		return nil, err
+	}
+
+	if document == nil {
+		log.Infof("Document %d has been moved to the trash during review", documentNumber)
+		return nil, nil
 	}
 
+	stateChanged := document.State != state
 	document.IsInReview = false
 	document.State = state
 	document, err = d.documents.Update(document)
@@ -285,6 +422,13 @@ func (d documentRegistryImpl) finishDocumentReview(documentNumber DocumentNumber
 		return nil, err
 	}
 
+	// The document's state is part of its index entry.
+	if stateChanged {
+		if err := d.index.IndexDocument(documentNumber); err != nil {
+			return nil, err
+		}
+	}
+
 	return document, nil
 }
 
@@ -306,7 +450,12 @@ func (d documentRegistryImpl) startPageReview(documentNumber DocumentNumber, pag
 func (d documentRegistryImpl) finishPageReview(documentNumber DocumentNumber, pageNumber PageNumber, state PageState) (*DocumentPage, error) {
 	page, err := d.documents.GetPageByDocumentNumberAndPageNumber(documentNumber, pageNumber)
 	if err != nil {
-		log.Error(err)
+		return nil, err
+	}
+
+	if page == nil {
+		log.Infof("Page %d of document %d has been deleted during review", pageNumber, documentNumber)
+		return nil, nil
 	}
 
 	if !page.IsInReview {
@@ -1,7 +1,112 @@
 package domain
 
+// SearchHighlightStyle represents the type of the style used for
+// highlighting matched terms in search results.
+type SearchHighlightStyle string
+
+const (
+	// SearchHighlightStyleNone disables highlighting.
+	SearchHighlightStyleNone SearchHighlightStyle = ""
+	// SearchHighlightStyleHTML marks matched terms using HTML <mark> tags
+	// within HTML-escaped fragments.
+	SearchHighlightStyleHTML SearchHighlightStyle = "html"
+	// SearchHighlightStyleText returns fragments as plain text without marks.
+	SearchHighlightStyleText SearchHighlightStyle = "text"
+)
+
+// DocumentFacet represents the type of a facet documents can be counted by.
+type DocumentFacet string
+
+const (
+	// DocumentFacetYear counts documents by the year of their date.
+	DocumentFacetYear DocumentFacet = "year"
+	// DocumentFacetMonth counts documents by the year and month of their date.
+	DocumentFacetMonth DocumentFacet = "month"
+	// DocumentFacetState counts documents by their state.
+	DocumentFacetState DocumentFacet = "state"
+	// DocumentFacetPageCount counts documents by ranges of their page count.
+	DocumentFacetPageCount DocumentFacet = "pageCount"
+	// DocumentFacetTags counts documents by the IDs of their tags.
+	DocumentFacetTags DocumentFacet = "tags"
+	// DocumentFacetClass counts documents by the ID of their class.
+	DocumentFacetClass DocumentFacet = "class"
+	// DocumentFacetCorrespondent counts documents by the ID of their
+	// correspondent.
+	DocumentFacetCorrespondent DocumentFacet = "correspondent"
+	// DocumentFacetType counts documents by their type.
+	DocumentFacetType DocumentFacet = "type"
+)
+
+// DocumentAccess represents the set of documents a search is restricted to,
+// i.e. the documents owned by a user and those shared with them.
+type DocumentAccess struct {
+	// Owner is the username of the user whose documents are accessible.
+	Owner Name
+	// DocumentNumbers lists further accessible documents, regardless of their
+	// owner.
+	DocumentNumbers []DocumentNumber
+}
+
+// DocumentSearchRequest represents a search for documents.
+type DocumentSearchRequest struct {
+	// Query is the query string documents have to match. Empty queries match
+	// any document.
+	Query     string
+	Filter    DocumentFilter
+	Highlight SearchHighlightStyle
+	// Facets lists the facets to count the matching documents by.
+	Facets []DocumentFacet
+	Page   PageRequest
+}
+
+// DocumentSearchResponse represents the outcome of a search for documents.
+type DocumentSearchResponse struct {
+	Results    []DocumentSearchResult
+	TotalCount Count
+	// Facets contains the counts for each of the requested facets.
+	Facets []DocumentFacetResult
+}
+
+// DocumentFacetResult represents the document counts for a single facet.
+type DocumentFacetResult struct {
+	Facet DocumentFacet
+	// Total denotes the number of documents having a value for the facet and
+	// Missing the number of documents not having one.
+	Total   int
+	Missing int
+	Terms   []DocumentFacetTerm
+}
+
+// DocumentFacetTerm represents the number of documents sharing a single
+// value of a facet.
+type DocumentFacetTerm struct {
+	Term  string
+	Count int
+}
+
+// DocumentSearchResult represents a document matching a search query.
 type DocumentSearchResult struct {
 	Document *Document
+	// Score denotes the relevance of the document with respect to the query.
+	Score float64
+	// TitleFragments and TextFragments contain the fragments of the document's
+	// title and page text the query matched on, if highlighting was requested.
+	TitleFragments []string
+	TextFragments  []string
+	// PageNumbers contains the numbers of the pages the query matched on.
+	PageNumbers []PageNumber
+}
+
+// DocumentSearchSuggestions represents suggestions for a search query being
+// typed by a user.
+type DocumentSearchSuggestions struct {
+	// Completions contains terms from the user's documents completing the
+	// query's last, possibly partial term, most frequent first.
+	Completions []string
+	// Correction contains the query with unknown terms replaced by similar
+	// terms from the user's documents. It is empty unless the query does not
+	// match any document and corrections have been found.
+	Correction string
 }
 
 // DocumentIndex abstracts all functionality required for indexing and searching document and pages.
@@ -12,6 +117,16 @@ type DocumentIndex interface {
 	// IndexDocument inserts or updates the index entry for the document with the given document number.
 	IndexDocument(documentNumber DocumentNumber) error
 
-	// Search returns all matching documents with respect to the given query.
-	Search(query string, pr PageRequest) ([]DocumentSearchResult, Count, error)
+	// DeleteDocument removes the index entry for the document with the given document number.
+	DeleteDocument(documentNumber DocumentNumber) error
+
+	// Search returns all documents within the given access scope matching the
+	// given search request, alongside with the requested facets.
+	Search(access DocumentAccess, request DocumentSearchRequest) (*DocumentSearchResponse, error)
+
+	// Suggest returns at most the given number of completions for the given
+	// query based on the documents owned by the user with the given username,
+	// as well as a corrected query in case the given one does not match any
+	// of them.
+	Suggest(username Name, query string, size int) (*DocumentSearchSuggestions, error)
 }
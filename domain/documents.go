@@ -1,6 +1,10 @@
 package domain
 
+import "time"
+
 // Documents defines an interface for managing the collection of all documents.
+// Documents moved to the trash are not part of the collection, unless stated
+// otherwise.
 type Documents interface {
 	// Find returns the a subset of all documents with respect to the given page request.
 	Find(pr PageRequest) ([]Document, Count, error)
@@ -9,9 +13,9 @@ type Documents interface {
 	FindByDocumentNumbers(documentNumbers ...DocumentNumber) ([]Document, error)
 
 	// FindByUsername returns the set of documents owned by the user
-	// with the given username, alongside with the total count with respect to the given
-	// page request.
-	FindByUsername(username Name, pr PageRequest) ([]Document, Count, error)
+	// with the given username and matching the given filter, alongside with the
+	// total count with respect to the given page request.
+	FindByUsername(username Name, filter DocumentFilter, pr PageRequest) ([]Document, Count, error)
 
 	// GetByDocumentNumber returns the document with the given document number
 	// or nil in case no such document exists.
@@ -20,9 +24,53 @@ type Documents interface {
 	// Add adds the given document without its pages.
 	Add(document *Document) (*Document, error)
 
-	// Update updates the given document without its pages.
+	// Update updates the given document without its pages, tags and custom
+	// field values.
 	Update(document *Document) (*Document, error)
 
+	// UpdateMetadata updates the title, date, class and correspondent of the
+	// given document, leaving all other attributes untouched.
+	UpdateMetadata(document *Document) error
+
+	// UpdateContent updates the fingerprint and type of the given document's
+	// generated content, leaving all other attributes untouched.
+	UpdateContent(document *Document) error
+
+	// UpdateLanguage updates the language of the given document, leaving all
+	// other attributes untouched.
+	UpdateLanguage(document *Document) error
+
+	// UpdateTags replaces the tags assigned to the given document by the
+	// document's tags.
+	UpdateTags(document *Document) error
+
+	// UpdateCustomFields replaces the custom field values of the given
+	// document by the document's custom field values.
+	UpdateCustomFields(document *Document) error
+
+	// Delete moves the given document including all its pages to the trash.
+	Delete(document *Document) error
+
+	// FindTrashedByUsername returns the set of documents in the trash owned by
+	// the user with the given username, alongside with the total count with
+	// respect to the given page request.
+	FindTrashedByUsername(username Name, pr PageRequest) ([]Document, Count, error)
+
+	// FindTrashedBefore returns all documents which have been moved to the
+	// trash before the given point in time.
+	FindTrashedBefore(deletedBefore time.Time) ([]Document, error)
+
+	// GetTrashedByDocumentNumber returns the document in the trash with the
+	// given document number or nil in case no such document exists.
+	GetTrashedByDocumentNumber(documentNumber DocumentNumber) (*Document, error)
+
+	// Restore restores the given document from the trash.
+	Restore(document *Document) error
+
+	// Purge permanently deletes the given document including all its pages,
+	// regardless of whether it is in the trash or not.
+	Purge(document *Document) error
+
 	// GetPagesByDocumentNumber returns all pages contained in the document for the given document number
 	// alongside the total count of pages with respect to the given page request.
 	GetPagesByDocumentNumber(documentNumber DocumentNumber, pr PageRequest) ([]DocumentPage, Count, error)
@@ -48,4 +96,63 @@ type Documents interface {
 		documentNumber DocumentNumber,
 		page *DocumentPage,
 	) (*DocumentPage, error)
+
+	// DeletePage deletes the page with the given page number from the
+	// document with the given document number. All subsequent pages are
+	// renumbered in order to close the resulting gap.
+	DeletePage(
+		documentNumber DocumentNumber,
+		pageNumber PageNumber,
+	) error
+
+	// ReorderPages renumbers the pages of the document with the given
+	// document number. The given page numbers have to contain all current
+	// page numbers of the document in their desired new order.
+	ReorderPages(
+		documentNumber DocumentNumber,
+		pageNumbers []PageNumber,
+	) error
+
+	// FindPagesByOriginalFingerprint returns all pages of documents owned by
+	// the user with the given username originating from the uploaded file
+	// with the given fingerprint.
+	FindPagesByOriginalFingerprint(username Name, fingerprint Fingerprint) ([]DocumentPage, error)
+
+	// FindPagesWithLegacyOriginalFingerprint returns all pages originating
+	// from an uploaded file identified by a random rather than a content
+	// fingerprint, including pages of documents in the trash.
+	FindPagesWithLegacyOriginalFingerprint() ([]DocumentPage, error)
+
+	// UpdateOriginalFingerprint replaces the given fingerprint of an uploaded
+	// file by the given new one for all pages of the document with the given
+	// document number originating from that file.
+	UpdateOriginalFingerprint(documentNumber DocumentNumber, fingerprint Fingerprint, newFingerprint Fingerprint) error
+
+	// FindPagesWithPerceptualHashByUsername returns all pages having a
+	// perceptual hash within the documents owned by the user with the given
+	// username.
+	FindPagesWithPerceptualHashByUsername(username Name) ([]DocumentPage, error)
+
+	// FindPagesWithoutPerceptualHash returns all preprocessed pages lacking a
+	// perceptual hash, including pages of documents in the trash.
+	FindPagesWithoutPerceptualHash() ([]DocumentPage, error)
+
+	// UpdatePagePerceptualHash sets the perceptual hash of the given page of
+	// the document with the given document number, unless the page's content
+	// changed in the meantime.
+	UpdatePagePerceptualHash(documentNumber DocumentNumber, page *DocumentPage, perceptualHash PerceptualHash) error
+
+	// FindDuplicatePagesByUsername returns all sets of pages sharing the same
+	// content fingerprint within the documents owned by the user with the
+	// given username.
+	FindDuplicatePagesByUsername(username Name) ([]PageDuplicates, error)
+
+	// MovePage moves the page with the given page number of the source
+	// document to the end of the target document. All subsequent pages of
+	// the source document are renumbered in order to close the resulting gap.
+	MovePage(
+		sourceDocumentNumber DocumentNumber,
+		pageNumber PageNumber,
+		targetDocumentNumber DocumentNumber,
+	) (*DocumentPage, error)
 }
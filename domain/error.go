@@ -1,10 +1,18 @@
 package domain
 
+import "fmt"
+
 // Error represents an error occurring in the domain logic.
 type Error struct {
 	message string
 }
 
+// NewErrorf creates a new domain error with the given message format and
+// arguments.
+func NewErrorf(message string, args ...interface{}) error {
+	return &Error{message: fmt.Sprintf(message, args...)}
+}
+
 func (err Error) Error() string {
 	return err.message
 }
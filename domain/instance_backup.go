@@ -0,0 +1,19 @@
+package domain
+
+import "io"
+
+// InstanceBackup defines an interface for backing up all users and documents
+// of an instance, including all stored content, and for restoring such a
+// backup onto another, fresh instance. The document index is not part of a
+// backup; it is rebuilt from the restored documents instead.
+type InstanceBackup interface {
+	// Backup writes a consistent backup of the whole instance to the given
+	// writer.
+	Backup(w io.Writer) error
+
+	// Restore restores the backup contained in the given content of the given
+	// size. Documents are renumbered as they are added. Restoring is refused if
+	// the instance already contains any users or documents. A failed restore
+	// is rolled back completely, so it may be retried.
+	Restore(content io.ReaderAt, size int64) error
+}
@@ -0,0 +1,9 @@
+package domain
+
+// LanguageDetector defines functionality for detecting the language of a
+// document's text.
+type LanguageDetector interface {
+	// DetectLanguage returns the language the given text is written in or
+	// DocumentLanguageUnknown in case it cannot be told.
+	DetectLanguage(text Text) DocumentLanguage
+}
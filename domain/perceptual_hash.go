@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// PerceptualHash represents a 64 bit hash of a page's visual content. Unlike
+// fingerprints, similar looking pages result in similar hashes.
+type PerceptualHash uint64
+
+// perceptualHashBits defines the number of bits of a perceptual hash.
+const perceptualHashBits = 64
+
+// Similarity returns the similarity of two perceptual hashes, ranging from 0
+// for completely different to 1 for identical hashes.
+func (h PerceptualHash) Similarity(other PerceptualHash) float64 {
+	distance := bits.OnesCount64(uint64(h ^ other))
+	return 1 - float64(distance)/perceptualHashBits
+}
+
+// SimilarPages represents a pair of pages looking alike.
+type SimilarPages struct {
+	Page       DocumentPage
+	Other      DocumentPage
+	Similarity float64
+}
+
+// SimilarDocuments represents a pair of documents containing alike pages.
+// The similarity denotes the share of both documents' pages having a look
+// alike within the other document.
+type SimilarDocuments struct {
+	Document   *Document
+	Other      *Document
+	Similarity float64
+}
+
+// FindSimilarPages returns all pairs of the given pages whose perceptual
+// hashes are at least as similar as the given threshold, most similar first.
+// Pages without perceptual hash are ignored.
+func FindSimilarPages(pages []DocumentPage, threshold float64) []SimilarPages {
+	similarPages := make([]SimilarPages, 0)
+
+	for i, page := range pages {
+		if page.PerceptualHash == nil {
+			continue
+		}
+
+		for _, other := range pages[i+1:] {
+			if other.PerceptualHash == nil {
+				continue
+			}
+
+			similarity := page.PerceptualHash.Similarity(*other.PerceptualHash)
+			if similarity >= threshold {
+				similarPages = append(similarPages, SimilarPages{page, other, similarity})
+			}
+		}
+	}
+
+	sort.SliceStable(similarPages, func(i, j int) bool {
+		return similarPages[i].Similarity > similarPages[j].Similarity
+	})
+
+	return similarPages
+}
+
+// FindSimilarDocuments aggregates the given pairs of similar pages into pairs
+// of similar documents, most similar first. The given page counts have to
+// contain the number of pages of each involved document.
+func FindSimilarDocuments(similarPages []SimilarPages, pageCounts map[DocumentNumber]int) []SimilarDocuments {
+	type documentPair struct {
+		document DocumentNumber
+		other    DocumentNumber
+	}
+
+	type pageKey struct {
+		document DocumentNumber
+		page     PageNumber
+	}
+
+	documents := make(map[DocumentNumber]*Document)
+	matchedPages := make(map[documentPair]map[pageKey]bool)
+	pairs := make([]documentPair, 0)
+
+	for _, similar := range similarPages {
+		if similar.Page.Document == nil || similar.Other.Document == nil {
+			continue
+		}
+
+		pair := documentPair{similar.Page.Document.DocumentNumber, similar.Other.Document.DocumentNumber}
+		if pair.document == pair.other {
+			continue
+		}
+
+		if pair.document > pair.other {
+			pair.document, pair.other = pair.other, pair.document
+		}
+
+		if matchedPages[pair] == nil {
+			matchedPages[pair] = make(map[pageKey]bool)
+			pairs = append(pairs, pair)
+		}
+
+		documents[similar.Page.Document.DocumentNumber] = similar.Page.Document
+		documents[similar.Other.Document.DocumentNumber] = similar.Other.Document
+		matchedPages[pair][pageKey{similar.Page.Document.DocumentNumber, similar.Page.PageNumber}] = true
+		matchedPages[pair][pageKey{similar.Other.Document.DocumentNumber, similar.Other.PageNumber}] = true
+	}
+
+	similarDocuments := make([]SimilarDocuments, 0, len(pairs))
+	for _, pair := range pairs {
+		totalPages := pageCounts[pair.document] + pageCounts[pair.other]
+		if totalPages == 0 {
+			continue
+		}
+
+		similarity := float64(len(matchedPages[pair])) / float64(totalPages)
+		if similarity > 1 {
+			similarity = 1
+		}
+
+		similarDocuments = append(similarDocuments, SimilarDocuments{
+			Document:   documents[pair.document],
+			Other:      documents[pair.other],
+			Similarity: similarity,
+		})
+	}
+
+	sort.SliceStable(similarDocuments, func(i, j int) bool {
+		return similarDocuments[i].Similarity > similarDocuments[j].Similarity
+	})
+
+	return similarDocuments
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPerceptualHashSimilarity(t *testing.T) {
+	hash := PerceptualHash(0xF0F0F0F0F0F0F0F0)
+
+	assert.Equal(t, 1.0, hash.Similarity(hash))
+	assert.Equal(t, 0.0, hash.Similarity(^hash))
+	assert.Equal(t, 1-2.0/64, hash.Similarity(hash^0x3))
+}
+
+func TestFindSimilarPagesAndDocuments(t *testing.T) {
+	hash := func(h PerceptualHash) *PerceptualHash { return &h }
+	first := &Document{DocumentNumber: 1}
+	second := &Document{DocumentNumber: 2}
+
+	pages := []DocumentPage{
+		{PageNumber: 1, Document: first, PerceptualHash: hash(0xFFFF)},
+		{PageNumber: 2, Document: first, PerceptualHash: hash(0xFFFF0000)},
+		{PageNumber: 1, Document: second, PerceptualHash: hash(0xFFFE)},
+		{PageNumber: 2, Document: second},
+	}
+
+	similarPages := FindSimilarPages(pages, 0.95)
+
+	assert.Len(t, similarPages, 1)
+	assert.Equal(t, PageNumber(1), similarPages[0].Page.PageNumber)
+	assert.Equal(t, second, similarPages[0].Other.Document)
+	assert.Equal(t, 1-1.0/64, similarPages[0].Similarity)
+
+	similarDocuments := FindSimilarDocuments(similarPages, map[DocumentNumber]int{1: 2, 2: 2})
+
+	assert.Len(t, similarDocuments, 1)
+	assert.Equal(t, first, similarDocuments[0].Document)
+	assert.Equal(t, second, similarDocuments[0].Other)
+	assert.Equal(t, 0.5, similarDocuments[0].Similarity)
+}
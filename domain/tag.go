@@ -0,0 +1,19 @@
+package domain
+
+type (
+	// TagID represents the type of a tag's unique identifier.
+	TagID uint
+
+	// Color represents the type of a color given as hex triplet, e.g. #ff0000.
+	Color string
+)
+
+// Tag represents a label users may assign to their documents for organizing
+// them.
+type Tag struct {
+	ID    TagID
+	Name  Name
+	Color Color
+
+	Owner *User
+}
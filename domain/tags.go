@@ -0,0 +1,29 @@
+package domain
+
+// Tags defines an interface for managing the collection of all tags.
+type Tags interface {
+	// FindByUsername returns the set of tags owned by the user with the given
+	// username, alongside with the total count with respect to the given page
+	// request.
+	FindByUsername(username Name, pr PageRequest) ([]Tag, Count, error)
+
+	// FindByIDs returns the subset of tags matching the given set of tag IDs.
+	FindByIDs(ids ...TagID) ([]Tag, error)
+
+	// GetByID returns the tag with the given ID or nil in case no such tag
+	// exists.
+	GetByID(id TagID) (*Tag, error)
+
+	// GetByUsernameAndName returns the tag with the given name owned by the
+	// user with the given username or nil in case no such tag exists.
+	GetByUsernameAndName(username Name, name Name) (*Tag, error)
+
+	// Add adds the given tag.
+	Add(tag *Tag) (*Tag, error)
+
+	// Update updates the given tag.
+	Update(tag *Tag) (*Tag, error)
+
+	// Delete deletes the given tag, removing it from all documents.
+	Delete(tag *Tag) error
+}
@@ -57,6 +57,17 @@ func Wrapf(err error, message string, args ...interface{}) error {
 	return wrappedErr
 }
 
+// Unwrap returns the error wrapped by the given error.
+func (err *genericError) Unwrap() error {
+	return err.cause
+}
+
+// As finds the first error in the given error's chain matching the given
+// target and, if so, sets the target to that error.
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
+
 // RootCause returns the root cause for the given error.
 // Might be the error itself in case not root cause could be determined.
 func RootCause(err error) error {
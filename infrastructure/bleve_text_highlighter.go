@@ -0,0 +1,38 @@
+package infrastructure
+
+import (
+	"github.com/blevesearch/bleve/v2/registry"
+	"github.com/blevesearch/bleve/v2/search/highlight"
+	simpleFragmenter "github.com/blevesearch/bleve/v2/search/highlight/fragmenter/simple"
+	simpleHighlighter "github.com/blevesearch/bleve/v2/search/highlight/highlighter/simple"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+// textHighlighterName is the name the plain text highlighter is registered
+// with at Bleve.
+const textHighlighterName = "paperless_text"
+
+// textFragmentFormatter formats fragments as plain text, leaving matched
+// terms unmarked.
+type textFragmentFormatter struct{}
+
+func (f *textFragmentFormatter) Format(fragment *highlight.Fragment, _ highlight.TermLocations) string {
+	return string(fragment.Orig[fragment.Start:fragment.End])
+}
+
+func newTextHighlighter(_ map[string]interface{}, cache *registry.Cache) (highlight.Highlighter, error) {
+	fragmenter, err := cache.FragmenterNamed(simpleFragmenter.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to build fragmenter")
+	}
+
+	return simpleHighlighter.NewHighlighter(
+		fragmenter,
+		&textFragmentFormatter{},
+		simpleHighlighter.DefaultSeparator,
+	), nil
+}
+
+func init() {
+	registry.RegisterHighlighter(textHighlighterName, newTextHighlighter)
+}
@@ -8,7 +8,13 @@ import (
 	"time"
 
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/standard"
+	"github.com/blevesearch/bleve/v2/analysis/lang/de"
+	"github.com/blevesearch/bleve/v2/analysis/lang/en"
 	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/highlight/highlighter/html"
 	"github.com/blevesearch/bleve/v2/search/query"
 	"github.com/concepts-system/go-paperless/common"
 	"github.com/concepts-system/go-paperless/domain"
@@ -16,7 +22,72 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-const indexBatchSize = 100
+const (
+	indexBatchSize = 100
+
+	titleField    = "Title"
+	pageTextField = "Pages.Text"
+
+	// indexMappingVersion has to be incremented whenever the index mapping
+	// changes, so existing indexes are recreated using the new mapping.
+	indexMappingVersion = "5"
+
+	// documentFacetSize limits the number of terms returned per facet.
+	documentFacetSize = 100
+
+	yearFormat  = "2006"
+	monthFormat = "2006-01"
+)
+
+// languageAnalyzers maps the supported document languages to the analyzers
+// their title and text are indexed with. Documents in other languages are
+// indexed using the standard analyzer.
+var languageAnalyzers = map[domain.DocumentLanguage]string{
+	domain.DocumentLanguageGerman:  de.AnalyzerName,
+	domain.DocumentLanguageEnglish: en.AnalyzerName,
+}
+
+// queryAnalyzers lists the analyzers queries on language-dependent fields are
+// analyzed with.
+var queryAnalyzers = []string{standard.Name, de.AnalyzerName, en.AnalyzerName}
+
+// languageFields contains the fields analyzed depending on the language of a
+// document, including the composite field queried by default.
+var languageFields = map[string]bool{
+	"":            true,
+	"_all":        true,
+	titleField:    true,
+	pageTextField: true,
+}
+
+// documentFacetFields maps facets to the indexed fields they are counted by.
+var documentFacetFields = map[domain.DocumentFacet]string{
+	domain.DocumentFacetYear:          "Year",
+	domain.DocumentFacetMonth:         "Month",
+	domain.DocumentFacetState:         "State",
+	domain.DocumentFacetPageCount:     "PageCount",
+	domain.DocumentFacetTags:          "TagIDs",
+	domain.DocumentFacetClass:         "ClassID",
+	domain.DocumentFacetCorrespondent: "CorrespondentID",
+	domain.DocumentFacetType:          "DocumentType",
+}
+
+// pageCountRanges defines the buckets documents are counted in by their page
+// count. Lower bounds are inclusive, upper bounds exclusive, and a zero upper
+// bound denotes an unbounded range.
+var pageCountRanges = []struct {
+	name     string
+	min, max float64
+}{
+	{"0", 0, 1},
+	{"1", 1, 2},
+	{"2-5", 2, 6},
+	{"6-10", 6, 11},
+	{"11-20", 11, 21},
+	{"21+", 21, 0},
+}
+
+var indexMappingVersionKey = []byte("mappingVersion")
 
 type indexer interface {
 	Index(id string, document interface{}) error
@@ -29,14 +100,28 @@ type bleveIndex struct {
 }
 
 type documentEntry struct {
-	DocumentNumber uint
-	OwnerUsername  string
-	Title          string
-	Date           *time.Time
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	PageCount      int
-	Pages          []pageEntry
+	DocumentNumber  uint
+	OwnerUsername   string
+	State           string
+	DocumentType    *string
+	Language        string
+	Class           string
+	ClassID         *string
+	Correspondent   string
+	CorrespondentID *string
+	Title           string
+	Date            *time.Time
+	Year            *string
+	Month           *string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	PageCount       int
+	Tags            []string
+	TagIDs          []string
+	CustomFields    map[string]interface{}
+	Pages           []pageEntry
+	CompletionTerms []string
+	SpellingTerms   []string
 }
 
 type pageEntry struct {
@@ -44,8 +129,10 @@ type pageEntry struct {
 	Text       string
 }
 
+// Type returns the name of the document mapping used for the entry, which
+// depends on the document's language.
 func (d *documentEntry) Type() string {
-	return "document"
+	return documentMappingName(domain.DocumentLanguage(d.Language))
 }
 
 func (p *pageEntry) Type() string {
@@ -110,66 +197,119 @@ func (b *bleveIndex) IndexDocument(documentNumber domain.DocumentNumber) error {
 		return err
 	}
 
+	// Trashed documents must not be found anymore.
+	if document == nil {
+		return b.DeleteDocument(documentNumber)
+	}
+
 	return b.indexDocument(*document, b.index)
 }
 
+func (b *bleveIndex) DeleteDocument(documentNumber domain.DocumentNumber) error {
+	if err := b.index.Delete(fmt.Sprint(documentNumber)); err != nil {
+		return errors.Wrapf(err, "Failed to delete document %d from index", documentNumber)
+	}
+
+	return nil
+}
+
 func (b *bleveIndex) Search(
-	queryString string,
-	page domain.PageRequest,
-) ([]domain.DocumentSearchResult, domain.Count, error) {
-	var query query.Query
-	if len(strings.TrimSpace(queryString)) > 0 {
-		query = bleve.NewQueryStringQuery(queryString)
-	} else {
-		query = bleve.NewMatchAllQuery()
+	access domain.DocumentAccess,
+	searchRequest domain.DocumentSearchRequest,
+) (*domain.DocumentSearchResponse, error) {
+	query, err := b.parseQuery(searchRequest.Query)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to search document index")
 	}
 
+	query = b.filterQuery(b.accessQuery(query, access), searchRequest.Filter)
+
 	request := bleve.NewSearchRequest(query)
-	request.From = page.Offset
-	request.Size = page.Size
-	if len(page.Sort) > 0 {
-		request.SortBy([]string{page.Sort})
+	request.From = searchRequest.Page.Offset
+	request.Size = searchRequest.Page.Size
+	request.IncludeLocations = true
+	request.Highlight = b.highlightRequest(searchRequest.Highlight)
+	if len(searchRequest.Page.Sort) > 0 {
+		request.SortBy([]string{searchRequest.Page.Sort})
+	}
+
+	for _, facet := range searchRequest.Facets {
+		facetRequest, err := b.facetRequest(facet)
+		if err != nil {
+			return nil, err
+		}
+
+		request.AddFacet(string(facet), facetRequest)
 	}
 
 	results, err := b.index.Search(request)
 	if err != nil {
-		return nil, 0, errors.Wrap(err, "Failed to search document index")
+		return nil, errors.Wrap(err, "Failed to search document index")
 	}
 
 	searchResults, err := b.mapDocumentSearchResults(results)
 	if err != nil {
-		return nil, -1, err
+		return nil, err
 	}
 
-	return searchResults, domain.Count(results.Total), nil
+	return &domain.DocumentSearchResponse{
+		Results:    searchResults,
+		TotalCount: domain.Count(results.Total),
+		Facets:     b.mapDocumentFacetResults(searchRequest.Facets, results.Facets),
+	}, nil
 }
 
 /* Helper Methods */
 
 func (b *bleveIndex) initializeDocumentIndex(path string) error {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		b.logger.Warnf("Index at '%s' not found, creating new index", path)
-		b.index, err = bleve.New(path, b.createIndexMapping())
+	if _, err := os.Stat(path); err == nil {
+		index, err := bleve.Open(path)
 		if err != nil {
 			return err
 		}
 
-		go func() {
-			err := b.IndexAllDocuments()
-			if err != nil {
-				b.logger.Error(err)
-			}
-		}()
+		version, err := index.GetInternal(indexMappingVersionKey)
+		if err != nil {
+			return err
+		}
 
-		return nil
+		if string(version) == indexMappingVersion {
+			b.index = index
+			return nil
+		}
+
+		b.logger.Warnf("Index at '%s' uses an outdated mapping, recreating index", path)
+		if err := index.Close(); err != nil {
+			return err
+		}
+
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	} else if os.IsNotExist(err) {
+		b.logger.Warnf("Index at '%s' not found, creating new index", path)
+	} else {
+		return err
 	}
 
-	index, err := bleve.Open(path)
+	index, err := bleve.New(path, b.createIndexMapping())
 	if err != nil {
 		return err
 	}
 
+	if err := index.SetInternal(indexMappingVersionKey, []byte(indexMappingVersion)); err != nil {
+		return err
+	}
+
 	b.index = index
+
+	go func() {
+		err := b.IndexAllDocuments()
+		if err != nil {
+			b.logger.Error(err)
+		}
+	}()
+
 	return nil
 }
 
@@ -177,31 +317,79 @@ func (b *bleveIndex) createIndexMapping() mapping.IndexMapping {
 	mapping := bleve.NewIndexMapping()
 	// mapping.DefaultDateTimeParser = time.RFC3339
 
-	mapping.AddDocumentMapping("document", b.createDocumentIndexMapping())
+	mapping.AddDocumentMapping(
+		documentMappingName(domain.DocumentLanguageUnknown),
+		b.createDocumentIndexMapping(standard.Name),
+	)
+
+	for language, analyzer := range languageAnalyzers {
+		mapping.AddDocumentMapping(documentMappingName(language), b.createDocumentIndexMapping(analyzer))
+	}
 
 	return mapping
 }
 
-func (b *bleveIndex) createDocumentIndexMapping() *mapping.DocumentMapping {
+// createDocumentIndexMapping creates the mapping for documents, analyzing
+// their title and text using the given analyzer.
+func (b *bleveIndex) createDocumentIndexMapping(analyzer string) *mapping.DocumentMapping {
 	mapping := bleve.NewDocumentMapping()
 
 	mapping.AddFieldMappingsAt("DocumentNumber", bleve.NewNumericFieldMapping())
-	mapping.AddFieldMappingsAt("OwnerUsername", bleve.NewTextFieldMapping())
-	mapping.AddFieldMappingsAt("Title", bleve.NewTextFieldMapping())
+	mapping.AddFieldMappingsAt("OwnerUsername", b.createKeywordFieldMapping())
+	mapping.AddFieldMappingsAt("State", b.createKeywordFieldMapping())
+	mapping.AddFieldMappingsAt("DocumentType", b.createKeywordFieldMapping())
+	mapping.AddFieldMappingsAt("Language", b.createKeywordFieldMapping())
+	mapping.AddFieldMappingsAt("Class", bleve.NewTextFieldMapping())
+	mapping.AddFieldMappingsAt("ClassID", b.createKeywordFieldMapping())
+	mapping.AddFieldMappingsAt("Correspondent", bleve.NewTextFieldMapping())
+	mapping.AddFieldMappingsAt("CorrespondentID", b.createKeywordFieldMapping())
+	mapping.AddFieldMappingsAt("Title", b.createLanguageFieldMapping(analyzer))
 	mapping.AddFieldMappingsAt("Date", bleve.NewDateTimeFieldMapping())
+	mapping.AddFieldMappingsAt("Year", b.createKeywordFieldMapping())
+	mapping.AddFieldMappingsAt("Month", b.createKeywordFieldMapping())
 	mapping.AddFieldMappingsAt("UpdatedAt", bleve.NewDateTimeFieldMapping())
 	mapping.AddFieldMappingsAt("CreatedAt", bleve.NewDateTimeFieldMapping())
 	mapping.AddFieldMappingsAt("PageCount", bleve.NewNumericFieldMapping())
-	mapping.AddSubDocumentMapping("Pages", b.createDocumentPageIndexMapping())
+	mapping.AddFieldMappingsAt("Tags", bleve.NewTextFieldMapping())
+	mapping.AddFieldMappingsAt("TagIDs", b.createKeywordFieldMapping())
+	mapping.AddSubDocumentMapping("CustomFields", b.createCustomFieldsIndexMapping())
+	mapping.AddSubDocumentMapping("Pages", b.createDocumentPageIndexMapping(analyzer))
+	mapping.AddFieldMappingsAt(completionTermsField, b.createSuggestionFieldMapping())
+	mapping.AddFieldMappingsAt(spellingTermsField, b.createSuggestionFieldMapping())
+
+	return mapping
+}
+
+// createCustomFieldsIndexMapping creates a dynamic mapping for the custom
+// fields, as their names are defined by the users. Fields are mapped by the
+// Go type of their values, so integers and monetary amounts are indexed as
+// numbers and dates as date times, allowing for range queries on them.
+func (b *bleveIndex) createCustomFieldsIndexMapping() *mapping.DocumentMapping {
+	mapping := bleve.NewDocumentMapping()
+	mapping.Dynamic = true
+
+	return mapping
+}
+
+func (b *bleveIndex) createKeywordFieldMapping() *mapping.FieldMapping {
+	mapping := bleve.NewTextFieldMapping()
+	mapping.Analyzer = keyword.Name
 
 	return mapping
 }
 
-func (b *bleveIndex) createDocumentPageIndexMapping() *mapping.DocumentMapping {
+func (b *bleveIndex) createLanguageFieldMapping(analyzer string) *mapping.FieldMapping {
+	mapping := bleve.NewTextFieldMapping()
+	mapping.Analyzer = analyzer
+
+	return mapping
+}
+
+func (b *bleveIndex) createDocumentPageIndexMapping(analyzer string) *mapping.DocumentMapping {
 	mapping := bleve.NewDocumentMapping()
 
 	mapping.AddFieldMappingsAt("PageNumber", bleve.NewNumericFieldMapping())
-	mapping.AddFieldMappingsAt("Text", bleve.NewTextFieldMapping())
+	mapping.AddFieldMappingsAt("Text", b.createLanguageFieldMapping(analyzer))
 
 	return mapping
 }
@@ -214,21 +402,96 @@ func (b *bleveIndex) documentEntry(document domain.Document) *documentEntry {
 	entry := documentEntry{
 		DocumentNumber: uint(document.DocumentNumber),
 		OwnerUsername:  string(document.Owner.Username),
+		State:          string(document.State),
+		Language:       string(document.Language),
 		Title:          string(document.Title),
 		Date:           document.Date,
 		CreatedAt:      document.CreatedAt,
 		UpdatedAt:      document.UpdatedAt,
 		PageCount:      len(document.Pages),
+		Tags:           make([]string, len(document.Tags)),
+		TagIDs:         make([]string, len(document.Tags)),
 		Pages:          make([]pageEntry, len(document.Pages)),
 	}
 
+	if document.Type != "" {
+		documentType := string(document.Type)
+		entry.DocumentType = &documentType
+	}
+
+	if document.Date != nil {
+		year := document.Date.Format(yearFormat)
+		month := document.Date.Format(monthFormat)
+		entry.Year = &year
+		entry.Month = &month
+	}
+
+	if document.Class != nil {
+		entry.Class = string(document.Class.Name)
+		classID := fmt.Sprint(document.Class.ID)
+		entry.ClassID = &classID
+	}
+
+	if document.Correspondent != nil {
+		entry.Correspondent = string(document.Correspondent.Name)
+		correspondentID := fmt.Sprint(document.Correspondent.ID)
+		entry.CorrespondentID = &correspondentID
+	}
+
+	for i, tag := range document.Tags {
+		entry.Tags[i] = string(tag.Name)
+		entry.TagIDs[i] = fmt.Sprint(tag.ID)
+	}
+
+	if len(document.CustomFields) > 0 {
+		entry.CustomFields = make(map[string]interface{}, len(document.CustomFields))
+	}
+
+	for _, customField := range document.CustomFields {
+		value, err := b.customFieldEntry(customField)
+		if err != nil {
+			b.logger.Warnf(
+				"Skipping invalid value of custom field %d of document %d: %s",
+				customField.Field.ID,
+				document.DocumentNumber,
+				err,
+			)
+
+			continue
+		}
+
+		entry.CustomFields[string(customField.Field.Name)] = value
+	}
+
 	for i, page := range document.Pages {
 		entry.Pages[i] = *b.documentPageEntry(page)
 	}
 
+	entry.CompletionTerms, entry.SpellingTerms = b.suggestionEntries(document)
+
 	return &entry
 }
 
+// customFieldEntry returns the value of the given custom field as indexed.
+// Integers are converted to floating point numbers, as Bleve maps numbers
+// to those anyway. Booleans are indexed as text, since the query syntax does
+// not support querying boolean fields.
+func (b *bleveIndex) customFieldEntry(customField domain.CustomFieldValue) (interface{}, error) {
+	value, err := customField.TypedValue()
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := value.(type) {
+	case int64:
+		return float64(value), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	default:
+		return value, nil
+	}
+}
+
 func (b *bleveIndex) documentPageEntry(page domain.DocumentPage) *pageEntry {
 	return &pageEntry{
 		PageNumber: uint(page.PageNumber),
@@ -236,6 +499,221 @@ func (b *bleveIndex) documentPageEntry(page domain.DocumentPage) *pageEntry {
 	}
 }
 
+// parseQuery parses the given query string. The query's terms are analyzed
+// using the analyzers of all supported languages, as the language a document
+// has been indexed with is not known in advance. Thus, the German "Rechnungen"
+// matches documents containing "Rechnung" as well. Empty query strings match
+// all documents.
+func (b *bleveIndex) parseQuery(queryString string) (query.Query, error) {
+	if len(strings.TrimSpace(queryString)) == 0 {
+		return bleve.NewMatchAllQuery(), nil
+	}
+
+	parsed, err := bleve.NewQueryStringQuery(queryString).Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.languageQuery(parsed), nil
+}
+
+// languageQuery replaces all match queries on language-dependent fields
+// within the given query by a disjunction of those queries analyzed by the
+// analyzers of each language.
+func (b *bleveIndex) languageQuery(q query.Query) query.Query {
+	switch q := q.(type) {
+	case *query.BooleanQuery:
+		if q.Must != nil {
+			q.Must = b.languageQuery(q.Must)
+		}
+
+		if q.Should != nil {
+			q.Should = b.languageQuery(q.Should)
+		}
+
+		if q.MustNot != nil {
+			q.MustNot = b.languageQuery(q.MustNot)
+		}
+	case *query.ConjunctionQuery:
+		for i, conjunct := range q.Conjuncts {
+			q.Conjuncts[i] = b.languageQuery(conjunct)
+		}
+	case *query.DisjunctionQuery:
+		for i, disjunct := range q.Disjuncts {
+			q.Disjuncts[i] = b.languageQuery(disjunct)
+		}
+	case *query.MatchQuery:
+		if q.Analyzer == "" && languageFields[q.FieldVal] {
+			return b.analyzerDisjunction(func(analyzer string) query.Query {
+				disjunct := *q
+				disjunct.Analyzer = analyzer
+				return &disjunct
+			})
+		}
+	case *query.MatchPhraseQuery:
+		if q.Analyzer == "" && languageFields[q.FieldVal] {
+			return b.analyzerDisjunction(func(analyzer string) query.Query {
+				disjunct := *q
+				disjunct.Analyzer = analyzer
+				return &disjunct
+			})
+		}
+	}
+
+	return q
+}
+
+// analyzerDisjunction returns a disjunction of the queries built for each of
+// the query analyzers.
+func (b *bleveIndex) analyzerDisjunction(build func(analyzer string) query.Query) query.Query {
+	disjuncts := make([]query.Query, len(queryAnalyzers))
+	for i, analyzer := range queryAnalyzers {
+		disjuncts[i] = build(analyzer)
+	}
+
+	return bleve.NewDisjunctionQuery(disjuncts...)
+}
+
+// accessQuery restricts the given query to documents within the given access
+// scope. The restriction is added as a separate query rather than being part
+// of the query string, so it cannot be bypassed using the query string syntax.
+func (b *bleveIndex) accessQuery(q query.Query, access domain.DocumentAccess) query.Query {
+	accessible := []query.Query{b.keywordQuery("OwnerUsername", string(access.Owner))}
+
+	if len(access.DocumentNumbers) > 0 {
+		ids := make([]string, len(access.DocumentNumbers))
+		for i, documentNumber := range access.DocumentNumbers {
+			ids[i] = fmt.Sprint(documentNumber)
+		}
+
+		accessible = append(accessible, bleve.NewDocIDQuery(ids))
+	}
+
+	return bleve.NewConjunctionQuery(q, bleve.NewDisjunctionQuery(accessible...))
+}
+
+// filterQuery restricts the given query to documents matching the given
+// filter. Documents have to be tagged with all of the filter's tags.
+func (b *bleveIndex) filterQuery(q query.Query, filter domain.DocumentFilter) query.Query {
+	conjuncts := []query.Query{q}
+
+	if filter.ClassID != nil {
+		conjuncts = append(conjuncts, b.keywordQuery("ClassID", fmt.Sprint(*filter.ClassID)))
+	}
+
+	if filter.CorrespondentID != nil {
+		conjuncts = append(conjuncts, b.keywordQuery("CorrespondentID", fmt.Sprint(*filter.CorrespondentID)))
+	}
+
+	for _, tagID := range filter.TagIDs {
+		conjuncts = append(conjuncts, b.keywordQuery("TagIDs", fmt.Sprint(tagID)))
+	}
+
+	if len(conjuncts) == 1 {
+		return q
+	}
+
+	return bleve.NewConjunctionQuery(conjuncts...)
+}
+
+func (b *bleveIndex) keywordQuery(field, keyword string) query.Query {
+	query := bleve.NewTermQuery(keyword)
+	query.SetField(field)
+
+	return query
+}
+
+// facetRequest returns the request for counting documents by the given facet.
+func (b *bleveIndex) facetRequest(facet domain.DocumentFacet) (*bleve.FacetRequest, error) {
+	field, ok := documentFacetFields[facet]
+	if !ok {
+		return nil, errors.Newf("Unknown document facet '%s'", facet)
+	}
+
+	request := bleve.NewFacetRequest(field, documentFacetSize)
+	if facet != domain.DocumentFacetPageCount {
+		return request, nil
+	}
+
+	for _, pageCountRange := range pageCountRanges {
+		min, max := pageCountRange.min, pageCountRange.max
+		if max == 0 {
+			request.AddNumericRange(pageCountRange.name, &min, nil)
+		} else {
+			request.AddNumericRange(pageCountRange.name, &min, &max)
+		}
+	}
+
+	return request, nil
+}
+
+// mapDocumentFacetResults maps the given Bleve facet results to the results
+// of the given facets in the same order. Terms are ordered by descending
+// count, except for page count ranges, which are kept in ascending order.
+func (b *bleveIndex) mapDocumentFacetResults(
+	facets []domain.DocumentFacet,
+	results search.FacetResults,
+) []domain.DocumentFacetResult {
+	facetResults := make([]domain.DocumentFacetResult, 0, len(facets))
+
+	for _, facet := range facets {
+		result, ok := results[string(facet)]
+		if !ok {
+			continue
+		}
+
+		facetResult := domain.DocumentFacetResult{
+			Facet:   facet,
+			Total:   result.Total,
+			Missing: result.Missing,
+			Terms:   make([]domain.DocumentFacetTerm, 0, len(result.Terms)),
+		}
+
+		for _, term := range result.Terms {
+			facetResult.Terms = append(facetResult.Terms, domain.DocumentFacetTerm{
+				Term:  term.Term,
+				Count: term.Count,
+			})
+		}
+
+		for _, pageCountRange := range pageCountRanges {
+			for _, numericRange := range result.NumericRanges {
+				if numericRange.Name == pageCountRange.name {
+					facetResult.Terms = append(facetResult.Terms, domain.DocumentFacetTerm{
+						Term:  numericRange.Name,
+						Count: numericRange.Count,
+					})
+				}
+			}
+		}
+
+		facetResults = append(facetResults, facetResult)
+	}
+
+	return facetResults
+}
+
+// highlightRequest returns the highlight request for the given style or nil
+// in case no highlighting is requested.
+func (b *bleveIndex) highlightRequest(style domain.SearchHighlightStyle) *bleve.HighlightRequest {
+	var request *bleve.HighlightRequest
+
+	switch style {
+	case domain.SearchHighlightStyleHTML:
+		request = bleve.NewHighlightWithStyle(html.Name)
+	case domain.SearchHighlightStyleText:
+		request = bleve.NewHighlightWithStyle(textHighlighterName)
+	default:
+		return nil
+	}
+
+	request.Fields = []string{titleField, pageTextField}
+	return request
+}
+
+// mapDocumentSearchResults maps the given search result's hits to document
+// search results, keeping the order of the hits. Hits for documents which
+// have been removed since being indexed are skipped.
 func (b *bleveIndex) mapDocumentSearchResults(result *bleve.SearchResult) ([]domain.DocumentSearchResult, error) {
 	count := len(result.Hits)
 	if count == 0 {
@@ -253,12 +731,69 @@ func (b *bleveIndex) mapDocumentSearchResults(result *bleve.SearchResult) ([]dom
 		return nil, err
 	}
 
-	results := make([]domain.DocumentSearchResult, len(result.Hits))
-	for i, document := range documents {
-		results[i] = domain.DocumentSearchResult{
-			Document: &document,
+	documentsByNumber := make(map[domain.DocumentNumber]domain.Document, len(documents))
+	for _, document := range documents {
+		documentsByNumber[document.DocumentNumber] = document
+	}
+
+	results := make([]domain.DocumentSearchResult, 0, len(result.Hits))
+	for i, hit := range result.Hits {
+		document, ok := documentsByNumber[documentNumbers[i]]
+		if !ok {
+			continue
 		}
+
+		results = append(results, domain.DocumentSearchResult{
+			Document:       &document,
+			Score:          hit.Score,
+			TitleFragments: b.hitFragments(hit, titleField),
+			TextFragments:  b.hitFragments(hit, pageTextField),
+			PageNumbers:    b.hitPageNumbers(hit, document),
+		})
 	}
 
 	return results, nil
 }
+
+// hitFragments returns the given hit's highlighted fragments of the given
+// field. Bleve falls back to the beginning of fields not containing any
+// matches, hence those fragments are omitted.
+func (b *bleveIndex) hitFragments(hit *search.DocumentMatch, field string) []string {
+	if len(hit.Locations[field]) == 0 {
+		return nil
+	}
+
+	return hit.Fragments[field]
+}
+
+// hitPageNumbers returns the numbers of the given document's pages the given
+// hit matched on in ascending order.
+func (b *bleveIndex) hitPageNumbers(hit *search.DocumentMatch, document domain.Document) []domain.PageNumber {
+	pageIndices := make(map[uint64]bool)
+	for _, locations := range hit.Locations[pageTextField] {
+		for _, location := range locations {
+			if len(location.ArrayPositions) > 0 {
+				pageIndices[location.ArrayPositions[0]] = true
+			}
+		}
+	}
+
+	pageNumbers := make([]domain.PageNumber, 0, len(pageIndices))
+	for i, page := range document.Pages {
+		if pageIndices[uint64(i)] {
+			pageNumbers = append(pageNumbers, page.PageNumber)
+		}
+	}
+
+	return pageNumbers
+}
+
+// documentMappingName returns the name of the mapping used for documents of
+// the given language.
+func documentMappingName(language domain.DocumentLanguage) string {
+	if _, ok := languageAnalyzers[language]; ok {
+		return "document_" + string(language)
+	}
+
+	return "document"
+}
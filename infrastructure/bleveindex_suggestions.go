@@ -0,0 +1,376 @@
+package infrastructure
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/analysis/char/asciifolding"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/v2/mapping"
+	index "github.com/blevesearch/bleve_index_api"
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+const (
+	completionTermsField = "CompletionTerms"
+	spellingTermsField   = "SpellingTerms"
+
+	// suggestionTermSeparator separates the parts of the terms indexed for
+	// suggestions. Terms are prefixed by their owner's username, so the term
+	// dictionaries can be scoped to a single user by prefix.
+	suggestionTermSeparator = "\x1f"
+
+	minSuggestionTermLength = 3
+	maxSuggestionTermLength = 40
+
+	// maxCompletionCandidates limits the number of dictionary entries taken
+	// into account for completing short prefixes.
+	maxCompletionCandidates = 1000
+)
+
+var (
+	suggestionTokenizer = unicode.NewUnicodeTokenizer()
+	suggestionFolding   = asciifolding.New()
+)
+
+// suggestionTerm represents a term of a text as typed (lower-cased) and in its
+// folded form used for matching, e.g. "prüfung" and "prufung".
+type suggestionTerm struct {
+	surface string
+	folded  string
+	start   int
+	end     int
+}
+
+type suggestionCandidate struct {
+	term     string
+	count    uint64
+	distance int
+}
+
+func (b *bleveIndex) Suggest(username domain.Name, query string, size int) (*domain.DocumentSearchSuggestions, error) {
+	owner := string(username)
+	terms := suggestionTerms(strings.ReplaceAll(query, ":", " "))
+	suggestions := &domain.DocumentSearchSuggestions{Completions: []string{}}
+
+	if len(terms) == 0 {
+		return suggestions, nil
+	}
+
+	// Only complete the last term in case it is still being typed.
+	if last := terms[len(terms)-1]; last.end == len(query) {
+		completions, err := b.completions(owner, last.folded, size)
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions.Completions = completions
+	}
+
+	correction, err := b.correction(owner, query, terms)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions.Correction = correction
+	return suggestions, nil
+}
+
+/* Helper Methods */
+
+func (b *bleveIndex) createSuggestionFieldMapping() *mapping.FieldMapping {
+	mapping := b.createKeywordFieldMapping()
+	mapping.Store = false
+	mapping.IncludeInAll = false
+	mapping.IncludeTermVectors = false
+	mapping.DocValues = false
+
+	return mapping
+}
+
+// suggestionEntries returns the terms of the given document to be indexed for
+// completions and spelling corrections respectively.
+func (b *bleveIndex) suggestionEntries(document domain.Document) ([]string, []string) {
+	owner := string(document.Owner.Username)
+	texts := []string{string(document.Title)}
+	for _, page := range document.Pages {
+		texts = append(texts, string(page.Text))
+	}
+
+	completionTerms := make(map[string]bool)
+	spellingTerms := make(map[string]bool)
+	for _, text := range texts {
+		for _, term := range suggestionTerms(text) {
+			if utf8.RuneCountInString(term.surface) < minSuggestionTermLength {
+				continue
+			}
+
+			completionTerms[completionTerm(owner, term.folded, term.surface)] = true
+			spellingTerms[spellingTerm(owner, term.folded)] = true
+		}
+	}
+
+	return setToSlice(completionTerms), setToSlice(spellingTerms)
+}
+
+// completions returns the most frequent terms of the given owner's documents
+// starting with the given folded prefix.
+func (b *bleveIndex) completions(owner, prefix string, size int) ([]string, error) {
+	dictionary, err := b.index.FieldDictPrefix(
+		completionTermsField,
+		[]byte(owner+suggestionTermSeparator+prefix),
+	)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read completion terms")
+	}
+
+	defer dictionary.Close()
+
+	counts := make(map[string]uint64)
+	for i := 0; i < maxCompletionCandidates; i++ {
+		entry, err := dictionary.Next()
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to read completion terms")
+		}
+
+		if entry == nil {
+			break
+		}
+
+		parts := strings.SplitN(entry.Term, suggestionTermSeparator, 3)
+		if len(parts) == 3 {
+			counts[parts[2]] += entry.Count
+		}
+	}
+
+	candidates := make([]suggestionCandidate, 0, len(counts))
+	for term, count := range counts {
+		candidates = append(candidates, suggestionCandidate{term: term, count: count})
+	}
+
+	sortSuggestionCandidates(candidates)
+
+	completions := make([]string, 0, size)
+	for i := 0; i < len(candidates) && i < size; i++ {
+		completions = append(completions, candidates[i].term)
+	}
+
+	return completions, nil
+}
+
+// correction returns the given query with all of its terms unknown to the
+// given owner's documents replaced by the most similar known terms. The
+// correction is empty in case the query matches any document, is
+// syntactically invalid or there is nothing to correct.
+func (b *bleveIndex) correction(owner, query string, terms []suggestionTerm) (string, error) {
+	parsedQuery, err := b.parseQuery(query)
+	if err != nil {
+		return "", nil
+	}
+
+	request := bleve.NewSearchRequestOptions(b.accessQuery(parsedQuery, domain.DocumentAccess{Owner: domain.Name(owner)}), 0, 0, false)
+	result, err := b.index.Search(request)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to search document index")
+	}
+
+	if result.Total > 0 {
+		return "", nil
+	}
+
+	advanced, err := b.index.Advanced()
+	if err != nil {
+		return "", err
+	}
+
+	reader, err := advanced.Reader()
+	if err != nil {
+		return "", err
+	}
+
+	defer reader.Close()
+
+	fuzzyReader, ok := reader.(index.IndexReaderFuzzy)
+	if !ok {
+		return "", nil
+	}
+
+	corrected := false
+	correction := query
+	for i := len(terms) - 1; i >= 0; i-- {
+		term := terms[i]
+		if utf8.RuneCountInString(term.folded) < minSuggestionTermLength {
+			continue
+		}
+
+		replacement, err := b.correctTerm(fuzzyReader, owner, term)
+		if err != nil {
+			return "", err
+		}
+
+		if replacement != "" {
+			correction = correction[:term.start] + replacement + correction[term.end:]
+			corrected = true
+		}
+	}
+
+	if !corrected {
+		return "", nil
+	}
+
+	return correction, nil
+}
+
+// correctTerm returns the most similar term to the given one known to the
+// given owner's documents or an empty string, in case the term is known as
+// typed or no similar term exists. Terms only differing by diacritics are
+// corrected to their most frequent spelling.
+func (b *bleveIndex) correctTerm(reader index.IndexReaderFuzzy, owner string, term suggestionTerm) (string, error) {
+	fuzziness := 2
+	if utf8.RuneCountInString(term.folded) <= 5 {
+		fuzziness = 1
+	}
+
+	prefix := owner + suggestionTermSeparator
+	dictionary, err := reader.FieldDictFuzzy(spellingTermsField, prefix+term.folded, fuzziness, prefix)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to read spelling terms")
+	}
+
+	defer dictionary.Close()
+
+	candidates := make([]suggestionCandidate, 0)
+	for {
+		entry, err := dictionary.Next()
+		if err != nil {
+			return "", errors.Wrap(err, "Failed to read spelling terms")
+		}
+
+		if entry == nil {
+			break
+		}
+
+		folded := strings.TrimPrefix(entry.Term, prefix)
+		distance := levenshteinDistance(term.folded, folded)
+		candidates = append(candidates, suggestionCandidate{term: folded, count: entry.Count, distance: distance})
+	}
+
+	if len(candidates) == 0 {
+		return "", nil
+	}
+
+	sortSuggestionCandidates(candidates)
+
+	// Correct to the most frequent spelling of the folded term.
+	completions, err := b.completions(owner, candidates[0].term+suggestionTermSeparator, 1)
+	if err != nil {
+		return "", err
+	}
+
+	if len(completions) == 0 || completions[0] == term.surface {
+		return "", nil
+	}
+
+	return completions[0], nil
+}
+
+/* Helper Functions */
+
+// suggestionTerms splits the given text into lower-cased terms containing
+// letters.
+func suggestionTerms(text string) []suggestionTerm {
+	tokens := suggestionTokenizer.Tokenize([]byte(text))
+	terms := make([]suggestionTerm, 0, len(tokens))
+
+	for _, token := range tokens {
+		if token.Type != analysis.AlphaNumeric || utf8.RuneCount(token.Term) > maxSuggestionTermLength {
+			continue
+		}
+
+		surface := strings.ToLower(string(token.Term))
+		terms = append(terms, suggestionTerm{
+			surface: surface,
+			folded:  string(suggestionFolding.Filter([]byte(surface))),
+			start:   token.Start,
+			end:     token.End,
+		})
+	}
+
+	return terms
+}
+
+func completionTerm(owner, folded, surface string) string {
+	return owner + suggestionTermSeparator + folded + suggestionTermSeparator + surface
+}
+
+func spellingTerm(owner, folded string) string {
+	return owner + suggestionTermSeparator + folded
+}
+
+// sortSuggestionCandidates sorts the given candidates by ascending distance,
+// descending frequency and alphabetically.
+func sortSuggestionCandidates(candidates []suggestionCandidate) {
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+
+		if candidates[i].count != candidates[j].count {
+			return candidates[i].count > candidates[j].count
+		}
+
+		return candidates[i].term < candidates[j].term
+	})
+}
+
+func levenshteinDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, value := range values[1:] {
+		if value < min {
+			min = value
+		}
+	}
+
+	return min
+}
+
+func setToSlice(set map[string]bool) []string {
+	values := make([]string, 0, len(set))
+	for value := range set {
+		values = append(values, value)
+	}
+
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,359 @@
+package infrastructure
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/concepts-system/go-paperless/common"
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+type documentsStub struct {
+	domain.Documents
+	documents map[domain.DocumentNumber]domain.Document
+}
+
+func (d documentsStub) FindByDocumentNumbers(documentNumbers ...domain.DocumentNumber) ([]domain.Document, error) {
+	documents := make([]domain.Document, 0, len(documentNumbers))
+	for _, documentNumber := range documentNumbers {
+		documents = append(documents, d.documents[documentNumber])
+	}
+
+	return documents, nil
+}
+
+func newTestBleveIndex(t *testing.T, documents ...domain.Document) *bleveIndex {
+	b := &bleveIndex{
+		logger:    common.NewLogger("bleve-index"),
+		documents: documentsStub{documents: make(map[domain.DocumentNumber]domain.Document)},
+	}
+
+	index, err := bleve.New(filepath.Join(t.TempDir(), "index.bleve"), b.createIndexMapping())
+	assert.Nil(t, err)
+	b.index = index
+	t.Cleanup(func() { index.Close() })
+
+	for _, document := range documents {
+		b.documents.(documentsStub).documents[document.DocumentNumber] = document
+		assert.Nil(t, b.indexDocument(document, b.index))
+	}
+
+	return b
+}
+
+func TestBleveIndex_Search_OnlyReturnsOwnedDocuments(t *testing.T) {
+	index := newTestBleveIndex(t,
+		domain.Document{DocumentNumber: 1, Title: "Invoice", Owner: &domain.User{Username: "alice"}},
+		domain.Document{DocumentNumber: 2, Title: "Invoice", Owner: &domain.User{Username: "bob"}},
+		domain.Document{DocumentNumber: 3, Title: "Invoice", Owner: &domain.User{Username: "Bob"}},
+	)
+
+	tests := []struct {
+		username domain.Name
+		query    string
+		expected []domain.DocumentNumber
+	}{
+		{"alice", "", []domain.DocumentNumber{1}},
+		{"alice", "invoice", []domain.DocumentNumber{1}},
+		{"bob", "invoice", []domain.DocumentNumber{2}},
+		{"Bob", "invoice", []domain.DocumentNumber{3}},
+		{"carol", "invoice", []domain.DocumentNumber{}},
+		{"alice", "OwnerUsername:bob", []domain.DocumentNumber{}},
+		{"alice", "invoice OwnerUsername:bob", []domain.DocumentNumber{1}},
+		{"alice", "+OwnerUsername:bob", []domain.DocumentNumber{}},
+		{"alice", "-OwnerUsername:alice", []domain.DocumentNumber{}},
+		{"alice", "invoice -OwnerUsername:alice", []domain.DocumentNumber{}},
+	}
+
+	for _, test := range tests {
+		response, err := index.Search(domain.DocumentAccess{Owner: test.username}, domain.DocumentSearchRequest{
+			Query: test.query,
+			Page:  domain.PageRequest{Size: 10},
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Count(len(test.expected)), response.TotalCount, "%s: %s", test.username, test.query)
+
+		documentNumbers := make([]domain.DocumentNumber, len(response.Results))
+		for i, result := range response.Results {
+			documentNumbers[i] = result.Document.DocumentNumber
+		}
+
+		assert.Equal(t, test.expected, documentNumbers, "%s: %s", test.username, test.query)
+	}
+}
+
+func TestBleveIndex_Search_ReturnsDocumentsWithinAccessScope(t *testing.T) {
+	index := newTestBleveIndex(t,
+		domain.Document{DocumentNumber: 1, Title: "Invoice", Owner: &domain.User{Username: "alice"}},
+		domain.Document{DocumentNumber: 2, Title: "Invoice", Owner: &domain.User{Username: "bob"}},
+		domain.Document{DocumentNumber: 3, Title: "Letter", Owner: &domain.User{Username: "bob"}},
+		domain.Document{DocumentNumber: 4, Title: "Invoice", Owner: &domain.User{Username: "carol"}},
+	)
+
+	tests := []struct {
+		access   domain.DocumentAccess
+		query    string
+		expected []domain.DocumentNumber
+	}{
+		{domain.DocumentAccess{Owner: "alice", DocumentNumbers: []domain.DocumentNumber{2, 3}}, "", []domain.DocumentNumber{1, 2, 3}},
+		{domain.DocumentAccess{Owner: "alice", DocumentNumbers: []domain.DocumentNumber{2, 3}}, "invoice", []domain.DocumentNumber{1, 2}},
+		{domain.DocumentAccess{Owner: "alice", DocumentNumbers: []domain.DocumentNumber{3}}, "invoice", []domain.DocumentNumber{1}},
+		{domain.DocumentAccess{Owner: "alice", DocumentNumbers: []domain.DocumentNumber{5}}, "", []domain.DocumentNumber{1}},
+		{domain.DocumentAccess{DocumentNumbers: []domain.DocumentNumber{4}}, "", []domain.DocumentNumber{4}},
+		{domain.DocumentAccess{Owner: "alice", DocumentNumbers: []domain.DocumentNumber{3}}, "OwnerUsername:carol", []domain.DocumentNumber{}},
+	}
+
+	for _, test := range tests {
+		response, err := index.Search(test.access, domain.DocumentSearchRequest{
+			Query: test.query,
+			Page:  domain.PageRequest{Size: 10, Sort: "_id"},
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Count(len(test.expected)), response.TotalCount, "%v: %s", test.access, test.query)
+
+		documentNumbers := make([]domain.DocumentNumber, len(response.Results))
+		for i, result := range response.Results {
+			documentNumbers[i] = result.Document.DocumentNumber
+		}
+
+		assert.Equal(t, test.expected, documentNumbers, "%v: %s", test.access, test.query)
+	}
+}
+
+func TestBleveIndex_Search_HighlightsMatches(t *testing.T) {
+	owner := &domain.User{Username: "alice"}
+	index := newTestBleveIndex(t,
+		domain.Document{DocumentNumber: 1, Title: "Letter", Owner: owner, Pages: []domain.DocumentPage{
+			{PageNumber: 1, Text: "Dear customer"},
+			{PageNumber: 2, Text: "please pay the <b>invoice</b>"},
+		}},
+		domain.Document{DocumentNumber: 2, Title: "Invoice", Owner: owner, Pages: []domain.DocumentPage{
+			{PageNumber: 1, Text: "Invoice"},
+			{PageNumber: 2, Text: "Terms"},
+			{PageNumber: 3, Text: "Invoice details"},
+		}},
+	)
+
+	response, err := index.Search(domain.DocumentAccess{Owner: "alice"}, domain.DocumentSearchRequest{
+		Query:     "invoice",
+		Highlight: domain.SearchHighlightStyleHTML,
+		Page:      domain.PageRequest{Size: 10},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Count(2), response.TotalCount)
+
+	results := response.Results
+	assert.Len(t, results, 2)
+
+	assert.Equal(t, domain.DocumentNumber(2), results[0].Document.DocumentNumber)
+	assert.Equal(t, []string{"<mark>Invoice</mark>"}, results[0].TitleFragments)
+	assert.Equal(t, []domain.PageNumber{1, 3}, results[0].PageNumbers)
+
+	assert.Equal(t, domain.DocumentNumber(1), results[1].Document.DocumentNumber)
+	assert.Empty(t, results[1].TitleFragments)
+	assert.Equal(t, []string{"please pay the &lt;b&gt;<mark>invoice</mark>&lt;/b&gt;"}, results[1].TextFragments)
+	assert.Equal(t, []domain.PageNumber{2}, results[1].PageNumbers)
+	assert.Greater(t, results[0].Score, results[1].Score)
+
+	response, err = index.Search(domain.DocumentAccess{Owner: "alice"}, domain.DocumentSearchRequest{
+		Query:     "Pages.Text:pay",
+		Highlight: domain.SearchHighlightStyleText,
+		Page:      domain.PageRequest{Size: 10},
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, response.Results, 1)
+	assert.Equal(t, []string{"please pay the <b>invoice</b>"}, response.Results[0].TextFragments)
+}
+
+func TestBleveIndex_Search_CountsFacets(t *testing.T) {
+	owner := &domain.User{Username: "alice"}
+	january := time.Date(2021, time.January, 15, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	pages := func(count int) []domain.DocumentPage {
+		return make([]domain.DocumentPage, count)
+	}
+
+	index := newTestBleveIndex(t,
+		domain.Document{
+			DocumentNumber: 1,
+			Owner:          owner,
+			Date:           &january,
+			State:          domain.DocumentStateProcessed,
+			Tags:           []domain.Tag{{ID: 1}, {ID: 2}},
+			Pages:          pages(1),
+		},
+		domain.Document{
+			DocumentNumber: 2,
+			Owner:          owner,
+			Date:           &march,
+			State:          domain.DocumentStateProcessed,
+			Tags:           []domain.Tag{{ID: 1}},
+			Class:          &domain.DocumentClass{ID: 3},
+			Pages:          pages(4),
+		},
+		domain.Document{
+			DocumentNumber: 3,
+			Owner:          owner,
+			State:          domain.DocumentStateEdited,
+			Pages:          pages(25),
+		},
+		domain.Document{
+			DocumentNumber: 4,
+			Owner:          &domain.User{Username: "bob"},
+			Date:           &march,
+			State:          domain.DocumentStateProcessed,
+			Tags:           []domain.Tag{{ID: 1}},
+		},
+	)
+
+	response, err := index.Search(domain.DocumentAccess{Owner: "alice"}, domain.DocumentSearchRequest{
+		Facets: []domain.DocumentFacet{
+			domain.DocumentFacetYear,
+			domain.DocumentFacetMonth,
+			domain.DocumentFacetState,
+			domain.DocumentFacetPageCount,
+			domain.DocumentFacetTags,
+			domain.DocumentFacetClass,
+		},
+		Page: domain.PageRequest{Size: 1},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Count(3), response.TotalCount)
+	assert.Len(t, response.Results, 1)
+	assert.Equal(t, []domain.DocumentFacetResult{
+		{
+			Facet:   domain.DocumentFacetYear,
+			Total:   2,
+			Missing: 1,
+			Terms:   []domain.DocumentFacetTerm{{Term: "2021", Count: 2}},
+		},
+		{
+			Facet:   domain.DocumentFacetMonth,
+			Total:   2,
+			Missing: 1,
+			Terms:   []domain.DocumentFacetTerm{{Term: "2021-01", Count: 1}, {Term: "2021-03", Count: 1}},
+		},
+		{
+			Facet: domain.DocumentFacetState,
+			Total: 3,
+			Terms: []domain.DocumentFacetTerm{{Term: "PROCESSED", Count: 2}, {Term: "EDITED", Count: 1}},
+		},
+		{
+			Facet: domain.DocumentFacetPageCount,
+			Total: 3,
+			Terms: []domain.DocumentFacetTerm{{Term: "1", Count: 1}, {Term: "2-5", Count: 1}, {Term: "21+", Count: 1}},
+		},
+		{
+			Facet:   domain.DocumentFacetTags,
+			Total:   3,
+			Missing: 1,
+			Terms:   []domain.DocumentFacetTerm{{Term: "1", Count: 2}, {Term: "2", Count: 1}},
+		},
+		{
+			Facet:   domain.DocumentFacetClass,
+			Total:   1,
+			Missing: 2,
+			Terms:   []domain.DocumentFacetTerm{{Term: "3", Count: 1}},
+		},
+	}, response.Facets)
+}
+
+func TestBleveIndex_Suggest(t *testing.T) {
+	alice := &domain.User{Username: "alice"}
+	index := newTestBleveIndex(t,
+		domain.Document{DocumentNumber: 1, Title: "Rechnung", Owner: alice, Pages: []domain.DocumentPage{
+			{PageNumber: 1, Text: "Ihre Rechnung der Telekom nach Prüfung"},
+		}},
+		domain.Document{DocumentNumber: 2, Title: "Rechnungen", Owner: alice, Pages: []domain.DocumentPage{
+			{PageNumber: 1, Text: "Rechnung"},
+		}},
+		domain.Document{DocumentNumber: 3, Title: "Rechtsanwalt", Owner: &domain.User{Username: "bob"}},
+	)
+
+	tests := []struct {
+		query       string
+		completions []string
+		correction  string
+	}{
+		{"rech", []string{"rechnung", "rechnungen"}, ""},
+		{"Telekom Rech", []string{"rechnung", "rechnungen"}, ""},
+		{"telekom ", []string{}, ""},
+		{"prufung", []string{"prüfung"}, "prüfung"},
+		{"Rechnnug Telekom", []string{"telekom"}, ""},
+		{"Rechnnug Telekon", []string{}, "rechnung telekom"},
+		{"Title:rechnug", []string{}, "Title:rechnung"},
+		{"rechnung", []string{"rechnung", "rechnungen"}, ""},
+		{"rechtsanwalt", []string{}, ""},
+	}
+
+	for _, test := range tests {
+		suggestions, err := index.Suggest("alice", test.query, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, test.completions, suggestions.Completions, test.query)
+		assert.Equal(t, test.correction, suggestions.Correction, test.query)
+	}
+}
+
+func TestBleveIndex_Search_AnalyzesDocumentLanguage(t *testing.T) {
+	owner := &domain.User{Username: "alice"}
+	index := newTestBleveIndex(t,
+		domain.Document{
+			DocumentNumber: 1,
+			Title:          "Rechnung",
+			Language:       domain.DocumentLanguageGerman,
+			Owner:          owner,
+			Pages:          []domain.DocumentPage{{PageNumber: 1, Text: "Bitte begleichen Sie die Rechnung"}},
+		},
+		domain.Document{
+			DocumentNumber: 2,
+			Title:          "Invoice",
+			Language:       domain.DocumentLanguageEnglish,
+			Owner:          owner,
+			Pages:          []domain.DocumentPage{{PageNumber: 1, Text: "Please pay the invoice"}},
+		},
+		domain.Document{
+			DocumentNumber: 3,
+			Title:          "Quittung",
+			Owner:          owner,
+			Pages:          []domain.DocumentPage{{PageNumber: 1, Text: "Quittung"}},
+		},
+	)
+
+	tests := []struct {
+		query    string
+		expected []domain.DocumentNumber
+	}{
+		{"Rechnungen", []domain.DocumentNumber{1}},
+		{"Title:rechnungen", []domain.DocumentNumber{1}},
+		{"Pages.Text:Rechnungen", []domain.DocumentNumber{1}},
+		{"invoices", []domain.DocumentNumber{2}},
+		{`"paying the invoices"`, []domain.DocumentNumber{2}},
+		{"quittung", []domain.DocumentNumber{3}},
+		{"+Rechnungen -begleichen", []domain.DocumentNumber{}},
+	}
+
+	for _, test := range tests {
+		response, err := index.Search(domain.DocumentAccess{Owner: "alice"}, domain.DocumentSearchRequest{
+			Query: test.query,
+			Page:  domain.PageRequest{Size: 10},
+		})
+
+		assert.Nil(t, err)
+
+		documentNumbers := make([]domain.DocumentNumber, len(response.Results))
+		for i, result := range response.Results {
+			documentNumbers[i] = result.Document.DocumentNumber
+		}
+
+		assert.Equal(t, test.expected, documentNumbers, test.query)
+	}
+}
@@ -0,0 +1,269 @@
+package infrastructure
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+type correspondentsGormImpl struct {
+	db     *Database
+	mapper *correspondentsGormMapper
+}
+
+type correspondentModel struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	OwnerID uint   `gorm:"not_null;uniqueIndex:idx_correspondents_owner_name"`
+	Name    string `gorm:"not_null;size:255;uniqueIndex:idx_correspondents_owner_name"`
+
+	Owner *userModel
+}
+
+// correspondentStatistics holds the figures derived from the documents of a
+// correspondent.
+type correspondentStatistics struct {
+	CorrespondentID        uint
+	DocumentCount          int64
+	LastCorrespondenceDate *time.Time
+}
+
+func (correspondentModel) TableName() string {
+	return "correspondents"
+}
+
+// NewCorrespondents creates a new correspondents domain repository.
+func NewCorrespondents(db *Database) domain.Correspondents {
+	return correspondentsGormImpl{
+		db:     db,
+		mapper: newCorrespondentsGormMapper(newUsersGormMapper()),
+	}
+}
+
+func (c correspondentsGormImpl) FindByUsername(
+	username domain.Name,
+	page domain.PageRequest,
+) ([]domain.Correspondent, domain.Count, error) {
+	var (
+		correspondents []correspondentModel
+		totalCount     int64
+	)
+
+	err := c.db.
+		Preload("Owner").
+		Joins("inner join users on users.id = correspondents.owner_id").
+		Where("users.username = ?", string(username)).
+		Order("correspondents.name asc").
+		Offset(page.Offset).
+		Limit(page.Size).
+		Find(&correspondents).
+		Count(&totalCount).
+		Error
+
+	if err != nil {
+		return nil, -1, err
+	}
+
+	statistics, err := c.getStatistics(correspondents...)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	return c.mapper.MapCorrespondentModelsToDomainEntities(correspondents, statistics), domain.Count(totalCount), nil
+}
+
+func (c correspondentsGormImpl) GetByID(id domain.CorrespondentID) (*domain.Correspondent, error) {
+	correspondent, err := c.getCorrespondentModelByID(uint(id))
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	statistics, err := c.getStatistics(*correspondent)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.mapper.MapCorrespondentModelToDomainEntity(correspondent, statistics[correspondent.ID]), nil
+}
+
+func (c correspondentsGormImpl) GetByUsernameAndName(
+	username domain.Name,
+	name domain.Name,
+) (*domain.Correspondent, error) {
+	var correspondent correspondentModel
+
+	err := c.db.
+		Preload("Owner").
+		Joins("inner join users on users.id = correspondents.owner_id").
+		Where("users.username = ? AND correspondents.name = ?", string(username), string(name)).
+		First(&correspondent).
+		Error
+
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return c.GetByID(domain.CorrespondentID(correspondent.ID))
+}
+
+func (c correspondentsGormImpl) Add(correspondent *domain.Correspondent) (*domain.Correspondent, error) {
+	owner, err := c.getCorrespondentOwner(correspondent)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create correspondent")
+	}
+
+	model := c.mapper.MapDomainEntityToCorrespondentModel(owner.ID, correspondent)
+	if err := c.db.Create(model).Error; err != nil {
+		return nil, errors.Wrap(err, "Failed to create correspondent")
+	}
+
+	return c.GetByID(domain.CorrespondentID(model.ID))
+}
+
+func (c correspondentsGormImpl) Update(correspondent *domain.Correspondent) (*domain.Correspondent, error) {
+	originalModel, err := c.getCorrespondentModelByID(uint(correspondent.ID))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to update correspondent")
+	}
+
+	model := c.mapper.MapDomainEntityToCorrespondentModel(originalModel.OwnerID, correspondent)
+	model.CreatedAt = originalModel.CreatedAt
+	if err := c.db.Save(model).Error; err != nil {
+		return nil, errors.Wrap(err, "Failed to update correspondent")
+	}
+
+	return c.GetByID(correspondent.ID)
+}
+
+func (c correspondentsGormImpl) Delete(correspondent *domain.Correspondent) error {
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Unscoped().
+			Model(&documentModel{}).
+			Where("correspondent_id = ?", uint(correspondent.ID)).
+			Update("correspondent_id", nil).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&correspondentModel{ID: uint(correspondent.ID)}).Error
+	})
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to delete correspondent %d", correspondent.ID)
+	}
+
+	return nil
+}
+
+/* Helper Methods */
+
+// getStatistics returns the document statistics of the given correspondents
+// by their IDs. Trashed documents are not taken into account.
+func (c *correspondentsGormImpl) getStatistics(
+	correspondents ...correspondentModel,
+) (map[uint]correspondentStatistics, error) {
+	statistics := make(map[uint]correspondentStatistics, len(correspondents))
+	if len(correspondents) == 0 {
+		return statistics, nil
+	}
+
+	ids := make([]uint, len(correspondents))
+	for i, correspondent := range correspondents {
+		ids[i] = correspondent.ID
+	}
+
+	var counts []correspondentStatistics
+	err := c.db.
+		Model(&documentModel{}).
+		Select("correspondent_id, count(*) as document_count").
+		Where("correspondent_id IN (?)", ids).
+		Group("correspondent_id").
+		Scan(&counts).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, count := range counts {
+		statistics[count.CorrespondentID] = count
+	}
+
+	// The latest dates are selected as plain column rather than aggregate,
+	// so they are scanned as dates regardless of the database system.
+	var latestDocuments []documentModel
+	err = c.db.
+		Select("correspondent_id, date").
+		Where("correspondent_id IN (?)", ids).
+		Where("date = (?)", c.db.
+			Unscoped().
+			Table("documents AS latest").
+			Select("max(latest.date)").
+			Where("latest.correspondent_id = documents.correspondent_id AND latest.deleted_at IS NULL")).
+		Find(&latestDocuments).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, document := range latestDocuments {
+		correspondentStatistics := statistics[*document.CorrespondentID]
+		correspondentStatistics.LastCorrespondenceDate = document.Date
+		statistics[*document.CorrespondentID] = correspondentStatistics
+	}
+
+	return statistics, nil
+}
+
+func (c *correspondentsGormImpl) getCorrespondentOwner(correspondent *domain.Correspondent) (*userModel, error) {
+	var owner userModel
+	err := c.db.
+		Select("id").
+		Where("username = ?", string(correspondent.Owner.Username)).
+		First(&owner).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Failed to find user with username '%s'",
+			string(correspondent.Owner.Username),
+		)
+	}
+
+	return &owner, nil
+}
+
+func (c *correspondentsGormImpl) getCorrespondentModelByID(id uint) (*correspondentModel, error) {
+	correspondent := correspondentModel{
+		ID: id,
+	}
+
+	err := c.db.
+		Preload("Owner").
+		First(&correspondent).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &correspondent, nil
+}
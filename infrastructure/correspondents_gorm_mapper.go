@@ -0,0 +1,68 @@
+package infrastructure
+
+import "github.com/concepts-system/go-paperless/domain"
+
+type correspondentsGormMapper struct {
+	usersMapper *usersGormMapper
+}
+
+func newCorrespondentsGormMapper(usersMapper *usersGormMapper) *correspondentsGormMapper {
+	return &correspondentsGormMapper{
+		usersMapper: usersMapper,
+	}
+}
+
+// MapCorrespondentModelToDomainEntity maps the given correspondent model and
+// its statistics to the corresponding domain entity.
+func (m *correspondentsGormMapper) MapCorrespondentModelToDomainEntity(
+	correspondent *correspondentModel,
+	statistics correspondentStatistics,
+) *domain.Correspondent {
+	if correspondent == nil {
+		return nil
+	}
+
+	return &domain.Correspondent{
+		ID:                     domain.CorrespondentID(correspondent.ID),
+		Name:                   domain.Name(correspondent.Name),
+		DocumentCount:          domain.Count(statistics.DocumentCount),
+		LastCorrespondenceDate: statistics.LastCorrespondenceDate,
+		Owner:                  m.usersMapper.MapUserModelToDomainEntity(correspondent.Owner),
+	}
+}
+
+// MapCorrespondentModelsToDomainEntities maps the given list of correspondent
+// models to a list containing the corresponding domain entities.
+func (m *correspondentsGormMapper) MapCorrespondentModelsToDomainEntities(
+	correspondents []correspondentModel,
+	statistics map[uint]correspondentStatistics,
+) []domain.Correspondent {
+	if correspondents == nil {
+		return nil
+	}
+
+	domainEntities := make([]domain.Correspondent, len(correspondents))
+
+	for i, correspondent := range correspondents {
+		domainEntities[i] = *m.MapCorrespondentModelToDomainEntity(&correspondent, statistics[correspondent.ID])
+	}
+
+	return domainEntities
+}
+
+// MapDomainEntityToCorrespondentModel maps the given domain entity to the
+// corresponding correspondent model.
+func (m *correspondentsGormMapper) MapDomainEntityToCorrespondentModel(
+	ownerID uint,
+	correspondent *domain.Correspondent,
+) *correspondentModel {
+	if correspondent == nil {
+		return nil
+	}
+
+	return &correspondentModel{
+		ID:      uint(correspondent.ID),
+		OwnerID: ownerID,
+		Name:    string(correspondent.Name),
+	}
+}
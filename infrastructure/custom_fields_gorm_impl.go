@@ -0,0 +1,225 @@
+package infrastructure
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+type customFieldsGormImpl struct {
+	db     *Database
+	mapper *customFieldsGormMapper
+}
+
+type customFieldModel struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	OwnerID uint   `gorm:"not_null;uniqueIndex:idx_custom_fields_owner_name"`
+	Name    string `gorm:"not_null;size:255;uniqueIndex:idx_custom_fields_owner_name"`
+	Type    string `gorm:"not_null;size:32"`
+
+	Owner *userModel
+}
+
+// documentCustomFieldModel represents the value of a custom field for a
+// document.
+type documentCustomFieldModel struct {
+	DocumentNumber uint   `gorm:"not_null;primaryKey;autoIncrement:false"`
+	CustomFieldID  uint   `gorm:"not_null;primaryKey;autoIncrement:false"`
+	Value          string `gorm:"not_null"`
+
+	CustomField *customFieldModel
+}
+
+func (customFieldModel) TableName() string {
+	return "custom_fields"
+}
+
+func (documentCustomFieldModel) TableName() string {
+	return "document_custom_fields"
+}
+
+// NewCustomFields creates a new custom fields domain repository.
+func NewCustomFields(db *Database) domain.CustomFields {
+	return customFieldsGormImpl{
+		db:     db,
+		mapper: newCustomFieldsGormMapper(newUsersGormMapper()),
+	}
+}
+
+func (f customFieldsGormImpl) FindByUsername(
+	username domain.Name,
+	page domain.PageRequest,
+) ([]domain.CustomField, domain.Count, error) {
+	var (
+		customFields []customFieldModel
+		totalCount   int64
+	)
+
+	err := f.db.
+		Preload("Owner").
+		Joins("inner join users on users.id = custom_fields.owner_id").
+		Where("users.username = ?", string(username)).
+		Order("custom_fields.name asc").
+		Offset(page.Offset).
+		Limit(page.Size).
+		Find(&customFields).
+		Count(&totalCount).
+		Error
+
+	if err != nil {
+		return nil, -1, err
+	}
+
+	return f.mapper.MapCustomFieldModelsToDomainEntities(customFields), domain.Count(totalCount), nil
+}
+
+func (f customFieldsGormImpl) FindByIDs(ids ...domain.CustomFieldID) ([]domain.CustomField, error) {
+	var customFields []customFieldModel
+
+	if len(ids) == 0 {
+		return []domain.CustomField{}, nil
+	}
+
+	err := f.db.
+		Preload("Owner").
+		Order("name asc").
+		Find(&customFields, ids).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return f.mapper.MapCustomFieldModelsToDomainEntities(customFields), nil
+}
+
+func (f customFieldsGormImpl) GetByID(id domain.CustomFieldID) (*domain.CustomField, error) {
+	customField, err := f.getCustomFieldModelByID(uint(id))
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return f.mapper.MapCustomFieldModelToDomainEntity(customField), nil
+}
+
+func (f customFieldsGormImpl) GetByUsernameAndName(
+	username domain.Name,
+	name domain.Name,
+) (*domain.CustomField, error) {
+	var customField customFieldModel
+
+	err := f.db.
+		Preload("Owner").
+		Joins("inner join users on users.id = custom_fields.owner_id").
+		Where("users.username = ? AND custom_fields.name = ?", string(username), string(name)).
+		First(&customField).
+		Error
+
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return f.mapper.MapCustomFieldModelToDomainEntity(&customField), nil
+}
+
+func (f customFieldsGormImpl) Add(customField *domain.CustomField) (*domain.CustomField, error) {
+	owner, err := f.getCustomFieldOwner(customField)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create custom field")
+	}
+
+	model := f.mapper.MapDomainEntityToCustomFieldModel(owner.ID, customField)
+	if err := f.db.Create(model).Error; err != nil {
+		return nil, errors.Wrap(err, "Failed to create custom field")
+	}
+
+	return f.GetByID(domain.CustomFieldID(model.ID))
+}
+
+func (f customFieldsGormImpl) Update(customField *domain.CustomField) (*domain.CustomField, error) {
+	originalModel, err := f.getCustomFieldModelByID(uint(customField.ID))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to update custom field")
+	}
+
+	model := f.mapper.MapDomainEntityToCustomFieldModel(originalModel.OwnerID, customField)
+	model.CreatedAt = originalModel.CreatedAt
+	if err := f.db.Save(model).Error; err != nil {
+		return nil, errors.Wrap(err, "Failed to update custom field")
+	}
+
+	return f.GetByID(customField.ID)
+}
+
+func (f customFieldsGormImpl) Delete(customField *domain.CustomField) error {
+	err := f.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("custom_field_id = ?", uint(customField.ID)).
+			Delete(&documentCustomFieldModel{}).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&customFieldModel{ID: uint(customField.ID)}).Error
+	})
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to delete custom field %d", customField.ID)
+	}
+
+	return nil
+}
+
+/* Helper Methods */
+
+func (f *customFieldsGormImpl) getCustomFieldOwner(customField *domain.CustomField) (*userModel, error) {
+	var owner userModel
+	err := f.db.
+		Select("id").
+		Where("username = ?", string(customField.Owner.Username)).
+		First(&owner).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Failed to find user with username '%s'",
+			string(customField.Owner.Username),
+		)
+	}
+
+	return &owner, nil
+}
+
+func (f *customFieldsGormImpl) getCustomFieldModelByID(id uint) (*customFieldModel, error) {
+	customField := customFieldModel{
+		ID: id,
+	}
+
+	err := f.db.
+		Preload("Owner").
+		First(&customField).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &customField, nil
+}
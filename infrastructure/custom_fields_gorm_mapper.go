@@ -0,0 +1,89 @@
+package infrastructure
+
+import "github.com/concepts-system/go-paperless/domain"
+
+type customFieldsGormMapper struct {
+	usersMapper *usersGormMapper
+}
+
+func newCustomFieldsGormMapper(usersMapper *usersGormMapper) *customFieldsGormMapper {
+	return &customFieldsGormMapper{
+		usersMapper: usersMapper,
+	}
+}
+
+// MapCustomFieldModelToDomainEntity maps the given custom field model to the
+// corresponding domain entity.
+func (m *customFieldsGormMapper) MapCustomFieldModelToDomainEntity(customField *customFieldModel) *domain.CustomField {
+	if customField == nil {
+		return nil
+	}
+
+	return &domain.CustomField{
+		ID:    domain.CustomFieldID(customField.ID),
+		Name:  domain.Name(customField.Name),
+		Type:  domain.CustomFieldType(customField.Type),
+		Owner: m.usersMapper.MapUserModelToDomainEntity(customField.Owner),
+	}
+}
+
+// MapCustomFieldModelsToDomainEntities maps the given list of custom field
+// models to a list containing the corresponding domain entities.
+func (m *customFieldsGormMapper) MapCustomFieldModelsToDomainEntities(
+	customFields []customFieldModel,
+) []domain.CustomField {
+	if customFields == nil {
+		return nil
+	}
+
+	domainEntities := make([]domain.CustomField, len(customFields))
+
+	for i, customField := range customFields {
+		domainEntities[i] = *m.MapCustomFieldModelToDomainEntity(&customField)
+	}
+
+	return domainEntities
+}
+
+// MapDomainEntityToCustomFieldModel maps the given domain entity to the
+// corresponding custom field model.
+func (m *customFieldsGormMapper) MapDomainEntityToCustomFieldModel(
+	ownerID uint,
+	customField *domain.CustomField,
+) *customFieldModel {
+	if customField == nil {
+		return nil
+	}
+
+	return &customFieldModel{
+		ID:      uint(customField.ID),
+		OwnerID: ownerID,
+		Name:    string(customField.Name),
+		Type:    string(customField.Type),
+	}
+}
+
+// MapCustomFieldValueModelsToDomainEntities maps the given list of custom
+// field value models to a list containing the corresponding domain entities.
+func (m *customFieldsGormMapper) MapCustomFieldValueModelsToDomainEntities(
+	values []documentCustomFieldModel,
+) []domain.CustomFieldValue {
+	if values == nil {
+		return nil
+	}
+
+	domainEntities := make([]domain.CustomFieldValue, 0, len(values))
+
+	for _, value := range values {
+		if value.CustomField == nil {
+			continue
+		}
+
+		domainEntities = append(domainEntities, domain.CustomFieldValue{
+			Field: *m.MapCustomFieldModelToDomainEntity(value.CustomField),
+			Value: value.Value,
+		})
+	}
+
+	return domainEntities
+}
@@ -73,7 +73,7 @@ func (store *documentArchiveFileSystemImpl) StoreContent(
 	return nil
 }
 
-// StoreContent stores content for a new document or page.
+// MoveContent moves the stored content of one key to another.
 func (store *documentArchiveFileSystemImpl) MoveContent(
 	documentNumber domain.DocumentNumber,
 	sourceContentKey domain.ContentKey,
@@ -93,12 +93,13 @@ func (store *documentArchiveFileSystemImpl) MoveContent(
 }
 
 // DeleteContent deletes the content for a document or page from the store.
+// Deleting content not present in the store is a no-op.
 func (store *documentArchiveFileSystemImpl) DeleteContent(
 	documentNumber domain.DocumentNumber,
 	contentKey domain.ContentKey,
 ) error {
 	path := store.getContentPath(documentNumber, contentKey)
-	if err := os.Remove(path); err != nil {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
 		return errors.Wrapf(err, "Failed to delete content file '%s'", path)
 	}
 
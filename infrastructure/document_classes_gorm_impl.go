@@ -0,0 +1,192 @@
+package infrastructure
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+type documentClassesGormImpl struct {
+	db     *Database
+	mapper *documentClassesGormMapper
+}
+
+type documentClassModel struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	OwnerID uint   `gorm:"not_null;uniqueIndex:idx_document_classes_owner_name"`
+	Name    string `gorm:"not_null;size:255;uniqueIndex:idx_document_classes_owner_name"`
+
+	Owner *userModel
+}
+
+func (documentClassModel) TableName() string {
+	return "document_classes"
+}
+
+// NewDocumentClasses creates a new document classes domain repository.
+func NewDocumentClasses(db *Database) domain.DocumentClasses {
+	return documentClassesGormImpl{
+		db:     db,
+		mapper: newDocumentClassesGormMapper(newUsersGormMapper()),
+	}
+}
+
+func (c documentClassesGormImpl) FindByUsername(
+	username domain.Name,
+	page domain.PageRequest,
+) ([]domain.DocumentClass, domain.Count, error) {
+	var (
+		documentClasses []documentClassModel
+		totalCount      int64
+	)
+
+	err := c.db.
+		Preload("Owner").
+		Joins("inner join users on users.id = document_classes.owner_id").
+		Where("users.username = ?", string(username)).
+		Order("document_classes.name asc").
+		Offset(page.Offset).
+		Limit(page.Size).
+		Find(&documentClasses).
+		Count(&totalCount).
+		Error
+
+	if err != nil {
+		return nil, -1, err
+	}
+
+	return c.mapper.MapDocumentClassModelsToDomainEntities(documentClasses), domain.Count(totalCount), nil
+}
+
+func (c documentClassesGormImpl) GetByID(id domain.DocumentClassID) (*domain.DocumentClass, error) {
+	documentClass, err := c.getDocumentClassModelByID(uint(id))
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return c.mapper.MapDocumentClassModelToDomainEntity(documentClass), nil
+}
+
+func (c documentClassesGormImpl) GetByUsernameAndName(
+	username domain.Name,
+	name domain.Name,
+) (*domain.DocumentClass, error) {
+	var documentClass documentClassModel
+
+	err := c.db.
+		Preload("Owner").
+		Joins("inner join users on users.id = document_classes.owner_id").
+		Where("users.username = ? AND document_classes.name = ?", string(username), string(name)).
+		First(&documentClass).
+		Error
+
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return c.mapper.MapDocumentClassModelToDomainEntity(&documentClass), nil
+}
+
+func (c documentClassesGormImpl) Add(documentClass *domain.DocumentClass) (*domain.DocumentClass, error) {
+	owner, err := c.getDocumentClassOwner(documentClass)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create document class")
+	}
+
+	model := c.mapper.MapDomainEntityToDocumentClassModel(owner.ID, documentClass)
+	if err := c.db.Create(model).Error; err != nil {
+		return nil, errors.Wrap(err, "Failed to create document class")
+	}
+
+	return c.GetByID(domain.DocumentClassID(model.ID))
+}
+
+func (c documentClassesGormImpl) Update(documentClass *domain.DocumentClass) (*domain.DocumentClass, error) {
+	originalModel, err := c.getDocumentClassModelByID(uint(documentClass.ID))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to update document class")
+	}
+
+	model := c.mapper.MapDomainEntityToDocumentClassModel(originalModel.OwnerID, documentClass)
+	model.CreatedAt = originalModel.CreatedAt
+	if err := c.db.Save(model).Error; err != nil {
+		return nil, errors.Wrap(err, "Failed to update document class")
+	}
+
+	return c.GetByID(documentClass.ID)
+}
+
+func (c documentClassesGormImpl) Delete(documentClass *domain.DocumentClass) error {
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Unscoped().
+			Model(&documentModel{}).
+			Where("class_id = ?", uint(documentClass.ID)).
+			Update("class_id", nil).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&documentClassModel{ID: uint(documentClass.ID)}).Error
+	})
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to delete document class %d", documentClass.ID)
+	}
+
+	return nil
+}
+
+/* Helper Methods */
+
+func (c *documentClassesGormImpl) getDocumentClassOwner(documentClass *domain.DocumentClass) (*userModel, error) {
+	var owner userModel
+	err := c.db.
+		Select("id").
+		Where("username = ?", string(documentClass.Owner.Username)).
+		First(&owner).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Failed to find user with username '%s'",
+			string(documentClass.Owner.Username),
+		)
+	}
+
+	return &owner, nil
+}
+
+func (c *documentClassesGormImpl) getDocumentClassModelByID(id uint) (*documentClassModel, error) {
+	documentClass := documentClassModel{
+		ID: id,
+	}
+
+	err := c.db.
+		Preload("Owner").
+		First(&documentClass).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &documentClass, nil
+}
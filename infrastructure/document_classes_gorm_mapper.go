@@ -0,0 +1,64 @@
+package infrastructure
+
+import "github.com/concepts-system/go-paperless/domain"
+
+type documentClassesGormMapper struct {
+	usersMapper *usersGormMapper
+}
+
+func newDocumentClassesGormMapper(usersMapper *usersGormMapper) *documentClassesGormMapper {
+	return &documentClassesGormMapper{
+		usersMapper: usersMapper,
+	}
+}
+
+// MapDocumentClassModelToDomainEntity maps the given document class model to
+// the corresponding domain entity.
+func (m *documentClassesGormMapper) MapDocumentClassModelToDomainEntity(
+	documentClass *documentClassModel,
+) *domain.DocumentClass {
+	if documentClass == nil {
+		return nil
+	}
+
+	return &domain.DocumentClass{
+		ID:    domain.DocumentClassID(documentClass.ID),
+		Name:  domain.Name(documentClass.Name),
+		Owner: m.usersMapper.MapUserModelToDomainEntity(documentClass.Owner),
+	}
+}
+
+// MapDocumentClassModelsToDomainEntities maps the given list of document class
+// models to a list containing the corresponding domain entities.
+func (m *documentClassesGormMapper) MapDocumentClassModelsToDomainEntities(
+	documentClasses []documentClassModel,
+) []domain.DocumentClass {
+	if documentClasses == nil {
+		return nil
+	}
+
+	domainEntities := make([]domain.DocumentClass, len(documentClasses))
+
+	for i, documentClass := range documentClasses {
+		domainEntities[i] = *m.MapDocumentClassModelToDomainEntity(&documentClass)
+	}
+
+	return domainEntities
+}
+
+// MapDomainEntityToDocumentClassModel maps the given domain entity to the
+// corresponding document class model.
+func (m *documentClassesGormMapper) MapDomainEntityToDocumentClassModel(
+	ownerID uint,
+	documentClass *domain.DocumentClass,
+) *documentClassModel {
+	if documentClass == nil {
+		return nil
+	}
+
+	return &documentClassModel{
+		ID:      uint(documentClass.ID),
+		OwnerID: ownerID,
+		Name:    string(documentClass.Name),
+	}
+}
@@ -0,0 +1,350 @@
+package infrastructure
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	exportManifestVersion  = 1
+	exportManifestFileName = "manifest.json"
+)
+
+type documentExporterZIPImpl struct {
+	documentArchive domain.DocumentArchive
+}
+
+type exportManifest struct {
+	Version        int                   `json:"version"`
+	ExportedAt     time.Time             `json:"exportedAt"`
+	Users          []exportUser          `json:"users,omitempty"`
+	Tags           []exportTag           `json:"tags,omitempty"`
+	Classes        []exportDocumentClass `json:"classes,omitempty"`
+	CustomFields   []exportCustomField   `json:"customFields,omitempty"`
+	Correspondents []exportCorrespondent `json:"correspondents,omitempty"`
+	Documents      []exportDocument      `json:"documents"`
+}
+
+type exportUser struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Forename string `json:"forename"`
+	Surname  string `json:"surname"`
+	IsAdmin  bool   `json:"isAdmin"`
+	IsActive bool   `json:"isActive"`
+}
+
+type exportDocument struct {
+	DocumentNumber uint                     `json:"documentNumber"`
+	Title          string                   `json:"title"`
+	Date           *time.Time               `json:"date,omitempty"`
+	State          string                   `json:"state"`
+	Fingerprint    string                   `json:"fingerprint,omitempty"`
+	Type           string                   `json:"type,omitempty"`
+	Language       string                   `json:"language,omitempty"`
+	CreatedAt      time.Time                `json:"createdAt"`
+	UpdatedAt      time.Time                `json:"updatedAt"`
+	DeletedAt      *time.Time               `json:"deletedAt,omitempty"`
+	Owner          *exportOwner             `json:"owner,omitempty"`
+	Tags           []exportTag              `json:"tags,omitempty"`
+	Class          *exportDocumentClass     `json:"class,omitempty"`
+	Correspondent  *exportCorrespondent     `json:"correspondent,omitempty"`
+	CustomFields   []exportCustomFieldValue `json:"customFields,omitempty"`
+	Content        string                   `json:"content,omitempty"`
+	Pages          []exportPage             `json:"pages"`
+	Originals      []exportOriginal         `json:"originals,omitempty"`
+}
+
+type exportOwner struct {
+	Username string `json:"username"`
+	Forename string `json:"forename"`
+	Surname  string `json:"surname"`
+}
+
+type exportTag struct {
+	Owner string `json:"owner,omitempty"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+type exportDocumentClass struct {
+	Owner string `json:"owner,omitempty"`
+	Name  string `json:"name"`
+}
+
+type exportCustomField struct {
+	Owner string `json:"owner,omitempty"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+}
+
+type exportCustomFieldValue struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type exportCorrespondent struct {
+	Owner string `json:"owner,omitempty"`
+	Name  string `json:"name"`
+}
+
+type exportPage struct {
+	PageNumber          uint   `json:"pageNumber"`
+	State               string `json:"state"`
+	Type                string `json:"type"`
+	Fingerprint         string `json:"fingerprint"`
+	Content             string `json:"content"`
+	Text                string `json:"text,omitempty"`
+	OriginalFingerprint string `json:"originalFingerprint,omitempty"`
+	PerceptualHash      string `json:"perceptualHash,omitempty"`
+}
+
+type exportOriginal struct {
+	Fingerprint string `json:"fingerprint"`
+	ContentType string `json:"contentType"`
+	FileName    string `json:"fileName"`
+	Content     string `json:"content"`
+}
+
+// exportEntry describes a single file of an export alongside the archived
+// content it originates from.
+type exportEntry struct {
+	name           string
+	modified       time.Time
+	documentNumber domain.DocumentNumber
+	contentKey     domain.ContentKey
+	text           string
+}
+
+// NewZIPDocumentExporter returns a new document exporter writing ZIP files
+// containing all document and page content alongside a JSON manifest.
+func NewZIPDocumentExporter(documentArchive domain.DocumentArchive) domain.DocumentExporter {
+	return &documentExporterZIPImpl{
+		documentArchive,
+	}
+}
+
+func (e *documentExporterZIPImpl) Export(documents []domain.Document) (io.ReadCloser, error) {
+	manifest, entries := e.buildManifest(documents)
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(e.writeZIP(pw, manifest, entries))
+	}()
+
+	return pr, nil
+}
+
+/* Helper Methods */
+
+func (e *documentExporterZIPImpl) buildManifest(documents []domain.Document) (*exportManifest, []exportEntry) {
+	manifest := &exportManifest{
+		Version:    exportManifestVersion,
+		ExportedAt: time.Now(),
+		Documents:  make([]exportDocument, len(documents)),
+	}
+
+	entries := make([]exportEntry, 0)
+	for i, document := range documents {
+		documentPath := fmt.Sprintf("documents/%d", document.DocumentNumber)
+		exported := exportDocument{
+			DocumentNumber: uint(document.DocumentNumber),
+			Title:          string(document.Title),
+			Date:           document.Date,
+			State:          string(document.State),
+			Fingerprint:    string(document.Fingerprint),
+			Type:           string(document.Type),
+			Language:       string(document.Language),
+			CreatedAt:      document.CreatedAt,
+			UpdatedAt:      document.UpdatedAt,
+			DeletedAt:      document.DeletedAt,
+			Pages:          make([]exportPage, len(document.Pages)),
+		}
+
+		if document.Owner != nil {
+			exported.Owner = &exportOwner{
+				Username: string(document.Owner.Username),
+				Forename: string(document.Owner.Forename),
+				Surname:  string(document.Owner.Surname),
+			}
+		}
+
+		for _, tag := range document.Tags {
+			exported.Tags = append(exported.Tags, exportTag{
+				Name:  string(tag.Name),
+				Color: string(tag.Color),
+			})
+		}
+
+		for _, value := range document.CustomFields {
+			exported.CustomFields = append(exported.CustomFields, exportCustomFieldValue{
+				Name:  string(value.Field.Name),
+				Type:  string(value.Field.Type),
+				Value: value.Value,
+			})
+		}
+
+		if document.Class != nil {
+			exported.Class = &exportDocumentClass{
+				Name: string(document.Class.Name),
+			}
+		}
+
+		if document.Correspondent != nil {
+			exported.Correspondent = &exportCorrespondent{
+				Name: string(document.Correspondent.Name),
+			}
+		}
+
+		if document.Fingerprint != "" && document.Type != "" {
+			exported.Content = path.Join(documentPath, "document."+strings.ToLower(string(document.Type)))
+			entries = append(entries, exportEntry{
+				name:           exported.Content,
+				modified:       document.UpdatedAt,
+				documentNumber: document.DocumentNumber,
+				contentKey:     document.ContentKey(),
+			})
+		}
+
+		exportedOriginals := make(map[domain.Fingerprint]bool)
+		for j, page := range document.Pages {
+			pagePath := path.Join(documentPath, "pages", fmt.Sprint(page.PageNumber))
+			exportedPage := exportPage{
+				PageNumber:  uint(page.PageNumber),
+				State:       string(page.State),
+				Type:        string(page.Type),
+				Fingerprint: string(page.Fingerprint),
+				Content:     pagePath + "." + strings.ToLower(string(page.Type)),
+			}
+
+			entries = append(entries, exportEntry{
+				name:           exportedPage.Content,
+				modified:       document.UpdatedAt,
+				documentNumber: document.DocumentNumber,
+				contentKey:     page.ContentKey(),
+			})
+
+			if page.Text != "" {
+				exportedPage.Text = pagePath + ".txt"
+				entries = append(entries, exportEntry{
+					name:     exportedPage.Text,
+					modified: document.UpdatedAt,
+					text:     string(page.Text),
+				})
+			}
+
+			if page.PerceptualHash != nil {
+				exportedPage.PerceptualHash = formatPerceptualHash(*page.PerceptualHash)
+			}
+
+			if page.Original != nil {
+				exportedPage.OriginalFingerprint = string(page.Original.Fingerprint)
+				if !exportedOriginals[page.Original.Fingerprint] {
+					exportedOriginals[page.Original.Fingerprint] = true
+					exportedOriginal := exportOriginal{
+						Fingerprint: string(page.Original.Fingerprint),
+						ContentType: page.Original.ContentType,
+						FileName:    page.Original.FileName,
+						Content: path.Join(
+							documentPath,
+							"originals",
+							string(page.Original.Fingerprint)+path.Ext(page.Original.FileName),
+						),
+					}
+
+					exported.Originals = append(exported.Originals, exportedOriginal)
+					entries = append(entries, exportEntry{
+						name:           exportedOriginal.Content,
+						modified:       document.UpdatedAt,
+						documentNumber: document.DocumentNumber,
+						contentKey:     page.Original.ContentKey(),
+					})
+				}
+			}
+
+			exported.Pages[j] = exportedPage
+		}
+
+		manifest.Documents[i] = exported
+	}
+
+	return manifest, entries
+}
+
+func (e *documentExporterZIPImpl) writeZIP(
+	w io.Writer,
+	manifest *exportManifest,
+	entries []exportEntry,
+) error {
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Failed to create export manifest")
+	}
+
+	zipWriter := zip.NewWriter(w)
+	manifestEntry := exportEntry{
+		name:     exportManifestFileName,
+		modified: manifest.ExportedAt,
+		text:     string(manifestContent),
+	}
+
+	if err := e.writeEntry(zipWriter, manifestEntry); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := e.writeEntry(zipWriter, entry); err != nil {
+			log.Errorf("Export failed: %v", err)
+			return err
+		}
+	}
+
+	return zipWriter.Close()
+}
+
+func (e *documentExporterZIPImpl) writeEntry(zipWriter *zip.Writer, entry exportEntry) error {
+	header := &zip.FileHeader{
+		Name:     entry.name,
+		Method:   zip.Deflate,
+		Modified: entry.modified,
+	}
+
+	// Archived content is stored as is, being compressed already.
+	if entry.contentKey != "" {
+		header.Method = zip.Store
+	}
+
+	writer, err := zipWriter.CreateHeader(header)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create export entry '%s'", entry.name)
+	}
+
+	var content io.Reader
+	if entry.contentKey == "" {
+		content = strings.NewReader(entry.text)
+	} else {
+		archivedContent, err := e.documentArchive.ReadContent(entry.documentNumber, entry.contentKey)
+		if err != nil {
+			return err
+		}
+		defer archivedContent.Close()
+
+		content = archivedContent
+	}
+
+	if _, err := io.Copy(writer, content); err != nil {
+		return errors.Wrapf(err, "Failed to write export entry '%s'", entry.name)
+	}
+
+	return nil
+}
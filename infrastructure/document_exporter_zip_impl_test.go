@@ -0,0 +1,86 @@
+package infrastructure
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZIPDocumentExporter_Export(t *testing.T) {
+	archive, err := NewDocumentArchiveFileSystemImpl(t.TempDir())
+	assert.Nil(t, err)
+
+	original := &domain.PageOriginal{Fingerprint: "upload", ContentType: "application/pdf", FileName: "scan.pdf"}
+	document := domain.Document{
+		DocumentNumber: 1,
+		Title:          "Invoice",
+		State:          domain.DocumentStateProcessed,
+		Fingerprint:    "document",
+		Type:           domain.DocumentTypePDF,
+		Owner:          &domain.User{Username: "user"},
+		Tags:           []domain.Tag{{ID: 1, Name: "tax", Color: "#ff0000"}},
+		Class:          &domain.DocumentClass{ID: 1, Name: "Tax Assessment"},
+		Correspondent:  &domain.Correspondent{ID: 1, Name: "Tax Office"},
+		CustomFields: []domain.CustomFieldValue{
+			{Field: domain.CustomField{ID: 1, Name: "amount", Type: domain.CustomFieldTypeMonetary}, Value: "1234.50"},
+		},
+		Pages: []domain.DocumentPage{
+			{PageNumber: 1, Type: domain.PageTypeTIFF, Fingerprint: "page1", Text: "first", Original: original},
+			{PageNumber: 2, Type: domain.PageTypeTIFF, Fingerprint: "page2", Original: original},
+		},
+	}
+
+	assert.Nil(t, archive.StoreContent(1, document.ContentKey(), strings.NewReader("pdf")))
+	assert.Nil(t, archive.StoreContent(1, document.Pages[0].ContentKey(), strings.NewReader("tiff1")))
+	assert.Nil(t, archive.StoreContent(1, document.Pages[1].ContentKey(), strings.NewReader("tiff2")))
+	assert.Nil(t, archive.StoreContent(1, original.ContentKey(), strings.NewReader("original")))
+
+	export, err := NewZIPDocumentExporter(archive).Export([]domain.Document{document})
+	assert.Nil(t, err)
+
+	content, err := ioutil.ReadAll(export)
+	assert.Nil(t, err)
+	assert.Nil(t, export.Close())
+
+	zipReader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	assert.Nil(t, err)
+
+	files := make(map[string]string)
+	for _, file := range zipReader.File {
+		reader, err := file.Open()
+		assert.Nil(t, err)
+		fileContent, err := ioutil.ReadAll(reader)
+		assert.Nil(t, err)
+		reader.Close()
+		files[file.Name] = string(fileContent)
+	}
+
+	assert.Len(t, files, 6)
+	assert.Equal(t, "pdf", files["documents/1/document.pdf"])
+	assert.Equal(t, "tiff1", files["documents/1/pages/1.tiff"])
+	assert.Equal(t, "tiff2", files["documents/1/pages/2.tiff"])
+	assert.Equal(t, "first", files["documents/1/pages/1.txt"])
+	assert.Equal(t, "original", files["documents/1/originals/upload.pdf"])
+
+	var manifest exportManifest
+	assert.Nil(t, json.Unmarshal([]byte(files[exportManifestFileName]), &manifest))
+	assert.Len(t, manifest.Documents, 1)
+	assert.Equal(t, "Invoice", manifest.Documents[0].Title)
+	assert.Equal(t, "user", manifest.Documents[0].Owner.Username)
+	assert.Len(t, manifest.Documents[0].Pages, 2)
+	assert.Len(t, manifest.Documents[0].Originals, 1)
+	assert.Equal(t, []exportTag{{Name: "tax", Color: "#ff0000"}}, manifest.Documents[0].Tags)
+	assert.Equal(t, &exportDocumentClass{Name: "Tax Assessment"}, manifest.Documents[0].Class)
+	assert.Equal(t, &exportCorrespondent{Name: "Tax Office"}, manifest.Documents[0].Correspondent)
+	assert.Equal(
+		t,
+		[]exportCustomFieldValue{{Name: "amount", Type: "MONETARY", Value: "1234.50"}},
+		manifest.Documents[0].CustomFields,
+	)
+}
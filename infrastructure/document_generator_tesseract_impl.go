@@ -0,0 +1,173 @@
+package infrastructure
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	pageListFileName   = "pages.txt"
+	outputBaseFileName = "document"
+)
+
+type documentGeneratorTesseractImpl struct {
+	documentArchive domain.DocumentArchive
+}
+
+// generatedFile reads a generated file and removes the working directory
+// containing it when being closed.
+type generatedFile struct {
+	*os.File
+	workingDirectory string
+}
+
+// NewTesseractDocumentGenerator returns a new document generator using
+// Tesseract OCR for creating searchable PDFs from the pages of a document.
+func NewTesseractDocumentGenerator(
+	documentArchive domain.DocumentArchive,
+) domain.DocumentGenerator {
+	return &documentGeneratorTesseractImpl{
+		documentArchive,
+	}
+}
+
+func (g *documentGeneratorTesseractImpl) Generate(document *domain.Document) (io.ReadCloser, error) {
+	if len(document.Pages) == 0 {
+		return nil, errors.Newf("Document %d has no pages to generate", document.DocumentNumber)
+	}
+
+	workingDirectory, err := ioutil.TempDir("", "paperless-generate-")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create working directory")
+	}
+
+	// The generated PDF is streamed from the working directory, which is
+	// removed once the content has been read.
+	content, err := g.generate(document, workingDirectory)
+	if err != nil {
+		os.RemoveAll(workingDirectory)
+		return nil, err
+	}
+
+	return content, nil
+}
+
+func (f *generatedFile) Close() error {
+	err := f.File.Close()
+	if removeErr := os.RemoveAll(f.workingDirectory); err == nil {
+		err = removeErr
+	}
+
+	return err
+}
+
+/* Helper Methods */
+
+func (g *documentGeneratorTesseractImpl) generate(
+	document *domain.Document,
+	workingDirectory string,
+) (io.ReadCloser, error) {
+	pageFiles, err := g.extractPages(document, workingDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	pageListPath := filepath.Join(workingDirectory, pageListFileName)
+	pageList := strings.Join(pageFiles, "\n") + "\n"
+	if err := ioutil.WriteFile(pageListPath, []byte(pageList), 0600); err != nil {
+		return nil, errors.Wrap(err, "Failed to write page list")
+	}
+
+	outputBasePath := filepath.Join(workingDirectory, outputBaseFileName)
+	if err := g.generatePDF(pageListPath, outputBasePath); err != nil {
+		return nil, errors.Wrapf(err, "Generating PDF failed for document %d", document.DocumentNumber)
+	}
+
+	file, err := os.Open(outputBasePath + ".pdf")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read generated PDF")
+	}
+
+	return &generatedFile{file, workingDirectory}, nil
+}
+
+func (g *documentGeneratorTesseractImpl) extractPages(
+	document *domain.Document,
+	directory string,
+) ([]string, error) {
+	pages := make([]domain.DocumentPage, len(document.Pages))
+	copy(pages, document.Pages)
+	sort.Slice(pages, func(i, j int) bool {
+		return pages[i].PageNumber < pages[j].PageNumber
+	})
+
+	pageFiles := make([]string, len(pages))
+	for i, page := range pages {
+		if page.State != domain.PageStateAnalyzed || page.Type != domain.PageTypeTIFF {
+			return nil, errors.Newf(
+				"Page %d of document %d is not ready for generation",
+				page.PageNumber,
+				document.DocumentNumber,
+			)
+		}
+
+		pageFile := filepath.Join(directory, fmt.Sprintf("%d.tiff", page.PageNumber))
+		if err := g.extractPage(document.DocumentNumber, page, pageFile); err != nil {
+			return nil, err
+		}
+
+		pageFiles[i] = pageFile
+	}
+
+	return pageFiles, nil
+}
+
+func (g *documentGeneratorTesseractImpl) extractPage(
+	documentNumber domain.DocumentNumber,
+	page domain.DocumentPage,
+	path string,
+) error {
+	content, err := g.documentArchive.ReadContent(documentNumber, page.ContentKey())
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create page file '%s'", path)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, content); err != nil {
+		return errors.Wrapf(err, "Failed to write page file '%s'", path)
+	}
+
+	return nil
+}
+
+func (g *documentGeneratorTesseractImpl) generatePDF(pageListPath, outputBasePath string) error {
+	path, err := exec.LookPath(tesseractExecutable)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Cmd{
+		Path:   path,
+		Args:   []string{tesseractExecutable, pageListPath, outputBasePath, "-l", languages, "pdf"},
+		Stdout: log.StandardLogger().Out,
+		Stderr: log.StandardLogger().Out,
+	}
+
+	return cmd.Run()
+}
@@ -0,0 +1,167 @@
+package infrastructure
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/tiff"
+)
+
+const renditionJPEGQuality = 85
+
+// renditionWidths defines the widths renditions are rendered in. Requested
+// widths are rounded up to the next one in order to keep the cache small.
+var renditionWidths = []int{128, 256, 512, 1024, 2048}
+
+var renditionFormats = []domain.RenditionFormat{
+	domain.RenditionFormatPNG,
+	domain.RenditionFormatJPEG,
+}
+
+type documentPageRendererImpl struct {
+	documentArchive domain.DocumentArchive
+}
+
+// NewDocumentPageRendererImpl returns a new page renderer using Go's standard
+// packages, caching renditions in the given document archive.
+func NewDocumentPageRendererImpl(
+	documentArchive domain.DocumentArchive,
+) domain.DocumentPageRenderer {
+	return &documentPageRendererImpl{
+		documentArchive,
+	}
+}
+
+func (r *documentPageRendererImpl) Render(
+	documentNumber domain.DocumentNumber,
+	page *domain.DocumentPage,
+	rendition domain.PageRendition,
+) (io.ReadCloser, error) {
+	if page.Type != domain.PageTypeTIFF || page.State == domain.PageStateEdited {
+		return nil, errors.Newf("Page %d of document %d has not been preprocessed yet", page.PageNumber, documentNumber)
+	}
+
+	rendition.Width = r.normalizeWidth(rendition.Width)
+	contentKey := rendition.ContentKey(*page)
+
+	if content, err := r.documentArchive.ReadContent(documentNumber, contentKey); err == nil {
+		return content, nil
+	}
+
+	log.Debugf("Rendering %d pixel wide %s rendition of document %d page %d", rendition.Width, rendition.Format, documentNumber, page.PageNumber)
+	rendered, err := r.render(documentNumber, page, rendition)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.documentArchive.StoreContent(documentNumber, contentKey, bytes.NewReader(rendered)); err != nil {
+		log.Warnf("Failed to cache rendition of document %d page %d: %v", documentNumber, page.PageNumber, err)
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(rendered)), nil
+}
+
+func (r *documentPageRendererImpl) Invalidate(
+	documentNumber domain.DocumentNumber,
+	page *domain.DocumentPage,
+) error {
+	for _, width := range append([]int{0}, renditionWidths...) {
+		for _, format := range renditionFormats {
+			rendition := domain.PageRendition{Width: width, Format: format}
+			if err := r.documentArchive.DeleteContent(documentNumber, rendition.ContentKey(*page)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+/* Helper Methods */
+
+func (r *documentPageRendererImpl) render(
+	documentNumber domain.DocumentNumber,
+	page *domain.DocumentPage,
+	rendition domain.PageRendition,
+) ([]byte, error) {
+	content, err := r.documentArchive.ReadContent(documentNumber, page.ContentKey())
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	source, err := tiff.Decode(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to decode page %d of document %d", page.PageNumber, documentNumber)
+	}
+
+	scaled := scaleImage(source, rendition.Width)
+	buffer := &bytes.Buffer{}
+
+	switch rendition.Format {
+	case domain.RenditionFormatPNG:
+		err = png.Encode(buffer, scaled)
+	case domain.RenditionFormatJPEG:
+		err = jpeg.Encode(buffer, scaled, &jpeg.Options{Quality: renditionJPEGQuality})
+	default:
+		err = errors.Newf("Unsupported rendition format '%s'", rendition.Format)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// normalizeWidth rounds the given width up to the next supported rendition
+// width. Zero, denoting the original width, is retained.
+func (r *documentPageRendererImpl) normalizeWidth(width int) int {
+	if width <= 0 {
+		return 0
+	}
+
+	for _, renditionWidth := range renditionWidths {
+		if width <= renditionWidth {
+			return renditionWidth
+		}
+	}
+
+	return renditionWidths[len(renditionWidths)-1]
+}
+
+/* Helper Functions */
+
+// scaleImage scales the given image to the given width, retaining its aspect
+// ratio. Images are never scaled up.
+func scaleImage(source image.Image, width int) image.Image {
+	bounds := source.Bounds()
+	if width <= 0 || width >= bounds.Dx() {
+		return source
+	}
+
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	var target draw.Image
+	switch source.(type) {
+	case *image.Gray, *image.Gray16, *image.Paletted:
+		target = image.NewGray(image.Rect(0, 0, width, height))
+	default:
+		target = image.NewRGBA(image.Rect(0, 0, width, height))
+	}
+
+	draw.ApproxBiLinear.Scale(target, target.Bounds(), source, bounds, draw.Src, nil)
+
+	return target
+}
@@ -0,0 +1,167 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+	"golang.org/x/image/tiff"
+)
+
+const (
+	tiffHeaderSize     = 8
+	tiffIFDEntrySize   = 12
+	tiffMagicNumber    = 42
+	tiffMaxPageCount   = 4096
+	tiffLittleEndian   = "II"
+	tiffBigEndian      = "MM"
+	tiffIFDOffsetIndex = 4
+)
+
+type documentPageSplitterTIFFImpl struct{}
+
+// overlayReaderAt reads from an underlying reader but replaces the TIFF
+// header, allowing to point the header to any image file directory.
+type overlayReaderAt struct {
+	io.ReaderAt
+	header [tiffHeaderSize]byte
+}
+
+// NewTIFFDocumentPageSplitter returns a new page splitter for multi-page TIFF
+// images using Go's standard packages.
+func NewTIFFDocumentPageSplitter() domain.DocumentPageSplitter {
+	return &documentPageSplitterTIFFImpl{}
+}
+
+func (s *documentPageSplitterTIFFImpl) Split(
+	content io.ReaderAt,
+	size int64,
+	handler func(page io.Reader) error,
+) error {
+	header, byteOrder, err := s.readHeader(content)
+	if err != nil {
+		return err
+	}
+
+	offsets, err := s.readImageFileDirectoryOffsets(content, byteOrder, byteOrder.Uint32(header[tiffIFDOffsetIndex:]))
+	if err != nil {
+		return err
+	}
+
+	for i, offset := range offsets {
+		pageHeader := header
+		byteOrder.PutUint32(pageHeader[tiffIFDOffsetIndex:], offset)
+		reader := &overlayReaderAt{content, pageHeader}
+
+		image, err := tiff.Decode(io.NewSectionReader(reader, 0, size))
+		if err != nil {
+			return s.contentError(err, "Failed to decode TIFF page %d", i+1)
+		}
+
+		buffer := &bytes.Buffer{}
+		resolution := readTIFFResolution(content, byteOrder, offset)
+		if err := encodeTIFF(buffer, image, resolution); err != nil {
+			return errors.Wrapf(err, "Failed to encode TIFF page %d", i+1)
+		}
+
+		if err := handler(buffer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *overlayReaderAt) ReadAt(p []byte, offset int64) (int, error) {
+	n, err := r.ReaderAt.ReadAt(p, offset)
+
+	for i := offset; i < tiffHeaderSize && i-offset < int64(n); i++ {
+		p[i-offset] = r.header[i]
+	}
+
+	return n, err
+}
+
+/* Helper Methods */
+
+func (s *documentPageSplitterTIFFImpl) readHeader(
+	content io.ReaderAt,
+) ([tiffHeaderSize]byte, binary.ByteOrder, error) {
+	var header [tiffHeaderSize]byte
+	if _, err := content.ReadAt(header[:], 0); err != nil {
+		return header, nil, s.contentError(err, "Failed to read TIFF header")
+	}
+
+	var byteOrder binary.ByteOrder
+	switch string(header[0:2]) {
+	case tiffLittleEndian:
+		byteOrder = binary.LittleEndian
+	case tiffBigEndian:
+		byteOrder = binary.BigEndian
+	default:
+		return header, nil, domain.NewErrorf("Content is not a TIFF image")
+	}
+
+	if byteOrder.Uint16(header[2:4]) != tiffMagicNumber {
+		return header, nil, domain.NewErrorf("Content is not a TIFF image or uses an unsupported TIFF variant")
+	}
+
+	return header, byteOrder, nil
+}
+
+// readImageFileDirectoryOffsets follows the chain of image file directories
+// starting at the given offset and returns the offsets of all directories,
+// each of them describing a single page.
+func (s *documentPageSplitterTIFFImpl) readImageFileDirectoryOffsets(
+	content io.ReaderAt,
+	byteOrder binary.ByteOrder,
+	offset uint32,
+) ([]uint32, error) {
+	offsets := make([]uint32, 0, 1)
+	visited := make(map[uint32]bool)
+
+	for offset != 0 {
+		if visited[offset] || len(offsets) >= tiffMaxPageCount {
+			return nil, domain.NewErrorf("TIFF image contains an invalid chain of pages")
+		}
+
+		visited[offset] = true
+		offsets = append(offsets, offset)
+
+		var entryCount [2]byte
+		if _, err := content.ReadAt(entryCount[:], int64(offset)); err != nil {
+			return nil, s.contentError(err, "Failed to read TIFF image file directory")
+		}
+
+		var nextOffset [4]byte
+		nextOffsetPosition := int64(offset) + 2 + int64(byteOrder.Uint16(entryCount[:]))*tiffIFDEntrySize
+		if _, err := content.ReadAt(nextOffset[:], nextOffsetPosition); err != nil {
+			return nil, s.contentError(err, "Failed to read TIFF image file directory")
+		}
+
+		offset = byteOrder.Uint32(nextOffset[:])
+	}
+
+	return offsets, nil
+}
+
+// contentError returns a domain error with the given message in case the
+// given error is caused by truncated or malformed content. Other errors, e.g.
+// failing reads, are wrapped with the given message.
+func (s *documentPageSplitterTIFFImpl) contentError(err error, message string, args ...interface{}) error {
+	message = fmt.Sprintf(message, args...)
+
+	switch err.(type) {
+	case tiff.FormatError, tiff.UnsupportedError:
+		return domain.NewErrorf("%s: %s", message, err.Error())
+	}
+
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return domain.NewErrorf("%s: content is truncated", message)
+	}
+
+	return errors.Wrap(err, message)
+}
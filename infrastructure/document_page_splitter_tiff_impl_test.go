@@ -0,0 +1,134 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/tiff"
+)
+
+func TestSplitTIFF_WithMultiplePages(t *testing.T) {
+	content := multiPageTIFF([]byte{10, 20, 30})
+	splitter := NewTIFFDocumentPageSplitter()
+
+	pages := make([]image.Image, 0)
+	err := splitter.Split(bytes.NewReader(content), int64(len(content)), func(page io.Reader) error {
+		image, err := tiff.Decode(page)
+		pages = append(pages, image)
+		return err
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, pages, 3)
+	for i, value := range []uint8{10, 20, 30} {
+		gray, ok := pages[i].(*image.Gray)
+		assert.True(t, ok)
+		assert.Equal(t, value, gray.GrayAt(0, 0).Y)
+	}
+}
+
+func TestSplitTIFF_PreservesResolution(t *testing.T) {
+	content := multiPageTIFF([]byte{10, 20})
+
+	resolutions := make([]*tiffResolution, 0)
+	err := NewTIFFDocumentPageSplitter().Split(bytes.NewReader(content), int64(len(content)), func(page io.Reader) error {
+		data, err := ioutil.ReadAll(page)
+		resolutions = append(resolutions, readTIFFImageResolution(bytes.NewReader(data)))
+		return err
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []*tiffResolution{
+		{x: [2]uint32{100, 1}, y: [2]uint32{200, 1}, unit: tiffResolutionInch},
+		{x: [2]uint32{200, 1}, y: [2]uint32{400, 1}, unit: tiffResolutionInch},
+	}, resolutions)
+}
+
+func TestSplitTIFF_WithSinglePage(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	_ = tiff.Encode(buffer, image.NewGray(image.Rect(0, 0, 2, 2)), nil)
+	content := buffer.Bytes()
+
+	pageCount := 0
+	err := NewTIFFDocumentPageSplitter().Split(bytes.NewReader(content), int64(len(content)), func(page io.Reader) error {
+		pageCount++
+		_, err := ioutil.ReadAll(page)
+		return err
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, pageCount)
+}
+
+func TestSplitTIFF_WithInvalidContent(t *testing.T) {
+	valid := multiPageTIFF([]byte{10, 20})
+
+	for _, content := range [][]byte{[]byte("no TIFF image"), []byte("II"), valid[:len(valid)-8]} {
+		err := NewTIFFDocumentPageSplitter().Split(bytes.NewReader(content), int64(len(content)), func(io.Reader) error {
+			return nil
+		})
+
+		var contentErr *domain.Error
+		assert.True(t, errors.As(err, &contentErr), "%v", err)
+	}
+}
+
+// multiPageTIFF builds an uncompressed little endian TIFF image containing one
+// single pixel grayscale page per given value. The n-th page states a
+// resolution of n*100 dpi horizontally and n*200 dpi vertically.
+func multiPageTIFF(values []byte) []byte {
+	const entryCount = 11
+	const ifdSize = 2 + entryCount*12 + 4
+	const pageSize = ifdSize + 2*8 + 1
+
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("II")
+	_ = binary.Write(buffer, binary.LittleEndian, uint16(42))
+	_ = binary.Write(buffer, binary.LittleEndian, uint32(8))
+
+	for i, value := range values {
+		ifdOffset := uint32(8 + i*pageSize)
+		resolutionOffset := ifdOffset + ifdSize
+		pixelOffset := resolutionOffset + 2*8
+		nextOffset := uint32(0)
+		if i < len(values)-1 {
+			nextOffset = pixelOffset + 1
+		}
+
+		entries := [entryCount][3]uint32{
+			{256, 4, 1},                    // ImageWidth
+			{257, 4, 1},                    // ImageLength
+			{258, 4, 8},                    // BitsPerSample
+			{259, 4, 1},                    // Compression: none
+			{262, 4, 1},                    // PhotometricInterpretation: BlackIsZero
+			{273, 4, pixelOffset},          // StripOffsets
+			{278, 4, 1},                    // RowsPerStrip
+			{279, 4, 1},                    // StripByteCounts
+			{282, 5, resolutionOffset},     // XResolution
+			{283, 5, resolutionOffset + 8}, // YResolution
+			{296, 3, 2},                    // ResolutionUnit: inch
+		}
+
+		_ = binary.Write(buffer, binary.LittleEndian, uint16(entryCount))
+		for _, entry := range entries {
+			_ = binary.Write(buffer, binary.LittleEndian, uint16(entry[0]))
+			_ = binary.Write(buffer, binary.LittleEndian, uint16(entry[1]))
+			_ = binary.Write(buffer, binary.LittleEndian, uint32(1))
+			_ = binary.Write(buffer, binary.LittleEndian, entry[2])
+		}
+
+		_ = binary.Write(buffer, binary.LittleEndian, nextOffset)
+		_ = binary.Write(buffer, binary.LittleEndian, [4]uint32{uint32(i+1) * 100, 1, uint32(i+1) * 200, 1})
+		buffer.WriteByte(value)
+	}
+
+	return buffer.Bytes()
+}
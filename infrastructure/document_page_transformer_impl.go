@@ -0,0 +1,174 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/image/tiff"
+)
+
+// transformedContentKey defines the content key used for storing transformed
+// page content until its fingerprint is known.
+const transformedContentKey = domain.ContentKey("transformed.tmp")
+
+type documentPageTransformerImpl struct {
+	documents       domain.Documents
+	documentArchive domain.DocumentArchive
+}
+
+type settableImage interface {
+	image.Image
+	Set(x, y int, c color.Color)
+}
+
+// NewDocumentPageTransformerImpl returns a new page transformer using Go's
+// standard packages.
+func NewDocumentPageTransformerImpl(
+	documents domain.Documents,
+	documentArchive domain.DocumentArchive,
+) domain.DocumentPageTransformer {
+	return &documentPageTransformerImpl{
+		documents,
+		documentArchive,
+	}
+}
+
+func (t *documentPageTransformerImpl) RotatePage(
+	documentNumber domain.DocumentNumber,
+	pageNumber domain.PageNumber,
+	rotation domain.PageRotation,
+) error {
+	page, err := t.documents.GetPageByDocumentNumberAndPageNumber(documentNumber, pageNumber)
+	if err != nil {
+		return err
+	}
+
+	if page.Type != domain.PageTypeTIFF {
+		return errors.Newf("Page %d of document %d has not been preprocessed yet", pageNumber, documentNumber)
+	}
+
+	content, err := t.documentArchive.ReadContent(documentNumber, page.ContentKey())
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadAll(content)
+	content.Close()
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read page %d of document %d", pageNumber, documentNumber)
+	}
+
+	source, err := tiff.Decode(bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrapf(err, "Failed to decode page %d of document %d", pageNumber, documentNumber)
+	}
+
+	rotated, err := rotateImage(source, rotation)
+	if err != nil {
+		return err
+	}
+
+	buffer := &bytes.Buffer{}
+	resolution := readTIFFImageResolution(bytes.NewReader(data)).rotated(rotation)
+	if err := encodeTIFF(buffer, rotated, resolution); err != nil {
+		return errors.Wrap(err, "Failed to encode rotated page")
+	}
+
+	hasher := sha256.New()
+	oldContentKey := page.ContentKey()
+	err = t.documentArchive.StoreContent(
+		documentNumber,
+		transformedContentKey,
+		io.TeeReader(buffer, hasher),
+	)
+	if err != nil {
+		return err
+	}
+
+	page.Fingerprint = domain.Fingerprint(hex.EncodeToString(hasher.Sum(nil)))
+	perceptualHash := computePerceptualHash(rotated)
+	page.PerceptualHash = &perceptualHash
+	if err := t.documentArchive.MoveContent(documentNumber, transformedContentKey, page.ContentKey()); err != nil {
+		return err
+	}
+
+	// The page's orientation changed, so its text needs to be recognized again.
+	page.State = domain.PageStatePreprocessed
+	page.Text = ""
+	if _, err := t.documents.UpdatePage(documentNumber, page); err != nil {
+		return err
+	}
+
+	if oldContentKey != page.ContentKey() {
+		if err := t.documentArchive.DeleteContent(documentNumber, oldContentKey); err != nil {
+			log.Warnf("Failed to delete outdated content of document %d page %d: %v", documentNumber, pageNumber, err)
+		}
+	}
+
+	return nil
+}
+
+/* Helper Functions */
+
+// rotateImage rotates the given image clockwise, retaining its color model
+// wherever possible so no information is lost.
+func rotateImage(source image.Image, rotation domain.PageRotation) (image.Image, error) {
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	var target settableImage
+	switch rotation {
+	case domain.PageRotation90, domain.PageRotation270:
+		target = newImageLike(source, image.Rect(0, 0, height, width))
+	case domain.PageRotation180:
+		target = newImageLike(source, image.Rect(0, 0, width, height))
+	default:
+		return nil, errors.Newf("Unsupported rotation by %d degrees", rotation)
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pixel := source.At(bounds.Min.X+x, bounds.Min.Y+y)
+
+			switch rotation {
+			case domain.PageRotation90:
+				target.Set(height-1-y, x, pixel)
+			case domain.PageRotation180:
+				target.Set(width-1-x, height-1-y, pixel)
+			case domain.PageRotation270:
+				target.Set(y, width-1-x, pixel)
+			}
+		}
+	}
+
+	return target, nil
+}
+
+func newImageLike(source image.Image, bounds image.Rectangle) settableImage {
+	switch source := source.(type) {
+	case *image.Gray:
+		return image.NewGray(bounds)
+	case *image.Gray16:
+		return image.NewGray16(bounds)
+	case *image.Paletted:
+		return image.NewPaletted(bounds, source.Palette)
+	case *image.RGBA:
+		return image.NewRGBA(bounds)
+	case *image.RGBA64:
+		return image.NewRGBA64(bounds)
+	case *image.NRGBA:
+		return image.NewNRGBA(bounds)
+	case *image.CMYK:
+		return image.NewCMYK(bounds)
+	default:
+		return image.NewNRGBA64(bounds)
+	}
+}
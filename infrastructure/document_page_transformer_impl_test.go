@@ -0,0 +1,44 @@
+package infrastructure
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateImage(t *testing.T) {
+	// 3x2 image with a single marked pixel in the top left corner.
+	source := image.NewGray(image.Rect(0, 0, 3, 2))
+	source.SetGray(0, 0, color.Gray{Y: 255})
+
+	cases := []struct {
+		name           string
+		rotation       domain.PageRotation
+		expectedBounds image.Rectangle
+		expectedMarked image.Point
+	}{
+		{"90", domain.PageRotation90, image.Rect(0, 0, 2, 3), image.Pt(1, 0)},
+		{"180", domain.PageRotation180, image.Rect(0, 0, 3, 2), image.Pt(2, 1)},
+		{"270", domain.PageRotation270, image.Rect(0, 0, 2, 3), image.Pt(0, 2)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rotated, err := rotateImage(source, c.rotation)
+
+			assert.Nil(t, err)
+			assert.IsType(t, &image.Gray{}, rotated)
+			assert.Equal(t, c.expectedBounds, rotated.Bounds())
+			assert.Equal(t, color.Gray{Y: 255}, rotated.At(c.expectedMarked.X, c.expectedMarked.Y))
+		})
+	}
+}
+
+func TestRotateImage_WithUnsupportedRotation(t *testing.T) {
+	_, err := rotateImage(image.NewGray(image.Rect(0, 0, 1, 1)), domain.PageRotation(45))
+
+	assert.NotNil(t, err)
+}
@@ -42,6 +42,11 @@ func (p *documentPreprocessorImpl) PreprocessPage(
 		return err
 	}
 
+	if page == nil {
+		log.Infof("Page %d of document %d does not exist anymore; skipping preprocessing", pageNumber, documentNumber)
+		return nil
+	}
+
 	if page.Type == domain.PageTypeTIFF {
 		log.Debugf("Page already in correct format; skipping conversion for document %d page %d", documentNumber, pageNumber)
 	} else {
@@ -56,6 +61,16 @@ func (p *documentPreprocessorImpl) PreprocessPage(
 		return err
 	}
 
+	// A missing perceptual hash only excludes the page from the detection of
+	// similar pages, so it must not fail preprocessing.
+	page.PerceptualHash = nil
+	perceptualHash, err := p.ComputePerceptualHash(documentNumber, page)
+	if err != nil {
+		log.Warnf("Failed to compute perceptual hash of document %d page %d: %v", documentNumber, pageNumber, err)
+	} else {
+		page.PerceptualHash = &perceptualHash
+	}
+
 	// Update the page's content key (hash)
 	oldContentKey := page.ContentKey()
 	page.Type = domain.PageTypeTIFF
@@ -85,6 +100,28 @@ func (p *documentPreprocessorImpl) PreprocessPage(
 	return nil
 }
 
+func (p *documentPreprocessorImpl) ComputePerceptualHash(
+	documentNumber domain.DocumentNumber,
+	page *domain.DocumentPage,
+) (domain.PerceptualHash, error) {
+	content, err := p.documentArchive.ReadContent(
+		documentNumber,
+		page.ContentKey(),
+	)
+
+	if err != nil {
+		return 0, err
+	}
+	defer content.Close()
+
+	image, err := tiff.Decode(content)
+	if err != nil {
+		return 0, err
+	}
+
+	return computePerceptualHash(image), nil
+}
+
 func (p *documentPreprocessorImpl) convertPageContentToTIFF(
 	documentNumber domain.DocumentNumber,
 	page *domain.DocumentPage,
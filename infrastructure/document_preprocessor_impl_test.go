@@ -0,0 +1,65 @@
+package infrastructure
+
+import (
+	"bytes"
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/tiff"
+)
+
+func TestPreprocessPage(t *testing.T) {
+	instance := newTestInstance(t)
+	documents := NewDocuments(instance.db)
+	document := addTestDocument(t, instance)
+
+	content := &bytes.Buffer{}
+	assert.Nil(t, tiff.Encode(content, image.NewGray(image.Rect(0, 0, 2, 2)), nil))
+	page := &domain.DocumentPage{PageNumber: 1, Type: domain.PageTypeTIFF, Fingerprint: "upload"}
+	assert.Nil(t, instance.archive.StoreContent(document.DocumentNumber, page.ContentKey(), content))
+	_, err := documents.AddPage(document.DocumentNumber, page)
+	assert.Nil(t, err)
+
+	err = NewDocumentPreprocessorImpl(documents, instance.archive).PreprocessPage(document.DocumentNumber, 1)
+
+	assert.Nil(t, err)
+	preprocessed, err := documents.GetPageByDocumentNumberAndPageNumber(document.DocumentNumber, 1)
+	assert.Nil(t, err)
+	assert.NotEqual(t, domain.Fingerprint("upload"), preprocessed.Fingerprint)
+	assert.NotNil(t, preprocessed.PerceptualHash)
+}
+
+func TestPreprocessPage_WithUndecodableContent(t *testing.T) {
+	instance := newTestInstance(t)
+	documents := NewDocuments(instance.db)
+	document := addTestDocument(t, instance)
+
+	page := &domain.DocumentPage{PageNumber: 1, Type: domain.PageTypeTIFF, Fingerprint: "upload"}
+	assert.Nil(t, instance.archive.StoreContent(document.DocumentNumber, page.ContentKey(), strings.NewReader("no TIFF image")))
+	_, err := documents.AddPage(document.DocumentNumber, page)
+	assert.Nil(t, err)
+
+	err = NewDocumentPreprocessorImpl(documents, instance.archive).PreprocessPage(document.DocumentNumber, 1)
+
+	assert.Nil(t, err)
+	preprocessed, err := documents.GetPageByDocumentNumberAndPageNumber(document.DocumentNumber, 1)
+	assert.Nil(t, err)
+	assert.NotEqual(t, domain.Fingerprint("upload"), preprocessed.Fingerprint)
+	assert.Nil(t, preprocessed.PerceptualHash)
+
+	content, err := instance.archive.ReadContent(document.DocumentNumber, preprocessed.ContentKey())
+	assert.Nil(t, err)
+	content.Close()
+}
+
+func addTestDocument(t *testing.T, instance testInstance) *domain.Document {
+	owner, err := NewUsers(instance.db).Add(&domain.User{Username: "alice", Password: "hash", IsActive: true})
+	assert.Nil(t, err)
+	document, err := NewDocuments(instance.db).Add(&domain.Document{Title: "Scan", State: domain.DocumentStateEdited, Owner: owner})
+	assert.Nil(t, err)
+
+	return document
+}
@@ -0,0 +1,114 @@
+package infrastructure
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	pdftoppmExecutable = "pdftoppm"
+	rasterResolution   = "300"
+	rasterFilePrefix   = "page"
+
+	// pdftoppmExitCodeOpenFailed is the exit code of pdftoppm in case the
+	// input could not be opened as PDF document.
+	pdftoppmExitCodeOpenFailed = 1
+)
+
+type documentRasterizerPdftoppmImpl struct{}
+
+// NewPdftoppmDocumentRasterizer returns a new document rasterizer using
+// Poppler's pdftoppm for rendering PDF pages.
+func NewPdftoppmDocumentRasterizer() domain.DocumentRasterizer {
+	return &documentRasterizerPdftoppmImpl{}
+}
+
+func (r *documentRasterizerPdftoppmImpl) Rasterize(
+	content io.Reader,
+	handler func(page io.Reader) error,
+) error {
+	workingDirectory, err := ioutil.TempDir("", "paperless-rasterize-")
+	if err != nil {
+		return errors.Wrap(err, "Failed to create working directory")
+	}
+	defer os.RemoveAll(workingDirectory)
+
+	outputPrefix := filepath.Join(workingDirectory, rasterFilePrefix)
+	if err := r.rasterize(content, outputPrefix); err != nil {
+		return err
+	}
+
+	// pdftoppm pads page numbers with zeros, so lexical order is page order.
+	pageFiles, err := filepath.Glob(outputPrefix + "-*.tif")
+	if err != nil {
+		return err
+	}
+
+	if len(pageFiles) == 0 {
+		return domain.NewErrorf("Document does not contain any pages")
+	}
+
+	sort.Strings(pageFiles)
+	for _, pageFile := range pageFiles {
+		if err := r.handlePage(pageFile, handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* Helper Methods */
+
+func (r *documentRasterizerPdftoppmImpl) rasterize(content io.Reader, outputPrefix string) error {
+	path, err := exec.LookPath(pdftoppmExecutable)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to find '%s'", pdftoppmExecutable)
+	}
+
+	cmd := exec.Cmd{
+		Path: path,
+		Args: []string{
+			pdftoppmExecutable,
+			"-r", rasterResolution,
+			"-tiff",
+			"-tiffcompression", "deflate",
+			"-", outputPrefix,
+		},
+		Stdin:  content,
+		Stdout: log.StandardLogger().Out,
+		Stderr: log.StandardLogger().Out,
+	}
+
+	err = cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == pdftoppmExitCodeOpenFailed {
+		return domain.NewErrorf("Content is not a valid PDF document")
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "Failed to rasterize document")
+	}
+
+	return nil
+}
+
+func (r *documentRasterizerPdftoppmImpl) handlePage(
+	pageFile string,
+	handler func(page io.Reader) error,
+) error {
+	file, err := os.Open(pageFile)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to open rasterized page '%s'", pageFile)
+	}
+	defer file.Close()
+
+	return handler(file)
+}
@@ -9,6 +9,11 @@ import (
 	"github.com/concepts-system/go-paperless/errors"
 )
 
+// contentFingerprintLength defines the length of fingerprints derived from
+// content, i.e. hex encoded SHA-256 hashes, as opposed to the random UUIDs
+// identifying originals uploaded before.
+const contentFingerprintLength = 64
+
 type documentsGormImpl struct {
 	db     *Database
 	mapper *documentsGormMapper
@@ -18,18 +23,25 @@ type documentModel struct {
 	DocumentNumber uint `gorm:"not_null;primary_key"`
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
-	DeletedAt      *time.Time `gorm:"index"`
-
-	OwnerID     uint
-	Title       string     `gorm:"not_null;size:255"`
-	Date        *time.Time `gorm:"index"`
-	State       string     `gorm:"not_null;size:32"`
-	Fingerprint string     `gorm:"size:255;index"`
-	Type        string     `gorm:"not_null;size:32"`
-	IsInReview  bool
-
-	Owner *userModel
-	Pages []documentPageModel `gorm:"foreignkey:DocumentNumber"`
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+
+	OwnerID         uint
+	ClassID         *uint      `gorm:"index"`
+	CorrespondentID *uint      `gorm:"index"`
+	Title           string     `gorm:"not_null;size:255"`
+	Date            *time.Time `gorm:"index"`
+	State           string     `gorm:"not_null;size:32"`
+	Fingerprint     string     `gorm:"size:255;index"`
+	Type            string     `gorm:"not_null;size:32"`
+	Language        string     `gorm:"not_null;size:8"`
+	IsInReview      bool
+
+	Owner         *userModel
+	Class         *documentClassModel
+	Correspondent *correspondentModel
+	Pages         []documentPageModel        `gorm:"foreignkey:DocumentNumber"`
+	Tags          []tagModel                 `gorm:"many2many:document_tags;joinForeignKey:DocumentNumber;joinReferences:TagID"`
+	CustomFields  []documentCustomFieldModel `gorm:"foreignkey:DocumentNumber"`
 }
 
 type documentPageModel struct {
@@ -45,6 +57,12 @@ type documentPageModel struct {
 	Text        string     `gorm:"size:8192"`
 	IsInReview  bool
 
+	OriginalFingerprint string `gorm:"size:255"`
+	OriginalContentType string `gorm:"size:255"`
+	OriginalFileName    string `gorm:"size:255"`
+
+	PerceptualHash *int64
+
 	Document *documentModel `gorm:"foreignKey:DocumentNumber"`
 }
 
@@ -58,9 +76,17 @@ func (documentPageModel) TableName() string {
 
 // NewDocuments creates a new documents domain repository.
 func NewDocuments(db *Database) domain.Documents {
+	usersMapper := newUsersGormMapper()
+
 	return documentsGormImpl{
-		db:     db,
-		mapper: newDocumentsGormMapper(newUsersGormMapper()),
+		db: db,
+		mapper: newDocumentsGormMapper(
+			usersMapper,
+			newTagsGormMapper(usersMapper),
+			newCustomFieldsGormMapper(usersMapper),
+			newDocumentClassesGormMapper(usersMapper),
+			newCorrespondentsGormMapper(usersMapper),
+		),
 	}
 }
 
@@ -72,7 +98,13 @@ func (d documentsGormImpl) Find(page domain.PageRequest) ([]domain.Document, dom
 
 	err := d.db.
 		Preload("Owner").
-		Preload("Pages").
+		Preload("Pages", orderPagesByPageNumber).
+		Preload("Tags", orderTagsByName).
+		Preload("CustomFields", orderCustomFieldsByID).
+		Preload("CustomFields.CustomField").
+		Preload("Class").
+		Preload("Correspondent").
+		Order("documents.document_number asc").
 		Offset(page.Offset).
 		Limit(page.Size).
 		Find(&documents).
@@ -90,7 +122,12 @@ func (d documentsGormImpl) FindByDocumentNumbers(documentNumbers ...domain.Docum
 	var documents []documentModel
 
 	err := d.db.
-		Preload("Pages").
+		Preload("Pages", orderPagesByPageNumber).
+		Preload("Tags", orderTagsByName).
+		Preload("CustomFields", orderCustomFieldsByID).
+		Preload("CustomFields.CustomField").
+		Preload("Class").
+		Preload("Correspondent").
 		Find(&documents, documentNumbers).
 		Error
 
@@ -103,6 +140,7 @@ func (d documentsGormImpl) FindByDocumentNumbers(documentNumbers ...domain.Docum
 
 func (d documentsGormImpl) FindByUsername(
 	username domain.Name,
+	filter domain.DocumentFilter,
 	page domain.PageRequest,
 ) ([]domain.Document, domain.Count, error) {
 	var (
@@ -110,10 +148,17 @@ func (d documentsGormImpl) FindByUsername(
 		totalCount int64
 	)
 
-	err := d.db.
-		Preload("Pages").
+	err := d.filterDocuments(d.db.DB, filter).
+		Preload("Owner").
+		Preload("Pages", orderPagesByPageNumber).
+		Preload("Tags", orderTagsByName).
+		Preload("CustomFields", orderCustomFieldsByID).
+		Preload("CustomFields.CustomField").
+		Preload("Class").
+		Preload("Correspondent").
 		Joins("inner join users on users.id = documents.owner_id").
 		Where("users.username = ?", username).
+		Order("documents.document_number asc").
 		Offset(page.Offset).
 		Limit(page.Size).
 		Find(&documents).
@@ -184,6 +229,300 @@ func (d documentsGormImpl) Update(document *domain.Document) (*domain.Document,
 	return d.mapper.MapDocumentModelToDoaminEntity(documentModel), nil
 }
 
+func (d documentsGormImpl) UpdateMetadata(document *domain.Document) error {
+	metadata := d.mapper.MapDomainEntityToDocumentModel(0, document)
+	result := d.db.
+		Model(&documentModel{}).
+		Where("document_number = ?", metadata.DocumentNumber).
+		Updates(map[string]interface{}{
+			"title":            metadata.Title,
+			"date":             metadata.Date,
+			"class_id":         metadata.ClassID,
+			"correspondent_id": metadata.CorrespondentID,
+		})
+
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "Failed to update metadata of document %d", document.DocumentNumber)
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (d documentsGormImpl) UpdateContent(document *domain.Document) error {
+	result := d.db.
+		Model(&documentModel{}).
+		Where("document_number = ? AND deleted_at IS NULL", uint(document.DocumentNumber)).
+		Updates(map[string]interface{}{
+			"fingerprint": string(document.Fingerprint),
+			"type":        string(document.Type),
+		})
+
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "Failed to update content of document %d", document.DocumentNumber)
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (d documentsGormImpl) UpdateLanguage(document *domain.Document) error {
+	result := d.db.
+		Model(&documentModel{}).
+		Where("document_number = ? AND deleted_at IS NULL", uint(document.DocumentNumber)).
+		Update("language", string(document.Language))
+
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "Failed to update language of document %d", document.DocumentNumber)
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (d documentsGormImpl) UpdateTags(document *domain.Document) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("document_number = ?", uint(document.DocumentNumber)).
+			Delete(&documentTagModel{}).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		for _, tag := range document.Tags {
+			documentTag := documentTagModel{
+				DocumentNumber: uint(document.DocumentNumber),
+				TagID:          uint(tag.ID),
+			}
+
+			if err := tx.Create(&documentTag).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to update tags of document %d", document.DocumentNumber)
+	}
+
+	return nil
+}
+
+func (d documentsGormImpl) UpdateCustomFields(document *domain.Document) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("document_number = ?", uint(document.DocumentNumber)).
+			Delete(&documentCustomFieldModel{}).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		for _, value := range document.CustomFields {
+			documentCustomField := documentCustomFieldModel{
+				DocumentNumber: uint(document.DocumentNumber),
+				CustomFieldID:  uint(value.Field.ID),
+				Value:          value.Value,
+			}
+
+			if err := tx.Create(&documentCustomField).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to update custom fields of document %d", document.DocumentNumber)
+	}
+
+	return nil
+}
+
+func (d documentsGormImpl) Delete(document *domain.Document) error {
+	err := d.db.Delete(&documentModel{DocumentNumber: uint(document.DocumentNumber)}).Error
+	if err != nil {
+		return errors.Wrapf(err, "Failed to delete document %d", document.DocumentNumber)
+	}
+
+	return nil
+}
+
+func (d documentsGormImpl) FindTrashedByUsername(
+	username domain.Name,
+	page domain.PageRequest,
+) ([]domain.Document, domain.Count, error) {
+	var (
+		documents  []documentModel
+		totalCount int64
+	)
+
+	err := d.db.
+		Unscoped().
+		Preload("Pages", orderPagesByPageNumber).
+		Preload("Tags", orderTagsByName).
+		Preload("CustomFields", orderCustomFieldsByID).
+		Preload("CustomFields.CustomField").
+		Preload("Class").
+		Preload("Correspondent").
+		Joins("inner join users on users.id = documents.owner_id").
+		Where("users.username = ? AND documents.deleted_at IS NOT NULL", username).
+		Order("documents.deleted_at desc").
+		Offset(page.Offset).
+		Limit(page.Size).
+		Find(&documents).
+		Count(&totalCount).
+		Error
+
+	if err != nil {
+		return nil, -1, err
+	}
+
+	return d.mapper.MapDocumentModelsToDomainEntities(documents), domain.Count(totalCount), nil
+}
+
+func (d documentsGormImpl) FindTrashedBefore(deletedBefore time.Time) ([]domain.Document, error) {
+	var documents []documentModel
+
+	err := d.db.
+		Unscoped().
+		Preload("Owner").
+		Preload("Pages", orderPagesByPageNumber).
+		Preload("Tags", orderTagsByName).
+		Preload("CustomFields", orderCustomFieldsByID).
+		Preload("CustomFields.CustomField").
+		Preload("Class").
+		Preload("Correspondent").
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Find(&documents).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return d.mapper.MapDocumentModelsToDomainEntities(documents), nil
+}
+
+func (d documentsGormImpl) GetTrashedByDocumentNumber(documentNumber domain.DocumentNumber) (*domain.Document, error) {
+	document := documentModel{
+		DocumentNumber: uint(documentNumber),
+	}
+
+	err := d.db.
+		Unscoped().
+		Preload("Owner").
+		Preload("Pages", orderPagesByPageNumber).
+		Preload("Tags", orderTagsByName).
+		Preload("CustomFields", orderCustomFieldsByID).
+		Preload("CustomFields.CustomField").
+		Preload("Class").
+		Preload("Correspondent").
+		Where("deleted_at IS NOT NULL").
+		First(&document).
+		Error
+
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return d.mapper.MapDocumentModelToDoaminEntity(&document), nil
+}
+
+// Restore restores the given document from the trash. As reviews are aborted
+// for trashed documents, all review flags of the document and its pages are
+// reset, too.
+func (d documentsGormImpl) Restore(document *domain.Document) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Unscoped().
+			Model(&documentModel{}).
+			Where("document_number = ?", uint(document.DocumentNumber)).
+			Updates(map[string]interface{}{
+				"deleted_at":   nil,
+				"is_in_review": false,
+			}).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.
+			Model(&documentPageModel{}).
+			Where("document_number = ?", uint(document.DocumentNumber)).
+			Update("is_in_review", false).
+			Error
+	})
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to restore document %d", document.DocumentNumber)
+	}
+
+	return nil
+}
+
+func (d documentsGormImpl) Purge(document *domain.Document) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("document_number = ?", uint(document.DocumentNumber)).
+			Delete(&documentPageModel{}).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Where("document_number = ?", uint(document.DocumentNumber)).
+			Delete(&documentTagModel{}).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Where("document_number = ?", uint(document.DocumentNumber)).
+			Delete(&documentCustomFieldModel{}).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.
+			Unscoped().
+			Delete(&documentModel{DocumentNumber: uint(document.DocumentNumber)}).
+			Error
+	})
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to purge document %d", document.DocumentNumber)
+	}
+
+	return nil
+}
+
 func (d documentsGormImpl) GetPagesByDocumentNumber(
 	documentNumber domain.DocumentNumber,
 	page domain.PageRequest,
@@ -192,6 +531,7 @@ func (d documentsGormImpl) GetPagesByDocumentNumber(
 	var pageModels []documentPageModel
 	err := d.db.
 		Where("document_number = ?", documentNumber).
+		Order("page_number asc").
 		Offset(page.Offset).
 		Limit(page.Size).
 		Find(&pageModels).
@@ -260,8 +600,334 @@ func (d documentsGormImpl) UpdatePage(
 	return d.mapper.MapPageModelToDomainEntity(pageModel), nil
 }
 
+func (d documentsGormImpl) DeletePage(
+	documentNumber domain.DocumentNumber,
+	pageNumber domain.PageNumber,
+) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("document_number = ? AND page_number = ?", uint(documentNumber), uint(pageNumber)).
+			Delete(&documentPageModel{}).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		return d.closePageNumberGap(tx, uint(documentNumber), uint(pageNumber))
+	})
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to delete page %d of document %d", pageNumber, documentNumber)
+	}
+
+	return nil
+}
+
+func (d documentsGormImpl) ReorderPages(
+	documentNumber domain.DocumentNumber,
+	pageNumbers []domain.PageNumber,
+) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		// Move all pages out of the way first, so that the final numbering
+		// never collides with a page not yet renumbered.
+		offset := uint(len(pageNumbers))
+		for _, pageNumber := range pageNumbers {
+			if err := d.updatePageNumber(tx, uint(documentNumber), uint(pageNumber), uint(pageNumber)+offset); err != nil {
+				return err
+			}
+		}
+
+		for i, pageNumber := range pageNumbers {
+			if err := d.updatePageNumber(tx, uint(documentNumber), uint(pageNumber)+offset, uint(i+1)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to reorder pages of document %d", documentNumber)
+	}
+
+	return nil
+}
+
+func (d documentsGormImpl) MovePage(
+	sourceDocumentNumber domain.DocumentNumber,
+	pageNumber domain.PageNumber,
+	targetDocumentNumber domain.DocumentNumber,
+) (*domain.DocumentPage, error) {
+	var targetPageNumber uint
+
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		var pageCount int64
+		err := tx.
+			Model(&documentPageModel{}).
+			Where("document_number = ?", uint(targetDocumentNumber)).
+			Count(&pageCount).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		targetPageNumber = uint(pageCount) + 1
+		err = tx.
+			Model(&documentPageModel{}).
+			Where("document_number = ? AND page_number = ?", uint(sourceDocumentNumber), uint(pageNumber)).
+			Updates(map[string]interface{}{
+				"document_number": uint(targetDocumentNumber),
+				"page_number":     targetPageNumber,
+			}).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		return d.closePageNumberGap(tx, uint(sourceDocumentNumber), uint(pageNumber))
+	})
+
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Failed to move page %d of document %d to document %d",
+			pageNumber,
+			sourceDocumentNumber,
+			targetDocumentNumber,
+		)
+	}
+
+	return d.GetPageByDocumentNumberAndPageNumber(targetDocumentNumber, domain.PageNumber(targetPageNumber))
+}
+
+func (d documentsGormImpl) FindPagesByOriginalFingerprint(
+	username domain.Name,
+	fingerprint domain.Fingerprint,
+) ([]domain.DocumentPage, error) {
+	var pageModels []documentPageModel
+
+	err := d.userPages(username).
+		Preload("Document").
+		Where("document_pages.original_fingerprint = ?", string(fingerprint)).
+		Order("document_pages.document_number asc, document_pages.page_number asc").
+		Find(&pageModels).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve document pages")
+	}
+
+	return d.mapper.MapPageModelsToDomainEntities(pageModels), nil
+}
+
+func (d documentsGormImpl) FindPagesWithLegacyOriginalFingerprint() ([]domain.DocumentPage, error) {
+	var pageModels []documentPageModel
+
+	err := d.db.
+		Preload("Document", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("original_fingerprint <> '' AND LENGTH(original_fingerprint) <> ?", contentFingerprintLength).
+		Order("document_number asc, page_number asc").
+		Find(&pageModels).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve document pages")
+	}
+
+	return d.mapper.MapPageModelsToDomainEntities(pageModels), nil
+}
+
+func (d documentsGormImpl) UpdateOriginalFingerprint(
+	documentNumber domain.DocumentNumber,
+	fingerprint domain.Fingerprint,
+	newFingerprint domain.Fingerprint,
+) error {
+	err := d.db.
+		Model(&documentPageModel{}).
+		Where("document_number = ? AND original_fingerprint = ?", uint(documentNumber), string(fingerprint)).
+		Update("original_fingerprint", string(newFingerprint)).
+		Error
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to update original fingerprint for document %d", documentNumber)
+	}
+
+	return nil
+}
+
+func (d documentsGormImpl) FindPagesWithPerceptualHashByUsername(username domain.Name) ([]domain.DocumentPage, error) {
+	var pageModels []documentPageModel
+
+	err := d.userPages(username).
+		Preload("Document").
+		Where("document_pages.perceptual_hash IS NOT NULL").
+		Order("document_pages.document_number asc, document_pages.page_number asc").
+		Find(&pageModels).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve document pages")
+	}
+
+	return d.mapper.MapPageModelsToDomainEntities(pageModels), nil
+}
+
+func (d documentsGormImpl) FindPagesWithoutPerceptualHash() ([]domain.DocumentPage, error) {
+	var pageModels []documentPageModel
+
+	err := d.db.
+		Preload("Document", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("perceptual_hash IS NULL AND type = ?", string(domain.PageTypeTIFF)).
+		Order("document_number asc, page_number asc").
+		Find(&pageModels).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve document pages")
+	}
+
+	return d.mapper.MapPageModelsToDomainEntities(pageModels), nil
+}
+
+func (d documentsGormImpl) UpdatePagePerceptualHash(
+	documentNumber domain.DocumentNumber,
+	page *domain.DocumentPage,
+	perceptualHash domain.PerceptualHash,
+) error {
+	err := d.db.
+		Model(&documentPageModel{}).
+		Where(
+			"document_number = ? AND page_number = ? AND fingerprint = ?",
+			uint(documentNumber),
+			uint(page.PageNumber),
+			string(page.Fingerprint),
+		).
+		Update("perceptual_hash", int64(perceptualHash)).
+		Error
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to update page %d of document %d", page.PageNumber, documentNumber)
+	}
+
+	return nil
+}
+
+func (d documentsGormImpl) FindDuplicatePagesByUsername(username domain.Name) ([]domain.PageDuplicates, error) {
+	var pageModels []documentPageModel
+
+	duplicateFingerprints := d.userPages(username).
+		Select("document_pages.fingerprint").
+		Group("document_pages.fingerprint").
+		Having("count(*) > 1")
+
+	err := d.userPages(username).
+		Preload("Document").
+		Where("document_pages.fingerprint IN (?)", duplicateFingerprints).
+		Order("document_pages.fingerprint asc, document_pages.document_number asc, document_pages.page_number asc").
+		Find(&pageModels).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve duplicate document pages")
+	}
+
+	duplicates := make([]domain.PageDuplicates, 0)
+	for _, page := range d.mapper.MapPageModelsToDomainEntities(pageModels) {
+		last := len(duplicates) - 1
+		if last < 0 || duplicates[last].Fingerprint != page.Fingerprint {
+			duplicates = append(duplicates, domain.PageDuplicates{Fingerprint: page.Fingerprint})
+			last++
+		}
+
+		duplicates[last].Pages = append(duplicates[last].Pages, page)
+	}
+
+	return duplicates, nil
+}
+
 /* Helper Methods */
 
+// userPages returns a query for all pages of documents owned by the user with
+// the given username, excluding documents in the trash.
+func (d *documentsGormImpl) userPages(username domain.Name) *gorm.DB {
+	return d.db.
+		Model(&documentPageModel{}).
+		Joins("inner join documents on documents.document_number = document_pages.document_number").
+		Joins("inner join users on users.id = documents.owner_id").
+		Where("users.username = ? AND documents.deleted_at IS NULL", string(username))
+}
+
+// filterDocuments restricts the given query on documents to those matching
+// the given filter. Documents have to be tagged with all of the filter's tags.
+func (d *documentsGormImpl) filterDocuments(db *gorm.DB, filter domain.DocumentFilter) *gorm.DB {
+	if filter.ClassID != nil {
+		db = db.Where("documents.class_id = ?", uint(*filter.ClassID))
+	}
+
+	if filter.CorrespondentID != nil {
+		db = db.Where("documents.correspondent_id = ?", uint(*filter.CorrespondentID))
+	}
+
+	for _, tagID := range filter.TagIDs {
+		taggedDocumentNumbers := d.db.
+			Model(&documentTagModel{}).
+			Select("document_number").
+			Where("tag_id = ?", uint(tagID))
+
+		db = db.Where("documents.document_number IN (?)", taggedDocumentNumbers)
+	}
+
+	return db
+}
+
+func orderPagesByPageNumber(db *gorm.DB) *gorm.DB {
+	return db.Order("page_number asc")
+}
+
+func orderTagsByName(db *gorm.DB) *gorm.DB {
+	return db.Order("name asc")
+}
+
+func orderCustomFieldsByID(db *gorm.DB) *gorm.DB {
+	return db.Order("custom_field_id asc")
+}
+
+func (d *documentsGormImpl) updatePageNumber(tx *gorm.DB, documentNumber uint, pageNumber uint, newPageNumber uint) error {
+	return tx.
+		Model(&documentPageModel{}).
+		Where("document_number = ? AND page_number = ?", documentNumber, pageNumber).
+		Update("page_number", newPageNumber).
+		Error
+}
+
+// closePageNumberGap decrements the page numbers of all pages following the
+// given page number. Pages are renumbered one by one in ascending order so
+// the primary key is never violated in between.
+func (d *documentsGormImpl) closePageNumberGap(tx *gorm.DB, documentNumber uint, pageNumber uint) error {
+	var subsequentPageNumbers []uint
+	err := tx.
+		Model(&documentPageModel{}).
+		Where("document_number = ? AND page_number > ?", documentNumber, pageNumber).
+		Order("page_number asc").
+		Pluck("page_number", &subsequentPageNumbers).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	for _, subsequentPageNumber := range subsequentPageNumbers {
+		if err := d.updatePageNumber(tx, documentNumber, subsequentPageNumber, subsequentPageNumber-1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (d *documentsGormImpl) getDocumentOwner(document *domain.Document) (*userModel, error) {
 	var owner userModel
 	err := d.db.
@@ -286,7 +952,12 @@ func (d *documentsGormImpl) getDocumentModelByDocumentNumber(
 
 	err := d.db.
 		Preload("Owner").
-		Preload("Pages").
+		Preload("Pages", orderPagesByPageNumber).
+		Preload("Tags", orderTagsByName).
+		Preload("CustomFields", orderCustomFieldsByID).
+		Preload("CustomFields.CustomField").
+		Preload("Class").
+		Preload("Correspondent").
 		First(&document).
 		Error
 
@@ -0,0 +1,108 @@
+package infrastructure
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocuments_FindByUsername_PagesThroughOwnedDocuments(t *testing.T) {
+	db := newTestDatabase(t)
+	assert.Nil(t, db.Migrate())
+
+	users := NewUsers(db)
+	documents := NewDocuments(db)
+	owner, err := users.Add(&domain.User{Username: "alice", Forename: "Alice", Password: "hash", IsActive: true})
+	assert.Nil(t, err)
+	other, err := users.Add(&domain.User{Username: "bob", Password: "hash", IsActive: true})
+	assert.Nil(t, err)
+
+	expected := make([]domain.DocumentNumber, 0)
+	for i := 0; i < 5; i++ {
+		document, err := documents.Add(&domain.Document{Title: domain.Text(fmt.Sprint(i)), State: domain.DocumentStateEmpty, Owner: owner})
+		assert.Nil(t, err)
+		expected = append(expected, document.DocumentNumber)
+
+		_, err = documents.Add(&domain.Document{Title: domain.Text(fmt.Sprint(i)), State: domain.DocumentStateEmpty, Owner: other})
+		assert.Nil(t, err)
+	}
+
+	found := make([]domain.DocumentNumber, 0)
+	for offset := 0; offset < 6; offset += 2 {
+		batch, _, err := documents.FindByUsername("alice", domain.DocumentFilter{}, domain.PageRequest{Offset: offset, Size: 2})
+		assert.Nil(t, err)
+
+		for _, document := range batch {
+			found = append(found, document.DocumentNumber)
+			if assert.NotNil(t, document.Owner) {
+				assert.Equal(t, domain.Name("alice"), document.Owner.Username)
+				assert.Equal(t, domain.Name("Alice"), document.Owner.Forename)
+			}
+		}
+	}
+
+	assert.Equal(t, expected, found)
+}
+
+func TestDocuments_UpdateContent_LeavesOtherAttributesUntouched(t *testing.T) {
+	db := newTestDatabase(t)
+	assert.Nil(t, db.Migrate())
+
+	documents := NewDocuments(db)
+	owner, err := NewUsers(db).Add(&domain.User{Username: "alice", Password: "hash", IsActive: true})
+	assert.Nil(t, err)
+	document, err := documents.Add(&domain.Document{Title: "Draft", State: domain.DocumentStateIndexed, Owner: owner})
+	assert.Nil(t, err)
+
+	stale := *document
+	document.Title = "Invoice"
+	assert.Nil(t, documents.UpdateMetadata(document))
+
+	stale.Fingerprint = "generated"
+	stale.Type = domain.DocumentTypePDF
+	assert.Nil(t, documents.UpdateContent(&stale))
+
+	updated, err := documents.GetByDocumentNumber(document.DocumentNumber)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Text("Invoice"), updated.Title)
+	assert.Equal(t, domain.Fingerprint("generated"), updated.Fingerprint)
+	assert.Equal(t, domain.DocumentTypePDF, updated.Type)
+
+	assert.Nil(t, documents.Delete(updated))
+	stale.Fingerprint = "regenerated"
+	assert.NotNil(t, documents.UpdateContent(&stale))
+
+	trashed, _, err := documents.FindTrashedByUsername("alice", domain.PageRequest{Size: 10})
+	assert.Nil(t, err)
+	if assert.Len(t, trashed, 1) {
+		assert.Equal(t, domain.Fingerprint("generated"), trashed[0].Fingerprint)
+	}
+}
+
+func TestDocuments_UpdateLanguage_LeavesOtherAttributesUntouched(t *testing.T) {
+	db := newTestDatabase(t)
+	assert.Nil(t, db.Migrate())
+
+	documents := NewDocuments(db)
+	owner, err := NewUsers(db).Add(&domain.User{Username: "alice", Password: "hash", IsActive: true})
+	assert.Nil(t, err)
+	document, err := documents.Add(&domain.Document{Title: "Draft", State: domain.DocumentStateEdited, Owner: owner})
+	assert.Nil(t, err)
+
+	stale := *document
+	document.Title = "Rechnung"
+	assert.Nil(t, documents.UpdateMetadata(document))
+
+	stale.Language = "de"
+	assert.Nil(t, documents.UpdateLanguage(&stale))
+
+	updated, err := documents.GetByDocumentNumber(document.DocumentNumber)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Text("Rechnung"), updated.Title)
+	assert.Equal(t, domain.DocumentLanguage("de"), updated.Language)
+
+	assert.Nil(t, documents.Delete(updated))
+	assert.NotNil(t, documents.UpdateLanguage(&stale))
+}
@@ -1,14 +1,34 @@
 package infrastructure
 
-import "github.com/concepts-system/go-paperless/domain"
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/concepts-system/go-paperless/domain"
+)
 
 type documentsGormMapper struct {
-	usersMapper *usersGormMapper
+	usersMapper           *usersGormMapper
+	tagsMapper            *tagsGormMapper
+	customFieldsMapper    *customFieldsGormMapper
+	documentClassesMapper *documentClassesGormMapper
+	correspondentsMapper  *correspondentsGormMapper
 }
 
-func newDocumentsGormMapper(usersMapper *usersGormMapper) *documentsGormMapper {
+func newDocumentsGormMapper(
+	usersMapper *usersGormMapper,
+	tagsMapper *tagsGormMapper,
+	customFieldsMapper *customFieldsGormMapper,
+	documentClassesMapper *documentClassesGormMapper,
+	correspondentsMapper *correspondentsGormMapper,
+) *documentsGormMapper {
 	return &documentsGormMapper{
-		usersMapper: usersMapper,
+		usersMapper:           usersMapper,
+		tagsMapper:            tagsMapper,
+		customFieldsMapper:    customFieldsMapper,
+		documentClassesMapper: documentClassesMapper,
+		correspondentsMapper:  correspondentsMapper,
 	}
 }
 
@@ -28,11 +48,17 @@ func (m *documentsGormMapper) MapDocumentModelToDoaminEntity(
 		State:          domain.DocumentState(document.State),
 		Fingerprint:    domain.Fingerprint(document.Fingerprint),
 		Type:           domain.DocumentType(document.Type),
+		Language:       domain.DocumentLanguage(document.Language),
 		IsInReview:     document.IsInReview,
 		CreatedAt:      document.CreatedAt,
 		UpdatedAt:      document.UpdatedAt,
+		DeletedAt:      m.mapDeletedAtToTime(document.DeletedAt),
 		Owner:          m.usersMapper.MapUserModelToDomainEntity(document.Owner),
+		Class:          m.documentClassesMapper.MapDocumentClassModelToDomainEntity(document.Class),
+		Correspondent:  m.mapCorrespondentModelToDomainEntity(document.Correspondent),
 		Pages:          m.MapPageModelsToDomainEntities(document.Pages),
+		Tags:           m.tagsMapper.MapTagModelsToDomainEntities(document.Tags),
+		CustomFields:   m.customFieldsMapper.MapCustomFieldValueModelsToDomainEntities(document.CustomFields),
 	}
 }
 
@@ -58,17 +84,33 @@ func (m *documentsGormMapper) MapDomainEntityToDocumentModel(ownerID uint, docum
 		return nil
 	}
 
+	var classID *uint
+	if document.Class != nil {
+		id := uint(document.Class.ID)
+		classID = &id
+	}
+
+	var correspondentID *uint
+	if document.Correspondent != nil {
+		id := uint(document.Correspondent.ID)
+		correspondentID = &id
+	}
+
 	return &documentModel{
-		DocumentNumber: uint(document.DocumentNumber),
-		OwnerID:        ownerID,
-		Title:          string(document.Title),
-		Date:           document.Date,
-		State:          string(document.State),
-		Fingerprint:    string(document.Fingerprint),
-		Type:           string(document.Type),
-		IsInReview:     document.IsInReview,
-		CreatedAt:      document.CreatedAt,
-		UpdatedAt:      document.UpdatedAt,
+		DocumentNumber:  uint(document.DocumentNumber),
+		OwnerID:         ownerID,
+		ClassID:         classID,
+		CorrespondentID: correspondentID,
+		Title:           string(document.Title),
+		Date:            document.Date,
+		State:           string(document.State),
+		Fingerprint:     string(document.Fingerprint),
+		Type:            string(document.Type),
+		Language:        string(document.Language),
+		IsInReview:      document.IsInReview,
+		CreatedAt:       document.CreatedAt,
+		UpdatedAt:       document.UpdatedAt,
+		DeletedAt:       m.mapTimeToDeletedAt(document.DeletedAt),
 	}
 }
 
@@ -80,7 +122,7 @@ func (m *documentsGormMapper) MapDomainEntityToPageModel(
 		return nil
 	}
 
-	return &documentPageModel{
+	pageModel := &documentPageModel{
 		DocumentNumber: documentID,
 		PageNumber:     uint(page.PageNumber),
 		State:          string(page.State),
@@ -89,6 +131,19 @@ func (m *documentsGormMapper) MapDomainEntityToPageModel(
 		Text:           string(page.Text),
 		IsInReview:     page.IsInReview,
 	}
+
+	if page.PerceptualHash != nil {
+		perceptualHash := int64(*page.PerceptualHash)
+		pageModel.PerceptualHash = &perceptualHash
+	}
+
+	if page.Original != nil {
+		pageModel.OriginalFingerprint = string(page.Original.Fingerprint)
+		pageModel.OriginalContentType = page.Original.ContentType
+		pageModel.OriginalFileName = page.Original.FileName
+	}
+
+	return pageModel
 }
 
 // MapPageModelToDomainEntity maps the given page model to the corresponding domain entity.
@@ -104,7 +159,39 @@ func (m *documentsGormMapper) MapPageModelToDomainEntity(page *documentPageModel
 		Type:        domain.PageType(page.Type),
 		Fingerprint: domain.Fingerprint(page.Fingerprint),
 		IsInReview:  page.IsInReview,
-		Document:    m.MapDocumentModelToDoaminEntity(page.Document),
+		Original:    m.mapPageModelToPageOriginal(page),
+
+		PerceptualHash: m.mapPageModelToPerceptualHash(page),
+		Document:       m.MapDocumentModelToDoaminEntity(page.Document),
+	}
+}
+
+// mapCorrespondentModelToDomainEntity maps the given correspondent model
+// without its statistics, which are not needed in the context of a document.
+func (m *documentsGormMapper) mapCorrespondentModelToDomainEntity(
+	correspondent *correspondentModel,
+) *domain.Correspondent {
+	return m.correspondentsMapper.MapCorrespondentModelToDomainEntity(correspondent, correspondentStatistics{})
+}
+
+func (m *documentsGormMapper) mapPageModelToPerceptualHash(page *documentPageModel) *domain.PerceptualHash {
+	if page.PerceptualHash == nil {
+		return nil
+	}
+
+	perceptualHash := domain.PerceptualHash(*page.PerceptualHash)
+	return &perceptualHash
+}
+
+func (m *documentsGormMapper) mapPageModelToPageOriginal(page *documentPageModel) *domain.PageOriginal {
+	if page.OriginalFingerprint == "" {
+		return nil
+	}
+
+	return &domain.PageOriginal{
+		Fingerprint: domain.Fingerprint(page.OriginalFingerprint),
+		ContentType: page.OriginalContentType,
+		FileName:    page.OriginalFileName,
 	}
 }
 
@@ -123,3 +210,19 @@ func (m *documentsGormMapper) MapPageModelsToDomainEntities(pages []documentPage
 
 	return domainEntities
 }
+
+func (m *documentsGormMapper) mapDeletedAtToTime(deletedAt gorm.DeletedAt) *time.Time {
+	if !deletedAt.Valid {
+		return nil
+	}
+
+	return &deletedAt.Time
+}
+
+func (m *documentsGormMapper) mapTimeToDeletedAt(deletedAt *time.Time) gorm.DeletedAt {
+	if deletedAt == nil {
+		return gorm.DeletedAt{}
+	}
+
+	return gorm.DeletedAt{Time: *deletedAt, Valid: true}
+}
@@ -0,0 +1,776 @@
+package infrastructure
+
+import (
+	"archive/zip"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const backupBatchSize = 100
+
+type instanceBackupZIPImpl struct {
+	db              *Database
+	users           domain.Users
+	tags            domain.Tags
+	documentClasses domain.DocumentClasses
+	correspondents  domain.Correspondents
+	customFields    domain.CustomFields
+	documents       domain.Documents
+	documentArchive domain.DocumentArchive
+	documentIndex   domain.DocumentIndex
+	exporter        *documentExporterZIPImpl
+
+	// restoredContent keeps track of the content stored while restoring, so
+	// it can be removed again in case the restore fails.
+	restoredContent []restoredContent
+}
+
+type restoredContent struct {
+	documentNumber domain.DocumentNumber
+	contentKey     domain.ContentKey
+}
+
+// NewZIPInstanceBackup returns a new instance backup writing ZIP files in the
+// document export format, extended by all users and trashed documents. The
+// document index is rebuilt on restore rather than being backed up.
+func NewZIPInstanceBackup(
+	db *Database,
+	documentArchive domain.DocumentArchive,
+	documentIndex domain.DocumentIndex,
+) domain.InstanceBackup {
+	return &instanceBackupZIPImpl{
+		db:              db,
+		users:           NewUsers(db),
+		tags:            NewTags(db),
+		documentClasses: NewDocumentClasses(db),
+		correspondents:  NewCorrespondents(db),
+		customFields:    NewCustomFields(db),
+		documents:       NewDocuments(db),
+		documentArchive: documentArchive,
+		documentIndex:   documentIndex,
+		exporter:        &documentExporterZIPImpl{documentArchive},
+	}
+}
+
+// Backup reads the whole instance within a single read-only transaction, so
+// the backup reflects a consistent state even if the instance is in use.
+func (b *instanceBackupZIPImpl) Backup(w io.Writer) error {
+	options := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	return b.transaction(func(tx *instanceBackupZIPImpl) error {
+		return tx.backup(w)
+	}, options)
+}
+
+// Restore restores the backup within a single transaction, so a failed
+// restore leaves the instance empty and may simply be retried.
+func (b *instanceBackupZIPImpl) Restore(content io.ReaderAt, size int64) error {
+	err := b.transaction(func(tx *instanceBackupZIPImpl) error {
+		if err := tx.restore(content, size); err != nil {
+			tx.removeRestoredContent()
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	log.Info("Rebuilding document index")
+	return b.documentIndex.IndexAllDocuments()
+}
+
+/* Helper Methods */
+
+// transaction runs the given function with an instance backup whose
+// repositories are bound to a new transaction. The transaction is rolled back
+// in case the function returns an error.
+func (b *instanceBackupZIPImpl) transaction(
+	fn func(tx *instanceBackupZIPImpl) error,
+	options ...*sql.TxOptions,
+) error {
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewZIPInstanceBackup(
+			&Database{DB: tx, config: b.db.config},
+			b.documentArchive,
+			b.documentIndex,
+		).(*instanceBackupZIPImpl))
+	}, options...)
+}
+
+func (b *instanceBackupZIPImpl) backup(w io.Writer) error {
+	users, err := b.findAllUsers()
+	if err != nil {
+		return errors.Wrap(err, "Failed to retrieve users")
+	}
+
+	documents, err := b.findAllDocuments()
+	if err != nil {
+		return errors.Wrap(err, "Failed to retrieve documents")
+	}
+
+	manifest, entries := b.exporter.buildManifest(documents)
+	manifest.Users = make([]exportUser, len(users))
+	for i, user := range users {
+		manifest.Users[i] = exportUser{
+			Username: string(user.Username),
+			Password: string(user.Password),
+			Forename: string(user.Forename),
+			Surname:  string(user.Surname),
+			IsAdmin:  user.IsAdmin,
+			IsActive: user.IsActive,
+		}
+
+		tags, err := b.findAllUserTags(user.Username)
+		if err != nil {
+			return errors.Wrap(err, "Failed to retrieve tags")
+		}
+
+		for _, tag := range tags {
+			manifest.Tags = append(manifest.Tags, exportTag{
+				Owner: string(user.Username),
+				Name:  string(tag.Name),
+				Color: string(tag.Color),
+			})
+		}
+
+		documentClasses, err := b.findAllUserDocumentClasses(user.Username)
+		if err != nil {
+			return errors.Wrap(err, "Failed to retrieve document classes")
+		}
+
+		for _, documentClass := range documentClasses {
+			manifest.Classes = append(manifest.Classes, exportDocumentClass{
+				Owner: string(user.Username),
+				Name:  string(documentClass.Name),
+			})
+		}
+
+		correspondents, err := b.findAllUserCorrespondents(user.Username)
+		if err != nil {
+			return errors.Wrap(err, "Failed to retrieve correspondents")
+		}
+
+		for _, correspondent := range correspondents {
+			manifest.Correspondents = append(manifest.Correspondents, exportCorrespondent{
+				Owner: string(user.Username),
+				Name:  string(correspondent.Name),
+			})
+		}
+
+		customFields, err := b.findAllUserCustomFields(user.Username)
+		if err != nil {
+			return errors.Wrap(err, "Failed to retrieve custom fields")
+		}
+
+		for _, customField := range customFields {
+			manifest.CustomFields = append(manifest.CustomFields, exportCustomField{
+				Owner: string(user.Username),
+				Name:  string(customField.Name),
+				Type:  string(customField.Type),
+			})
+		}
+	}
+
+	log.Infof("Backing up %d users and %d documents", len(users), len(documents))
+	return b.exporter.writeZIP(w, manifest, entries)
+}
+
+func (b *instanceBackupZIPImpl) restore(content io.ReaderAt, size int64) error {
+	if err := b.ensureInstanceIsEmpty(); err != nil {
+		return err
+	}
+
+	zipReader, err := zip.NewReader(content, size)
+	if err != nil {
+		return errors.Wrap(err, "Failed to open backup")
+	}
+
+	files := make(map[string]*zip.File, len(zipReader.File))
+	for _, file := range zipReader.File {
+		files[file.Name] = file
+	}
+
+	manifest, err := b.readManifest(files)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range manifest.Users {
+		if err := b.restoreUser(user); err != nil {
+			return err
+		}
+	}
+
+	for _, tag := range manifest.Tags {
+		if _, err := b.restoreTag(domain.Name(tag.Owner), tag); err != nil {
+			return err
+		}
+	}
+
+	for _, documentClass := range manifest.Classes {
+		if _, err := b.restoreDocumentClass(domain.Name(documentClass.Owner), documentClass); err != nil {
+			return err
+		}
+	}
+
+	for _, correspondent := range manifest.Correspondents {
+		if _, err := b.restoreCorrespondent(domain.Name(correspondent.Owner), correspondent); err != nil {
+			return err
+		}
+	}
+
+	for _, customField := range manifest.CustomFields {
+		if _, err := b.restoreCustomField(domain.Name(customField.Owner), customField); err != nil {
+			return err
+		}
+	}
+
+	for _, document := range manifest.Documents {
+		if err := b.restoreDocument(files, document); err != nil {
+			return errors.Wrapf(err, "Failed to restore document %d", document.DocumentNumber)
+		}
+	}
+
+	return nil
+}
+
+// removeRestoredContent removes all content stored while restoring. Failures
+// are logged only, as the restore already failed.
+func (b *instanceBackupZIPImpl) removeRestoredContent() {
+	for _, content := range b.restoredContent {
+		if err := b.documentArchive.DeleteContent(content.documentNumber, content.contentKey); err != nil {
+			log.Warnf("Failed to remove restored content of document %d: %v", content.documentNumber, err)
+		}
+	}
+}
+
+func (b *instanceBackupZIPImpl) ensureInstanceIsEmpty() error {
+	_, userCount, err := b.users.Find(domain.PageRequest{Size: 1})
+	if err != nil {
+		return err
+	}
+
+	_, documentCount, err := b.documents.Find(domain.PageRequest{Size: 1})
+	if err != nil {
+		return err
+	}
+
+	if userCount > 0 || documentCount > 0 {
+		return errors.Newf(
+			"Backups can only be restored onto a fresh instance, but %d user(s) and %d document(s) already exist",
+			userCount,
+			documentCount,
+		)
+	}
+
+	return nil
+}
+
+func (b *instanceBackupZIPImpl) findAllUsers() ([]domain.User, error) {
+	users := make([]domain.User, 0)
+	pr := domain.PageRequest{Size: backupBatchSize}
+
+	for {
+		batch, _, err := b.users.Find(pr)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, batch...)
+		if len(batch) < backupBatchSize {
+			return users, nil
+		}
+
+		pr.Offset += backupBatchSize
+	}
+}
+
+func (b *instanceBackupZIPImpl) findAllUserTags(username domain.Name) ([]domain.Tag, error) {
+	tags := make([]domain.Tag, 0)
+	pr := domain.PageRequest{Size: backupBatchSize}
+
+	for {
+		batch, _, err := b.tags.FindByUsername(username, pr)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, batch...)
+		if len(batch) < backupBatchSize {
+			return tags, nil
+		}
+
+		pr.Offset += backupBatchSize
+	}
+}
+
+func (b *instanceBackupZIPImpl) findAllUserDocumentClasses(username domain.Name) ([]domain.DocumentClass, error) {
+	documentClasses := make([]domain.DocumentClass, 0)
+	pr := domain.PageRequest{Size: backupBatchSize}
+
+	for {
+		batch, _, err := b.documentClasses.FindByUsername(username, pr)
+		if err != nil {
+			return nil, err
+		}
+
+		documentClasses = append(documentClasses, batch...)
+		if len(batch) < backupBatchSize {
+			return documentClasses, nil
+		}
+
+		pr.Offset += backupBatchSize
+	}
+}
+
+func (b *instanceBackupZIPImpl) findAllUserCorrespondents(username domain.Name) ([]domain.Correspondent, error) {
+	correspondents := make([]domain.Correspondent, 0)
+	pr := domain.PageRequest{Size: backupBatchSize}
+
+	for {
+		batch, _, err := b.correspondents.FindByUsername(username, pr)
+		if err != nil {
+			return nil, err
+		}
+
+		correspondents = append(correspondents, batch...)
+		if len(batch) < backupBatchSize {
+			return correspondents, nil
+		}
+
+		pr.Offset += backupBatchSize
+	}
+}
+
+func (b *instanceBackupZIPImpl) findAllUserCustomFields(username domain.Name) ([]domain.CustomField, error) {
+	customFields := make([]domain.CustomField, 0)
+	pr := domain.PageRequest{Size: backupBatchSize}
+
+	for {
+		batch, _, err := b.customFields.FindByUsername(username, pr)
+		if err != nil {
+			return nil, err
+		}
+
+		customFields = append(customFields, batch...)
+		if len(batch) < backupBatchSize {
+			return customFields, nil
+		}
+
+		pr.Offset += backupBatchSize
+	}
+}
+
+// findAllDocuments returns all documents including the ones in the trash.
+func (b *instanceBackupZIPImpl) findAllDocuments() ([]domain.Document, error) {
+	documents := make([]domain.Document, 0)
+	pr := domain.PageRequest{Size: backupBatchSize}
+
+	for {
+		batch, _, err := b.documents.Find(pr)
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, batch...)
+		if len(batch) < backupBatchSize {
+			break
+		}
+
+		pr.Offset += backupBatchSize
+	}
+
+	trashedDocuments, err := b.documents.FindTrashedBefore(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return append(documents, trashedDocuments...), nil
+}
+
+func (b *instanceBackupZIPImpl) readManifest(files map[string]*zip.File) (*exportManifest, error) {
+	content, err := b.readFile(files, exportManifestFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest exportManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, errors.Wrap(err, "Failed to read backup manifest")
+	}
+
+	if manifest.Version != exportManifestVersion {
+		return nil, errors.Newf("Unsupported backup version %d", manifest.Version)
+	}
+
+	return &manifest, nil
+}
+
+func (b *instanceBackupZIPImpl) restoreUser(user exportUser) error {
+	_, err := b.users.Add(&domain.User{
+		Username: domain.Name(user.Username),
+		Password: domain.Password(user.Password),
+		Forename: domain.Name(user.Forename),
+		Surname:  domain.Name(user.Surname),
+		IsAdmin:  user.IsAdmin,
+		IsActive: user.IsActive,
+	})
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to restore user '%s'", user.Username)
+	}
+
+	return nil
+}
+
+func (b *instanceBackupZIPImpl) restoreDocument(files map[string]*zip.File, exported exportDocument) error {
+	if exported.Owner == nil {
+		return errors.New("Document has no owner")
+	}
+
+	owner, err := b.users.GetByUsername(domain.Name(exported.Owner.Username))
+	if err != nil {
+		return err
+	}
+
+	if owner == nil {
+		return errors.Newf("Owner '%s' does not exist", exported.Owner.Username)
+	}
+
+	var documentClass *domain.DocumentClass
+	if exported.Class != nil {
+		documentClass, err = b.restoreDocumentClass(owner.Username, *exported.Class)
+		if err != nil {
+			return err
+		}
+	}
+
+	var correspondent *domain.Correspondent
+	if exported.Correspondent != nil {
+		correspondent, err = b.restoreCorrespondent(owner.Username, *exported.Correspondent)
+		if err != nil {
+			return err
+		}
+	}
+
+	document, err := b.documents.Add(&domain.Document{
+		Title:         domain.Text(exported.Title),
+		Date:          exported.Date,
+		State:         domain.DocumentState(exported.State),
+		Fingerprint:   domain.Fingerprint(exported.Fingerprint),
+		Type:          domain.DocumentType(exported.Type),
+		Language:      domain.DocumentLanguage(exported.Language),
+		CreatedAt:     exported.CreatedAt,
+		UpdatedAt:     exported.UpdatedAt,
+		Owner:         owner,
+		Class:         documentClass,
+		Correspondent: correspondent,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if exported.Content != "" {
+		if err := b.restoreContent(files, exported.Content, document.DocumentNumber, document.ContentKey()); err != nil {
+			return err
+		}
+	}
+
+	originals := make(map[string]*domain.PageOriginal, len(exported.Originals))
+	for _, exportedOriginal := range exported.Originals {
+		original := &domain.PageOriginal{
+			Fingerprint: domain.Fingerprint(exportedOriginal.Fingerprint),
+			ContentType: exportedOriginal.ContentType,
+			FileName:    exportedOriginal.FileName,
+		}
+
+		if err := b.restoreContent(files, exportedOriginal.Content, document.DocumentNumber, original.ContentKey()); err != nil {
+			return err
+		}
+
+		originals[exportedOriginal.Fingerprint] = original
+	}
+
+	for _, exportedPage := range exported.Pages {
+		if err := b.restorePage(files, document.DocumentNumber, exportedPage, originals); err != nil {
+			return err
+		}
+	}
+
+	for _, exportedTag := range exported.Tags {
+		tag, err := b.restoreTag(owner.Username, exportedTag)
+		if err != nil {
+			return err
+		}
+
+		document.Tags = append(document.Tags, *tag)
+	}
+
+	if err := b.documents.UpdateTags(document); err != nil {
+		return err
+	}
+
+	for _, exportedValue := range exported.CustomFields {
+		customField, err := b.restoreCustomField(owner.Username, exportCustomField{
+			Name: exportedValue.Name,
+			Type: exportedValue.Type,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		document.CustomFields = append(document.CustomFields, domain.CustomFieldValue{
+			Field: *customField,
+			Value: exportedValue.Value,
+		})
+	}
+
+	if err := b.documents.UpdateCustomFields(document); err != nil {
+		return err
+	}
+
+	// Trashed documents are moved to the trash again, restarting their retention period.
+	if exported.DeletedAt != nil {
+		if err := b.documents.Delete(document); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Restored document %d as document %d", exported.DocumentNumber, document.DocumentNumber)
+	return nil
+}
+
+// restoreTag restores the given tag for the user with the given username,
+// reusing an existing tag with the same name.
+func (b *instanceBackupZIPImpl) restoreTag(username domain.Name, exported exportTag) (*domain.Tag, error) {
+	existingTag, err := b.tags.GetByUsernameAndName(username, domain.Name(exported.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	if existingTag != nil {
+		return existingTag, nil
+	}
+
+	owner, err := b.users.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner == nil {
+		return nil, errors.Newf("Owner '%s' does not exist", username)
+	}
+
+	tag, err := b.tags.Add(&domain.Tag{
+		Name:  domain.Name(exported.Name),
+		Color: domain.Color(exported.Color),
+		Owner: owner,
+	})
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to restore tag '%s'", exported.Name)
+	}
+
+	return tag, nil
+}
+
+func (b *instanceBackupZIPImpl) restorePage(
+	files map[string]*zip.File,
+	documentNumber domain.DocumentNumber,
+	exported exportPage,
+	originals map[string]*domain.PageOriginal,
+) error {
+	page := &domain.DocumentPage{
+		PageNumber:  domain.PageNumber(exported.PageNumber),
+		State:       domain.PageState(exported.State),
+		Type:        domain.PageType(exported.Type),
+		Fingerprint: domain.Fingerprint(exported.Fingerprint),
+		Original:    originals[exported.OriginalFingerprint],
+	}
+
+	if exported.Text != "" {
+		text, err := b.readFile(files, exported.Text)
+		if err != nil {
+			return err
+		}
+
+		page.Text = domain.Text(text)
+	}
+
+	if exported.PerceptualHash != "" {
+		perceptualHash, err := parsePerceptualHash(exported.PerceptualHash)
+		if err != nil {
+			return err
+		}
+
+		page.PerceptualHash = &perceptualHash
+	}
+
+	if err := b.restoreContent(files, exported.Content, documentNumber, page.ContentKey()); err != nil {
+		return err
+	}
+
+	_, err := b.documents.AddPage(documentNumber, page)
+	return err
+}
+
+func (b *instanceBackupZIPImpl) restoreContent(
+	files map[string]*zip.File,
+	name string,
+	documentNumber domain.DocumentNumber,
+	contentKey domain.ContentKey,
+) error {
+	file, ok := files[name]
+	if !ok {
+		return errors.Newf("Backup entry '%s' is missing", name)
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read backup entry '%s'", name)
+	}
+	defer content.Close()
+
+	if err := b.documentArchive.StoreContent(documentNumber, contentKey, content); err != nil {
+		return err
+	}
+
+	b.restoredContent = append(b.restoredContent, restoredContent{documentNumber, contentKey})
+	return nil
+}
+
+func (b *instanceBackupZIPImpl) readFile(files map[string]*zip.File, name string) ([]byte, error) {
+	file, ok := files[name]
+	if !ok {
+		return nil, errors.Newf("Backup entry '%s' is missing", name)
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read backup entry '%s'", name)
+	}
+	defer content.Close()
+
+	return ioutil.ReadAll(content)
+}
+
+// restoreDocumentClass restores the given document class for the user with
+// the given username, reusing an existing document class with the same name.
+func (b *instanceBackupZIPImpl) restoreDocumentClass(
+	username domain.Name,
+	exported exportDocumentClass,
+) (*domain.DocumentClass, error) {
+	existingDocumentClass, err := b.documentClasses.GetByUsernameAndName(username, domain.Name(exported.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	if existingDocumentClass != nil {
+		return existingDocumentClass, nil
+	}
+
+	owner, err := b.users.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner == nil {
+		return nil, errors.Newf("Owner '%s' does not exist", username)
+	}
+
+	documentClass, err := b.documentClasses.Add(&domain.DocumentClass{
+		Name:  domain.Name(exported.Name),
+		Owner: owner,
+	})
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to restore document class '%s'", exported.Name)
+	}
+
+	return documentClass, nil
+}
+
+// restoreCorrespondent restores the given correspondent for the user with the
+// given username, reusing an existing correspondent with the same name.
+func (b *instanceBackupZIPImpl) restoreCorrespondent(
+	username domain.Name,
+	exported exportCorrespondent,
+) (*domain.Correspondent, error) {
+	existingCorrespondent, err := b.correspondents.GetByUsernameAndName(username, domain.Name(exported.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	if existingCorrespondent != nil {
+		return existingCorrespondent, nil
+	}
+
+	owner, err := b.users.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner == nil {
+		return nil, errors.Newf("Owner '%s' does not exist", username)
+	}
+
+	correspondent, err := b.correspondents.Add(&domain.Correspondent{
+		Name:  domain.Name(exported.Name),
+		Owner: owner,
+	})
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to restore correspondent '%s'", exported.Name)
+	}
+
+	return correspondent, nil
+}
+
+// restoreCustomField restores the given custom field for the user with the
+// given username, reusing an existing custom field with the same name.
+func (b *instanceBackupZIPImpl) restoreCustomField(
+	username domain.Name,
+	exported exportCustomField,
+) (*domain.CustomField, error) {
+	existingCustomField, err := b.customFields.GetByUsernameAndName(username, domain.Name(exported.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	if existingCustomField != nil {
+		return existingCustomField, nil
+	}
+
+	owner, err := b.users.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner == nil {
+		return nil, errors.Newf("Owner '%s' does not exist", username)
+	}
+
+	customField, err := b.customFields.Add(&domain.CustomField{
+		Name:  domain.Name(exported.Name),
+		Type:  domain.CustomFieldType(exported.Type),
+		Owner: owner,
+	})
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to restore custom field '%s'", exported.Name)
+	}
+
+	return customField, nil
+}
@@ -0,0 +1,193 @@
+package infrastructure
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+type documentIndexStub struct {
+	domain.DocumentIndex
+}
+
+func (documentIndexStub) IndexAllDocuments() error {
+	return nil
+}
+
+type testInstance struct {
+	db          *Database
+	archivePath string
+	archive     domain.DocumentArchive
+	backup      domain.InstanceBackup
+}
+
+func newTestInstance(t *testing.T) testInstance {
+	db := newTestDatabase(t)
+	assert.Nil(t, db.Migrate())
+
+	archivePath := filepath.Join(t.TempDir(), "documents")
+	archive, err := NewDocumentArchiveFileSystemImpl(archivePath)
+	assert.Nil(t, err)
+
+	return testInstance{db, archivePath, archive, NewZIPInstanceBackup(db, archive, documentIndexStub{})}
+}
+
+func (i testInstance) count(t *testing.T, model interface{}) int64 {
+	var count int64
+	assert.Nil(t, i.db.Unscoped().Model(model).Count(&count).Error)
+
+	return count
+}
+
+func (i testInstance) countContent(t *testing.T) int {
+	count := 0
+	err := filepath.Walk(i.archivePath, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			count++
+		}
+
+		return err
+	})
+
+	assert.Nil(t, err)
+	return count
+}
+
+// newBackup fills a new instance with more users, tags and documents than
+// fit into two batches and returns a backup of it.
+func newBackup(t *testing.T, documentCount int) []byte {
+	instance := newTestInstance(t)
+	users := NewUsers(instance.db)
+	tags := NewTags(instance.db)
+	documents := NewDocuments(instance.db)
+
+	var owner *domain.User
+	for i := 0; i < 2*backupBatchSize+1; i++ {
+		user, err := users.Add(&domain.User{Username: domain.Name(fmt.Sprintf("user%03d", i)), Password: "hash", IsActive: true})
+		assert.Nil(t, err)
+		owner = user
+	}
+
+	for i := 0; i < 2*backupBatchSize+1; i++ {
+		_, err := tags.Add(&domain.Tag{Name: domain.Name(fmt.Sprintf("tag%03d", i)), Owner: owner})
+		assert.Nil(t, err)
+	}
+
+	for i := 0; i < documentCount; i++ {
+		document, err := documents.Add(&domain.Document{
+			Title: domain.Text(fmt.Sprintf("Document %d", i)),
+			State: domain.DocumentStateEdited,
+			Owner: owner,
+		})
+		assert.Nil(t, err)
+
+		page := &domain.DocumentPage{PageNumber: 1, Type: domain.PageTypeTIFF, Fingerprint: domain.Fingerprint(fmt.Sprint(i))}
+		assert.Nil(t, instance.archive.StoreContent(document.DocumentNumber, page.ContentKey(), strings.NewReader("tiff")))
+		_, err = documents.AddPage(document.DocumentNumber, page)
+		assert.Nil(t, err)
+
+		if i == 0 {
+			assert.Nil(t, documents.Delete(document))
+		}
+	}
+
+	var backup bytes.Buffer
+	assert.Nil(t, instance.backup.Backup(&backup))
+
+	return backup.Bytes()
+}
+
+// withoutEntry returns a copy of the given backup lacking the entry with the
+// given name.
+func withoutEntry(t *testing.T, backup []byte, name string) []byte {
+	zipReader, err := zip.NewReader(bytes.NewReader(backup), int64(len(backup)))
+	assert.Nil(t, err)
+
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	for _, file := range zipReader.File {
+		if file.Name == name {
+			continue
+		}
+
+		w, err := zipWriter.CreateHeader(&file.FileHeader)
+		assert.Nil(t, err)
+		r, err := file.Open()
+		assert.Nil(t, err)
+		_, err = io.Copy(w, r)
+		assert.Nil(t, err)
+		r.Close()
+	}
+
+	assert.Nil(t, zipWriter.Close())
+	return buffer.Bytes()
+}
+
+func TestZIPInstanceBackup_BackupAndRestore(t *testing.T) {
+	documentCount := 2*backupBatchSize + 50
+	backup := newBackup(t, documentCount)
+
+	instance := newTestInstance(t)
+	assert.Nil(t, instance.backup.Restore(bytes.NewReader(backup), int64(len(backup))))
+
+	assert.Equal(t, int64(2*backupBatchSize+1), instance.count(t, &userModel{}))
+	assert.Equal(t, int64(2*backupBatchSize+1), instance.count(t, &tagModel{}))
+	assert.Equal(t, int64(documentCount), instance.count(t, &documentModel{}))
+	assert.Equal(t, int64(documentCount), instance.count(t, &documentPageModel{}))
+
+	assert.Equal(t, documentCount, instance.countContent(t))
+
+	trashed, _, err := NewDocuments(instance.db).FindTrashedByUsername("user200", domain.PageRequest{Size: 10})
+	assert.Nil(t, err)
+	assert.Len(t, trashed, 1)
+	assert.Equal(t, domain.Text("Document 0"), trashed[0].Title)
+
+	content, err := instance.archive.ReadContent(trashed[0].DocumentNumber, trashed[0].Pages[0].ContentKey())
+	assert.Nil(t, err)
+	defer content.Close()
+	data, err := ioutil.ReadAll(content)
+	assert.Nil(t, err)
+	assert.Equal(t, "tiff", string(data))
+}
+
+func TestZIPInstanceBackup_Restore_RollsBackOnFailure(t *testing.T) {
+	backup := newBackup(t, 3)
+	incompleteBackup := withoutEntry(t, backup, "documents/3/pages/1.tiff")
+
+	instance := newTestInstance(t)
+	err := instance.backup.Restore(bytes.NewReader(incompleteBackup), int64(len(incompleteBackup)))
+	assert.NotNil(t, err)
+
+	assert.Equal(t, int64(0), instance.count(t, &userModel{}))
+	assert.Equal(t, int64(0), instance.count(t, &tagModel{}))
+	assert.Equal(t, int64(0), instance.count(t, &documentModel{}))
+	assert.Equal(t, int64(0), instance.count(t, &documentPageModel{}))
+
+	assert.Equal(t, 0, instance.countContent(t))
+
+	assert.Nil(t, instance.backup.Restore(bytes.NewReader(backup), int64(len(backup))))
+	assert.Equal(t, int64(3), instance.count(t, &documentModel{}))
+	assert.Equal(t, 3, instance.countContent(t))
+}
+
+func TestZIPInstanceBackup_Restore_RefusesNonEmptyInstance(t *testing.T) {
+	backup := newBackup(t, 1)
+
+	instance := newTestInstance(t)
+	_, err := NewUsers(instance.db).Add(&domain.User{Username: "admin", Password: "hash", IsActive: true})
+	assert.Nil(t, err)
+
+	err = instance.backup.Restore(bytes.NewReader(backup), int64(len(backup)))
+	assert.NotNil(t, err)
+	assert.Equal(t, int64(1), instance.count(t, &userModel{}))
+	assert.Equal(t, int64(0), instance.count(t, &documentModel{}))
+}
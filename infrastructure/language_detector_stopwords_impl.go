@@ -0,0 +1,72 @@
+package infrastructure
+
+import (
+	"strings"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/analysis/lang/de"
+	"github.com/blevesearch/bleve/v2/analysis/lang/en"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+// minLanguageStopWords defines the minimum number of stop words of a language
+// a text has to contain for being considered written in that language.
+const minLanguageStopWords = 3
+
+type stopWordLanguageDetector struct {
+	tokenizer analysis.Tokenizer
+	stopWords map[domain.DocumentLanguage]analysis.TokenMap
+}
+
+// NewStopWordLanguageDetector returns a language detector telling languages
+// apart by counting their stop words, e.g. "und" and "der" in German or "and"
+// and "the" in English. Stop words make up a large share of any text, so this
+// works well even for OCR text with many misspelled words.
+func NewStopWordLanguageDetector() (domain.LanguageDetector, error) {
+	stopWordLists := map[domain.DocumentLanguage][]byte{
+		domain.DocumentLanguageGerman:  de.GermanStopWords,
+		domain.DocumentLanguageEnglish: en.EnglishStopWords,
+	}
+
+	detector := &stopWordLanguageDetector{
+		tokenizer: unicode.NewUnicodeTokenizer(),
+		stopWords: make(map[domain.DocumentLanguage]analysis.TokenMap, len(stopWordLists)),
+	}
+
+	for language, stopWordList := range stopWordLists {
+		stopWords := analysis.NewTokenMap()
+		if err := stopWords.LoadBytes(stopWordList); err != nil {
+			return nil, errors.Wrapf(err, "Failed to load stop words of language '%s'", language)
+		}
+
+		detector.stopWords[language] = stopWords
+	}
+
+	return detector, nil
+}
+
+func (d *stopWordLanguageDetector) DetectLanguage(text domain.Text) domain.DocumentLanguage {
+	counts := make(map[domain.DocumentLanguage]int, len(d.stopWords))
+	for _, token := range d.tokenizer.Tokenize([]byte(text)) {
+		word := strings.ToLower(string(token.Term))
+		for language, stopWords := range d.stopWords {
+			if _, ok := stopWords[word]; ok {
+				counts[language]++
+			}
+		}
+	}
+
+	detected, maxCount := domain.DocumentLanguageUnknown, minLanguageStopWords-1
+	for language, count := range counts {
+		if count > maxCount {
+			detected, maxCount = language, count
+		} else if count == maxCount {
+			// Tell no language in case of a tie.
+			detected = domain.DocumentLanguageUnknown
+		}
+	}
+
+	return detected
+}
@@ -0,0 +1,28 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStopWordLanguageDetector_DetectLanguage(t *testing.T) {
+	detector, err := NewStopWordLanguageDetector()
+	assert.Nil(t, err)
+
+	tests := []struct {
+		text     string
+		expected domain.DocumentLanguage
+	}{
+		{"Sehr geehrte Damen und Herren, anbei erhalten Sie die Rechnung für den Monat März.", domain.DocumentLanguageGerman},
+		{"Dear customer, please find attached the invoice for the month of March.", domain.DocumentLanguageEnglish},
+		{"DIE RECHNUNG IST BIS ZUM 15.03. ZU BEZAHLEN", domain.DocumentLanguageGerman},
+		{"Rechnung 2021-0042", domain.DocumentLanguageUnknown},
+		{"", domain.DocumentLanguageUnknown},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, detector.DetectLanguage(domain.Text(test.text)), test.text)
+	}
+}
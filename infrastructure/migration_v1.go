@@ -1,25 +1,90 @@
 package infrastructure
 
 import (
+	"time"
+
 	gormigrate "github.com/go-gormigrate/gormigrate/v2"
 	"gorm.io/gorm"
 )
 
+// Migrations use snapshots of the models as of their version rather than the
+// models in use, so fresh databases go through the same steps as existing
+// ones and end up with the same schema.
+
+type userModelV1 struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time `gorm:"index"`
+	Username  string     `gorm:"size:32;not_null;index:username"`
+	Password  string     `gorm:"size:60;not_null"`
+	Surname   string     `gorm:"size:32;not_null"`
+	Forename  string     `gorm:"size:32;not_null"`
+	IsAdmin   bool       `gorm:"not_null"`
+	IsActive  bool       `gorm:"not_null"`
+}
+
+type documentModelV1 struct {
+	DocumentNumber uint `gorm:"not_null;primary_key"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      *time.Time `gorm:"index"`
+
+	OwnerID     uint
+	Title       string     `gorm:"not_null;size:255"`
+	Date        *time.Time `gorm:"index"`
+	State       string     `gorm:"not_null;size:32"`
+	Fingerprint string     `gorm:"size:255;index"`
+	Type        string     `gorm:"not_null;size:32"`
+	IsInReview  bool
+
+	Owner *userModelV1
+	Pages []documentPageModelV1 `gorm:"foreignkey:DocumentNumber"`
+}
+
+type documentPageModelV1 struct {
+	DocumentNumber uint `gorm:"not_null;primaryKey;autoIncrement:false"`
+	PageNumber     uint `gorm:"not_null;primaryKey;autoIncrement:false"`
+
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time `gorm:"index"`
+	State       string     `gorm:"not_null;size:32"`
+	Type        string     `gorm:"not_null;size:32"`
+	Fingerprint string     `gorm:"not_null;size:32"`
+	Text        string     `gorm:"size:8192"`
+	IsInReview  bool
+
+	Document *documentModelV1 `gorm:"foreignKey:DocumentNumber"`
+}
+
+func (userModelV1) TableName() string {
+	return "users"
+}
+
+func (documentModelV1) TableName() string {
+	return "documents"
+}
+
+func (documentPageModelV1) TableName() string {
+	return "document_pages"
+}
+
 var migrationV1 = gormigrate.Migration{
 	ID: "1",
 	Migrate: func(tx *gorm.DB) error {
 		// Users
-		if err := tx.AutoMigrate(&userModel{}); err != nil {
+		if err := tx.AutoMigrate(&userModelV1{}); err != nil {
 			return err
 		}
 
 		// Documents
-		if err := tx.AutoMigrate(&documentModel{}); err != nil {
+		if err := tx.AutoMigrate(&documentModelV1{}); err != nil {
 			return err
 		}
 
 		// Document Pages
-		if err := tx.AutoMigrate(&documentPageModel{}); err != nil {
+		if err := tx.AutoMigrate(&documentPageModelV1{}); err != nil {
 			return err
 		}
 
@@ -28,17 +93,17 @@ var migrationV1 = gormigrate.Migration{
 
 	Rollback: func(tx *gorm.DB) error {
 		// Document Pages
-		if err := tx.Migrator().DropTable(documentPageModel{}.TableName()); err != nil {
+		if err := tx.Migrator().DropTable(documentPageModelV1{}.TableName()); err != nil {
 			return err
 		}
 
 		// Documents
-		if err := tx.Migrator().DropTable(documentModel{}.TableName()); err != nil {
+		if err := tx.Migrator().DropTable(documentModelV1{}.TableName()); err != nil {
 			return err
 		}
 
 		// Users
-		if err := tx.Migrator().DropTable(userModel{}.TableName()); err != nil {
+		if err := tx.Migrator().DropTable(userModelV1{}.TableName()); err != nil {
 			return err
 		}
 
@@ -0,0 +1,48 @@
+package infrastructure
+
+import (
+	gormigrate "github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// documentPageModelV2 holds the columns added to document pages.
+type documentPageModelV2 struct {
+	OriginalFingerprint string `gorm:"size:255"`
+	OriginalContentType string `gorm:"size:255"`
+	OriginalFileName    string `gorm:"size:255"`
+}
+
+func (documentPageModelV2) TableName() string {
+	return "document_pages"
+}
+
+var pageOriginalColumns = []string{
+	"OriginalFingerprint",
+	"OriginalContentType",
+	"OriginalFileName",
+}
+
+var migrationV2 = gormigrate.Migration{
+	ID: "2",
+	Migrate: func(tx *gorm.DB) error {
+		// Document Pages: Originals
+		for _, column := range pageOriginalColumns {
+			if err := tx.Migrator().AddColumn(&documentPageModelV2{}, column); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		// Document Pages: Originals
+		for _, column := range pageOriginalColumns {
+			if err := tx.Migrator().DropColumn(&documentPageModelV2{}, column); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
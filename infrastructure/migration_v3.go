@@ -0,0 +1,28 @@
+package infrastructure
+
+import (
+	gormigrate "github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// documentPageModelV3 holds the column added to document pages.
+type documentPageModelV3 struct {
+	PerceptualHash *int64
+}
+
+func (documentPageModelV3) TableName() string {
+	return "document_pages"
+}
+
+var migrationV3 = gormigrate.Migration{
+	ID: "3",
+	Migrate: func(tx *gorm.DB) error {
+		// Document Pages: Perceptual Hash
+		return tx.Migrator().AddColumn(&documentPageModelV3{}, "PerceptualHash")
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		// Document Pages: Perceptual Hash
+		return tx.Migrator().DropColumn(&documentPageModelV3{}, "PerceptualHash")
+	},
+}
@@ -0,0 +1,64 @@
+package infrastructure
+
+import (
+	"time"
+
+	gormigrate "github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type tagModelV4 struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	OwnerID uint   `gorm:"not_null;uniqueIndex:idx_tags_owner_name"`
+	Name    string `gorm:"not_null;size:255;uniqueIndex:idx_tags_owner_name"`
+	Color   string `gorm:"size:7"`
+
+	Owner *userModelV1
+}
+
+type documentTagModelV4 struct {
+	DocumentNumber uint `gorm:"not_null;primaryKey;autoIncrement:false"`
+	TagID          uint `gorm:"not_null;primaryKey;autoIncrement:false"`
+}
+
+func (tagModelV4) TableName() string {
+	return "tags"
+}
+
+func (documentTagModelV4) TableName() string {
+	return "document_tags"
+}
+
+var migrationV4 = gormigrate.Migration{
+	ID: "4",
+	Migrate: func(tx *gorm.DB) error {
+		// Tags
+		if err := tx.AutoMigrate(&tagModelV4{}); err != nil {
+			return err
+		}
+
+		// Document Tags
+		if err := tx.AutoMigrate(&documentTagModelV4{}); err != nil {
+			return err
+		}
+
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		// Document Tags
+		if err := tx.Migrator().DropTable(documentTagModelV4{}.TableName()); err != nil {
+			return err
+		}
+
+		// Tags
+		if err := tx.Migrator().DropTable(tagModelV4{}.TableName()); err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"time"
+
+	gormigrate "github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type correspondentModelV5 struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	OwnerID uint   `gorm:"not_null;uniqueIndex:idx_correspondents_owner_name"`
+	Name    string `gorm:"not_null;size:255;uniqueIndex:idx_correspondents_owner_name"`
+
+	Owner *userModelV1
+}
+
+// documentModelV5 holds the column added to documents.
+type documentModelV5 struct {
+	CorrespondentID *uint `gorm:"index"`
+
+	Correspondent *correspondentModelV5
+}
+
+func (correspondentModelV5) TableName() string {
+	return "correspondents"
+}
+
+func (documentModelV5) TableName() string {
+	return "documents"
+}
+
+var migrationV5 = gormigrate.Migration{
+	ID: "5",
+	Migrate: func(tx *gorm.DB) error {
+		// Correspondents
+		if err := tx.AutoMigrate(&correspondentModelV5{}); err != nil {
+			return err
+		}
+
+		// Documents: Correspondent
+		if err := tx.Migrator().AddColumn(&documentModelV5{}, "CorrespondentID"); err != nil {
+			return err
+		}
+
+		if err := tx.Migrator().CreateIndex(&documentModelV5{}, "CorrespondentID"); err != nil {
+			return err
+		}
+
+		return createForeignKey(tx, &documentModelV5{}, "Correspondent")
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		// Documents: Correspondent
+		if err := tx.Migrator().DropColumn(&documentModelV5{}, "CorrespondentID"); err != nil {
+			return err
+		}
+
+		// Correspondents
+		if err := tx.Migrator().DropTable(correspondentModelV5{}.TableName()); err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"time"
+
+	gormigrate "github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type documentClassModelV6 struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	OwnerID uint   `gorm:"not_null;uniqueIndex:idx_document_classes_owner_name"`
+	Name    string `gorm:"not_null;size:255;uniqueIndex:idx_document_classes_owner_name"`
+
+	Owner *userModelV1
+}
+
+// documentModelV6 holds the column added to documents.
+type documentModelV6 struct {
+	ClassID *uint `gorm:"index"`
+
+	Class *documentClassModelV6
+}
+
+func (documentClassModelV6) TableName() string {
+	return "document_classes"
+}
+
+func (documentModelV6) TableName() string {
+	return "documents"
+}
+
+var migrationV6 = gormigrate.Migration{
+	ID: "6",
+	Migrate: func(tx *gorm.DB) error {
+		// Document Classes
+		if err := tx.AutoMigrate(&documentClassModelV6{}); err != nil {
+			return err
+		}
+
+		// Documents: Class
+		if err := tx.Migrator().AddColumn(&documentModelV6{}, "ClassID"); err != nil {
+			return err
+		}
+
+		if err := tx.Migrator().CreateIndex(&documentModelV6{}, "ClassID"); err != nil {
+			return err
+		}
+
+		return createForeignKey(tx, &documentModelV6{}, "Class")
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		// Documents: Class
+		if err := tx.Migrator().DropColumn(&documentModelV6{}, "ClassID"); err != nil {
+			return err
+		}
+
+		// Document Classes
+		if err := tx.Migrator().DropTable(documentClassModelV6{}.TableName()); err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"time"
+
+	gormigrate "github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type customFieldModelV7 struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	OwnerID uint   `gorm:"not_null;uniqueIndex:idx_custom_fields_owner_name"`
+	Name    string `gorm:"not_null;size:255;uniqueIndex:idx_custom_fields_owner_name"`
+	Type    string `gorm:"not_null;size:32"`
+
+	Owner *userModelV1
+}
+
+type documentCustomFieldModelV7 struct {
+	DocumentNumber uint   `gorm:"not_null;primaryKey;autoIncrement:false"`
+	CustomFieldID  uint   `gorm:"not_null;primaryKey;autoIncrement:false"`
+	Value          string `gorm:"not_null"`
+
+	CustomField *customFieldModelV7
+}
+
+func (customFieldModelV7) TableName() string {
+	return "custom_fields"
+}
+
+func (documentCustomFieldModelV7) TableName() string {
+	return "document_custom_fields"
+}
+
+var migrationV7 = gormigrate.Migration{
+	ID: "7",
+	Migrate: func(tx *gorm.DB) error {
+		// Custom Fields
+		if err := tx.AutoMigrate(&customFieldModelV7{}); err != nil {
+			return err
+		}
+
+		// Document Custom Fields
+		if err := tx.AutoMigrate(&documentCustomFieldModelV7{}); err != nil {
+			return err
+		}
+
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		// Document Custom Fields
+		if err := tx.Migrator().DropTable(documentCustomFieldModelV7{}.TableName()); err != nil {
+			return err
+		}
+
+		// Custom Fields
+		if err := tx.Migrator().DropTable(customFieldModelV7{}.TableName()); err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
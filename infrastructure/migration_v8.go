@@ -0,0 +1,28 @@
+package infrastructure
+
+import (
+	gormigrate "github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// documentModelV8 holds the column added to documents.
+type documentModelV8 struct {
+	Language string `gorm:"not_null;size:8"`
+}
+
+func (documentModelV8) TableName() string {
+	return "documents"
+}
+
+var migrationV8 = gormigrate.Migration{
+	ID: "8",
+	Migrate: func(tx *gorm.DB) error {
+		// Documents: Language
+		return tx.Migrator().AddColumn(&documentModelV8{}, "Language")
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		// Documents: Language
+		return tx.Migrator().DropColumn(&documentModelV8{}, "Language")
+	},
+}
@@ -7,8 +7,27 @@ import (
 
 var migrations = []*gormigrate.Migration{
 	&migrationV1,
+	&migrationV2,
+	&migrationV3,
+	&migrationV4,
+	&migrationV5,
+	&migrationV6,
+	&migrationV7,
+	&migrationV8,
 }
 
 func buildMigrator(db *gorm.DB) *gormigrate.Gormigrate {
 	return gormigrate.New(db, gormigrate.DefaultOptions, migrations)
 }
+
+// createForeignKey creates the foreign key constraint of the relation with the
+// given name of the given model. SQLite does not support adding constraints
+// to existing tables, nor does it enforce them by default, so they are left
+// out there.
+func createForeignKey(tx *gorm.DB, model interface{}, relation string) error {
+	if tx.Dialector.Name() == "sqlite" {
+		return nil
+	}
+
+	return tx.Migrator().CreateConstraint(model, relation)
+}
@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/concepts-system/go-paperless/config"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var migratedModels = []interface{}{
+	&userModel{},
+	&documentModel{},
+	&documentPageModel{},
+	&tagModel{},
+	&documentTagModel{},
+	&correspondentModel{},
+	&documentClassModel{},
+	&customFieldModel{},
+	&documentCustomFieldModel{},
+}
+
+func newTestDatabase(t *testing.T) *Database {
+	cfg := &config.Configuration{}
+	cfg.Database.Type = "sqlite3"
+	cfg.Database.URL = filepath.Join(t.TempDir(), "paperless.db")
+
+	db := NewDatabase(cfg)
+	assert.Nil(t, db.Connect())
+
+	return db
+}
+
+func TestMigrations_MatchModels(t *testing.T) {
+	db := newTestDatabase(t)
+	assert.Nil(t, db.Migrate())
+
+	for _, model := range migratedModels {
+		statement := &gorm.Statement{DB: db.DB}
+		assert.Nil(t, statement.Parse(model))
+
+		for _, field := range statement.Schema.Fields {
+			if field.DBName != "" {
+				assert.True(
+					t,
+					db.Migrator().HasColumn(model, field.DBName),
+					"%s.%s", statement.Schema.Table, field.DBName,
+				)
+			}
+		}
+	}
+}
+
+func TestMigrations_Rollback(t *testing.T) {
+	db := newTestDatabase(t)
+	assert.Nil(t, db.Migrate())
+
+	migrator := buildMigrator(db.DB)
+	for range migrations {
+		assert.Nil(t, migrator.RollbackLast())
+	}
+
+	for _, model := range migratedModels {
+		assert.False(t, db.Migrator().HasTable(model))
+	}
+}
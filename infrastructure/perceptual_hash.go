@@ -0,0 +1,54 @@
+package infrastructure
+
+import (
+	"fmt"
+	"image"
+	"strconv"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+	"golang.org/x/image/draw"
+)
+
+const (
+	perceptualHashWidth  = 9
+	perceptualHashHeight = 8
+)
+
+/* Helper Functions */
+
+// computePerceptualHash computes the difference hash (dHash) of the given
+// image. The image is scaled down to 9x8 gray pixels, with each bit of the
+// hash denoting whether a pixel is brighter than its right neighbour. Small
+// changes in alignment, scale or noise thus hardly affect the hash.
+func computePerceptualHash(source image.Image) domain.PerceptualHash {
+	thumbnail := image.NewGray(image.Rect(0, 0, perceptualHashWidth, perceptualHashHeight))
+	draw.BiLinear.Scale(thumbnail, thumbnail.Bounds(), source, source.Bounds(), draw.Src, nil)
+
+	var hash domain.PerceptualHash
+	for y := 0; y < perceptualHashHeight; y++ {
+		for x := 0; x < perceptualHashWidth-1; x++ {
+			hash <<= 1
+			if thumbnail.GrayAt(x, y).Y > thumbnail.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash
+}
+
+// formatPerceptualHash formats the given perceptual hash as hexadecimal
+// number, so it is not rounded by JSON parsers using floating point numbers.
+func formatPerceptualHash(hash domain.PerceptualHash) string {
+	return fmt.Sprintf("%016x", uint64(hash))
+}
+
+func parsePerceptualHash(value string) (domain.PerceptualHash, error) {
+	hash, err := strconv.ParseUint(value, 16, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Invalid perceptual hash '%s'", value)
+	}
+
+	return domain.PerceptualHash(hash), nil
+}
@@ -0,0 +1,50 @@
+package infrastructure
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/concepts-system/go-paperless/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testDocumentImage returns a gray image with a few dark blocks mimicking
+// text lines, shifted by the given offset.
+func testDocumentImage(offset int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 900, 800))
+	for y := 0; y < 800; y++ {
+		for x := 0; x < 900; x++ {
+			value := uint8(255)
+			if ((y-offset)/100)%2 == 1 && ((x-offset)/150)%2 == 0 {
+				value = 0
+			}
+
+			img.SetGray(x, y, color.Gray{Y: value})
+		}
+	}
+
+	return img
+}
+
+func TestComputePerceptualHash(t *testing.T) {
+	hash := computePerceptualHash(testDocumentImage(0))
+	shiftedHash := computePerceptualHash(testDocumentImage(4))
+	otherHash := computePerceptualHash(testDocumentImage(100))
+
+	assert.Equal(t, hash, computePerceptualHash(testDocumentImage(0)))
+	assert.GreaterOrEqual(t, hash.Similarity(shiftedHash), 0.9)
+	assert.Less(t, hash.Similarity(otherHash), 0.9)
+}
+
+func TestParsePerceptualHash(t *testing.T) {
+	for _, hash := range []domain.PerceptualHash{0, 42, 1<<63 + 1, ^domain.PerceptualHash(0)} {
+		parsed, err := parsePerceptualHash(formatPerceptualHash(hash))
+		assert.Nil(t, err)
+		assert.Equal(t, hash, parsed)
+	}
+
+	_, err := parsePerceptualHash("no hash")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,214 @@
+package infrastructure
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+type tagsGormImpl struct {
+	db     *Database
+	mapper *tagsGormMapper
+}
+
+type tagModel struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	OwnerID uint   `gorm:"not_null;uniqueIndex:idx_tags_owner_name"`
+	Name    string `gorm:"not_null;size:255;uniqueIndex:idx_tags_owner_name"`
+	Color   string `gorm:"size:7"`
+
+	Owner *userModel
+}
+
+// documentTagModel represents the assignment of a tag to a document.
+type documentTagModel struct {
+	DocumentNumber uint `gorm:"not_null;primaryKey;autoIncrement:false"`
+	TagID          uint `gorm:"not_null;primaryKey;autoIncrement:false"`
+}
+
+func (tagModel) TableName() string {
+	return "tags"
+}
+
+func (documentTagModel) TableName() string {
+	return "document_tags"
+}
+
+// NewTags creates a new tags domain repository.
+func NewTags(db *Database) domain.Tags {
+	return tagsGormImpl{
+		db:     db,
+		mapper: newTagsGormMapper(newUsersGormMapper()),
+	}
+}
+
+func (t tagsGormImpl) FindByUsername(
+	username domain.Name,
+	page domain.PageRequest,
+) ([]domain.Tag, domain.Count, error) {
+	var (
+		tags       []tagModel
+		totalCount int64
+	)
+
+	err := t.db.
+		Preload("Owner").
+		Joins("inner join users on users.id = tags.owner_id").
+		Where("users.username = ?", string(username)).
+		Order("tags.name asc").
+		Offset(page.Offset).
+		Limit(page.Size).
+		Find(&tags).
+		Count(&totalCount).
+		Error
+
+	if err != nil {
+		return nil, -1, err
+	}
+
+	return t.mapper.MapTagModelsToDomainEntities(tags), domain.Count(totalCount), nil
+}
+
+func (t tagsGormImpl) FindByIDs(ids ...domain.TagID) ([]domain.Tag, error) {
+	var tags []tagModel
+
+	if len(ids) == 0 {
+		return []domain.Tag{}, nil
+	}
+
+	err := t.db.
+		Preload("Owner").
+		Order("name asc").
+		Find(&tags, ids).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.mapper.MapTagModelsToDomainEntities(tags), nil
+}
+
+func (t tagsGormImpl) GetByID(id domain.TagID) (*domain.Tag, error) {
+	tag, err := t.getTagModelByID(uint(id))
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return t.mapper.MapTagModelToDomainEntity(tag), nil
+}
+
+func (t tagsGormImpl) GetByUsernameAndName(username domain.Name, name domain.Name) (*domain.Tag, error) {
+	var tag tagModel
+
+	err := t.db.
+		Preload("Owner").
+		Joins("inner join users on users.id = tags.owner_id").
+		Where("users.username = ? AND tags.name = ?", string(username), string(name)).
+		First(&tag).
+		Error
+
+	if err != nil {
+		if gorm.ErrRecordNotFound == err {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return t.mapper.MapTagModelToDomainEntity(&tag), nil
+}
+
+func (t tagsGormImpl) Add(tag *domain.Tag) (*domain.Tag, error) {
+	owner, err := t.getTagOwner(tag)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create tag")
+	}
+
+	model := t.mapper.MapDomainEntityToTagModel(owner.ID, tag)
+	if err := t.db.Create(model).Error; err != nil {
+		return nil, errors.Wrap(err, "Failed to create tag")
+	}
+
+	return t.GetByID(domain.TagID(model.ID))
+}
+
+func (t tagsGormImpl) Update(tag *domain.Tag) (*domain.Tag, error) {
+	originalModel, err := t.getTagModelByID(uint(tag.ID))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to update tag")
+	}
+
+	model := t.mapper.MapDomainEntityToTagModel(originalModel.OwnerID, tag)
+	model.CreatedAt = originalModel.CreatedAt
+	if err := t.db.Save(model).Error; err != nil {
+		return nil, errors.Wrap(err, "Failed to update tag")
+	}
+
+	return t.GetByID(tag.ID)
+}
+
+func (t tagsGormImpl) Delete(tag *domain.Tag) error {
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("tag_id = ?", uint(tag.ID)).
+			Delete(&documentTagModel{}).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&tagModel{ID: uint(tag.ID)}).Error
+	})
+
+	if err != nil {
+		return errors.Wrapf(err, "Failed to delete tag %d", tag.ID)
+	}
+
+	return nil
+}
+
+/* Helper Methods */
+
+func (t *tagsGormImpl) getTagOwner(tag *domain.Tag) (*userModel, error) {
+	var owner userModel
+	err := t.db.
+		Select("id").
+		Where("username = ?", string(tag.Owner.Username)).
+		First(&owner).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to find user with username '%s'", string(tag.Owner.Username))
+	}
+
+	return &owner, nil
+}
+
+func (t *tagsGormImpl) getTagModelByID(id uint) (*tagModel, error) {
+	tag := tagModel{
+		ID: id,
+	}
+
+	err := t.db.
+		Preload("Owner").
+		First(&tag).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
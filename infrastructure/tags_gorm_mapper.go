@@ -0,0 +1,59 @@
+package infrastructure
+
+import "github.com/concepts-system/go-paperless/domain"
+
+type tagsGormMapper struct {
+	usersMapper *usersGormMapper
+}
+
+func newTagsGormMapper(usersMapper *usersGormMapper) *tagsGormMapper {
+	return &tagsGormMapper{
+		usersMapper: usersMapper,
+	}
+}
+
+// MapTagModelToDomainEntity maps the given tag model to the corresponding
+// domain entity.
+func (m *tagsGormMapper) MapTagModelToDomainEntity(tag *tagModel) *domain.Tag {
+	if tag == nil {
+		return nil
+	}
+
+	return &domain.Tag{
+		ID:    domain.TagID(tag.ID),
+		Name:  domain.Name(tag.Name),
+		Color: domain.Color(tag.Color),
+		Owner: m.usersMapper.MapUserModelToDomainEntity(tag.Owner),
+	}
+}
+
+// MapTagModelsToDomainEntities maps the given list of tag models to a list
+// containing the corresponding domain entities.
+func (m *tagsGormMapper) MapTagModelsToDomainEntities(tags []tagModel) []domain.Tag {
+	if tags == nil {
+		return nil
+	}
+
+	domainEntities := make([]domain.Tag, len(tags))
+
+	for i, tag := range tags {
+		domainEntities[i] = *m.MapTagModelToDomainEntity(&tag)
+	}
+
+	return domainEntities
+}
+
+// MapDomainEntityToTagModel maps the given domain entity to the corresponding
+// tag model.
+func (m *tagsGormMapper) MapDomainEntityToTagModel(ownerID uint, tag *domain.Tag) *tagModel {
+	if tag == nil {
+		return nil
+	}
+
+	return &tagModel{
+		ID:      uint(tag.ID),
+		OwnerID: ownerID,
+		Name:    string(tag.Name),
+		Color:   string(tag.Color),
+	}
+}
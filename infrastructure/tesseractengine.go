@@ -42,6 +42,11 @@ func (t *TesseractOcrEngine) ScanPage(
 		return err
 	}
 
+	if page == nil {
+		log.Infof("Page %d of document %d does not exist anymore; skipping scan", pageNumber, documentNumber)
+		return nil
+	}
+
 	content, err := t.documentArchive.ReadContent(page.Document.DocumentNumber, page.ContentKey())
 	if err != nil {
 		return err
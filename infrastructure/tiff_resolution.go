@@ -0,0 +1,156 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"io"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"golang.org/x/image/tiff"
+)
+
+const (
+	tiffTagXResolution    = 282
+	tiffTagYResolution    = 283
+	tiffTagResolutionUnit = 296
+	tiffTypeShort         = 3
+	tiffTypeRational      = 5
+	tiffResolutionInch    = 2
+)
+
+// tiffResolution represents the resolution of a TIFF image as stated by its
+// image file directory, i.e. pixels per resolution unit as rational numbers.
+type tiffResolution struct {
+	x    [2]uint32
+	y    [2]uint32
+	unit uint16
+}
+
+// rotated returns the resolution of the image after being rotated by the
+// given rotation.
+func (r *tiffResolution) rotated(rotation domain.PageRotation) *tiffResolution {
+	if r == nil {
+		return nil
+	}
+
+	rotated := *r
+	if rotation == domain.PageRotation90 || rotation == domain.PageRotation270 {
+		rotated.x, rotated.y = r.y, r.x
+	}
+
+	return &rotated
+}
+
+// encodeTIFF encodes the given image as deflate compressed TIFF image. As the
+// encoder always states a resolution of 72 dpi, the given resolution, if any,
+// is written over the encoder's one.
+func encodeTIFF(w io.Writer, image image.Image, resolution *tiffResolution) error {
+	buffer := &bytes.Buffer{}
+	if err := tiff.Encode(buffer, image, &tiff.Options{Compression: tiff.Deflate}); err != nil {
+		return err
+	}
+
+	content := buffer.Bytes()
+	if resolution != nil {
+		// The encoder always writes little endian images with a single image
+		// file directory.
+		byteOrder := binary.LittleEndian
+		offset := byteOrder.Uint32(content[tiffIFDOffsetIndex:])
+		entryCount := int(byteOrder.Uint16(content[offset:]))
+
+		for i := 0; i < entryCount; i++ {
+			entry := content[int(offset)+2+i*tiffIFDEntrySize:]
+			value := entry[8:tiffIFDEntrySize]
+
+			switch byteOrder.Uint16(entry[0:2]) {
+			case tiffTagXResolution:
+				putTIFFRational(content[byteOrder.Uint32(value):], byteOrder, resolution.x)
+			case tiffTagYResolution:
+				putTIFFRational(content[byteOrder.Uint32(value):], byteOrder, resolution.y)
+			case tiffTagResolutionUnit:
+				byteOrder.PutUint16(value, resolution.unit)
+			}
+		}
+	}
+
+	_, err := w.Write(content)
+	return err
+}
+
+// readTIFFImageResolution returns the resolution of the first page of the
+// given TIFF image or nil in case it cannot be determined.
+func readTIFFImageResolution(content io.ReaderAt) *tiffResolution {
+	var header [tiffHeaderSize]byte
+	if _, err := content.ReadAt(header[:], 0); err != nil {
+		return nil
+	}
+
+	var byteOrder binary.ByteOrder
+	switch string(header[0:2]) {
+	case tiffLittleEndian:
+		byteOrder = binary.LittleEndian
+	case tiffBigEndian:
+		byteOrder = binary.BigEndian
+	default:
+		return nil
+	}
+
+	return readTIFFResolution(content, byteOrder, byteOrder.Uint32(header[tiffIFDOffsetIndex:]))
+}
+
+// readTIFFResolution returns the resolution stated by the image file directory
+// at the given offset or nil in case it is missing or malformed. A missing
+// resolution unit defaults to inches as defined by the TIFF specification.
+func readTIFFResolution(content io.ReaderAt, byteOrder binary.ByteOrder, offset uint32) *tiffResolution {
+	var entryCount [2]byte
+	if _, err := content.ReadAt(entryCount[:], int64(offset)); err != nil {
+		return nil
+	}
+
+	resolution := &tiffResolution{unit: tiffResolutionInch}
+	found := 0
+	for i := int64(0); i < int64(byteOrder.Uint16(entryCount[:])); i++ {
+		var entry [tiffIFDEntrySize]byte
+		if _, err := content.ReadAt(entry[:], int64(offset)+2+i*tiffIFDEntrySize); err != nil {
+			return nil
+		}
+
+		tag, dataType := byteOrder.Uint16(entry[0:2]), byteOrder.Uint16(entry[2:4])
+		switch {
+		case tag == tiffTagXResolution && dataType == tiffTypeRational:
+			if !readTIFFRational(content, byteOrder, byteOrder.Uint32(entry[8:]), &resolution.x) {
+				return nil
+			}
+			found++
+		case tag == tiffTagYResolution && dataType == tiffTypeRational:
+			if !readTIFFRational(content, byteOrder, byteOrder.Uint32(entry[8:]), &resolution.y) {
+				return nil
+			}
+			found++
+		case tag == tiffTagResolutionUnit && dataType == tiffTypeShort:
+			resolution.unit = byteOrder.Uint16(entry[8:])
+		}
+	}
+
+	if found != 2 {
+		return nil
+	}
+
+	return resolution
+}
+
+func readTIFFRational(content io.ReaderAt, byteOrder binary.ByteOrder, offset uint32, rational *[2]uint32) bool {
+	var value [8]byte
+	if _, err := content.ReadAt(value[:], int64(offset)); err != nil {
+		return false
+	}
+
+	rational[0], rational[1] = byteOrder.Uint32(value[0:4]), byteOrder.Uint32(value[4:8])
+	return rational[1] != 0
+}
+
+func putTIFFRational(b []byte, byteOrder binary.ByteOrder, rational [2]uint32) {
+	byteOrder.PutUint32(b[0:4], rational[0])
+	byteOrder.PutUint32(b[4:8], rational[1])
+}
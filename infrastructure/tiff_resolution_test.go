@@ -0,0 +1,46 @@
+package infrastructure
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/tiff"
+)
+
+func TestEncodeTIFF_WithResolution(t *testing.T) {
+	resolution := &tiffResolution{x: [2]uint32{300, 1}, y: [2]uint32{600, 1}, unit: 3}
+	buffer := &bytes.Buffer{}
+
+	err := encodeTIFF(buffer, image.NewGray(image.Rect(0, 0, 2, 1)), resolution)
+
+	assert.Nil(t, err)
+	assert.Equal(t, resolution, readTIFFImageResolution(bytes.NewReader(buffer.Bytes())))
+	decoded, err := tiff.Decode(bytes.NewReader(buffer.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, image.Rect(0, 0, 2, 1), decoded.Bounds())
+}
+
+func TestEncodeTIFF_WithoutResolution(t *testing.T) {
+	buffer := &bytes.Buffer{}
+
+	err := encodeTIFF(buffer, image.NewGray(image.Rect(0, 0, 1, 1)), nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t,
+		&tiffResolution{x: [2]uint32{72, 1}, y: [2]uint32{72, 1}, unit: tiffResolutionInch},
+		readTIFFImageResolution(bytes.NewReader(buffer.Bytes())),
+	)
+}
+
+func TestTIFFResolution_Rotated(t *testing.T) {
+	resolution := &tiffResolution{x: [2]uint32{300, 1}, y: [2]uint32{600, 1}, unit: tiffResolutionInch}
+	swapped := &tiffResolution{x: [2]uint32{600, 1}, y: [2]uint32{300, 1}, unit: tiffResolutionInch}
+
+	assert.Equal(t, swapped, resolution.rotated(domain.PageRotation90))
+	assert.Equal(t, resolution, resolution.rotated(domain.PageRotation180))
+	assert.Equal(t, swapped, resolution.rotated(domain.PageRotation270))
+	assert.Nil(t, (*tiffResolution)(nil).rotated(domain.PageRotation90))
+}
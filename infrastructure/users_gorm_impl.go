@@ -58,7 +58,7 @@ func (u usersGormImpl) Find(page domain.PageRequest) ([]domain.User, domain.Coun
 	)
 
 	err := u.db.
-		Order("surname, forename").
+		Order("surname, forename, id").
 		Offset(page.Offset).
 		Limit(page.Size).
 		Find(&users).
@@ -25,6 +25,9 @@ var (
 
 const (
 	documentsDirectory = "documents"
+
+	commandBackup  = "backup"
+	commandRestore = "restore"
 )
 
 var log = common.NewLogger("main")
@@ -37,16 +40,34 @@ type bootstrapper struct {
 	tubeMail domain.TubeMail
 
 	users                domain.Users
+	tags                 domain.Tags
+	documentClasses      domain.DocumentClasses
+	customFields         domain.CustomFields
+	correspondents       domain.Correspondents
 	documents            domain.Documents
 	documentArchive      domain.DocumentArchive
 	documentPreprocessor domain.DocumentPreprocessor
 	documentAnalyzer     domain.DocumentAnalyzer
+	languageDetector     domain.LanguageDetector
+	documentGenerator    domain.DocumentGenerator
 	documentIndex        domain.DocumentIndex
 	documentRegistry     domain.DocumentRegistry
-
-	authService     application.AuthService
-	userService     application.UserService
-	documentService application.DocumentService
+	documentTransformer  domain.DocumentPageTransformer
+	documentRasterizer   domain.DocumentRasterizer
+	documentPageSplitter domain.DocumentPageSplitter
+	documentPageRenderer domain.DocumentPageRenderer
+	documentExporter     domain.DocumentExporter
+	instanceBackup       domain.InstanceBackup
+
+	authService          application.AuthService
+	userService          application.UserService
+	tagService           application.TagService
+	documentClassService application.DocumentClassService
+	customFieldService   application.CustomFieldService
+	correspondentService application.CorrespondentService
+	documentService      application.DocumentService
+
+	documentConsumer application.DocumentConsumer
 
 	tokenKeyResolver application.TokenKeyResolver
 }
@@ -78,8 +99,18 @@ func main() {
 	defer db.Close()
 
 	setupDependencies(bs)
+
+	if len(os.Args) > 1 {
+		runCommand(bs, os.Args[1], os.Args[2:])
+		return
+	}
+
 	initializeServer(bs)
 	ensureUserExists(bs)
+	startTrashPurge(bs)
+	startPerceptualHashBackfill(bs)
+	startOriginalFingerprintBackfill(bs)
+	startDocumentConsumer(bs)
 
 	log.Infof("Bootstrap completed in %v", time.Since(start))
 
@@ -130,18 +161,37 @@ func setupDependencies(bs *bootstrapper) {
 	bs.tokenKeyResolver = application.ConfigTokenKeyResolver(bs.config)
 	bs.tubeMail = infrastructure.NewLocalAsyncTubeMailImpl()
 	bs.users = infrastructure.NewUsers(bs.database)
+	bs.tags = infrastructure.NewTags(bs.database)
+	bs.documentClasses = infrastructure.NewDocumentClasses(bs.database)
+	bs.customFields = infrastructure.NewCustomFields(bs.database)
+	bs.correspondents = infrastructure.NewCorrespondents(bs.database)
 	bs.documents = infrastructure.NewDocuments(bs.database)
 	initializeDocumentArchive(bs)
 	bs.documentPreprocessor = infrastructure.NewDocumentPreprocessorImpl(bs.documents, bs.documentArchive)
 	bs.documentAnalyzer = infrastructure.NewTesseractOcrEngine(bs.documents, bs.documentArchive)
+	initializeLanguageDetector(bs)
+	bs.documentGenerator = infrastructure.NewTesseractDocumentGenerator(bs.documentArchive)
+	bs.documentTransformer = infrastructure.NewDocumentPageTransformerImpl(bs.documents, bs.documentArchive)
+	bs.documentRasterizer = infrastructure.NewPdftoppmDocumentRasterizer()
+	bs.documentPageSplitter = infrastructure.NewTIFFDocumentPageSplitter()
+	bs.documentPageRenderer = infrastructure.NewDocumentPageRendererImpl(bs.documentArchive)
+	bs.documentExporter = infrastructure.NewZIPDocumentExporter(bs.documentArchive)
 	initializeDocumentIndex(bs)
+	bs.instanceBackup = infrastructure.NewZIPInstanceBackup(
+		bs.database,
+		bs.documentArchive,
+		bs.documentIndex,
+	)
 
 	bs.documentRegistry = domain.NewDocumentRegistry(
 		bs.tubeMail,
 		bs.documents,
 		bs.documentPreprocessor,
 		bs.documentAnalyzer,
+		bs.languageDetector,
 		bs.documentIndex,
+		bs.documentGenerator,
+		bs.documentArchive,
 	)
 
 	bs.userService = application.NewUserService(bs.users)
@@ -151,13 +201,117 @@ func setupDependencies(bs *bootstrapper) {
 		bs.tokenKeyResolver,
 	)
 
+	bs.tagService = application.NewTagService(
+		bs.users,
+		bs.tags,
+		bs.documents,
+		bs.documentIndex,
+	)
+
+	bs.documentClassService = application.NewDocumentClassService(
+		bs.users,
+		bs.documentClasses,
+		bs.documents,
+		bs.documentIndex,
+	)
+
+	bs.customFieldService = application.NewCustomFieldService(
+		bs.users,
+		bs.customFields,
+		bs.documents,
+		bs.documentIndex,
+	)
+
+	bs.correspondentService = application.NewCorrespondentService(
+		bs.users,
+		bs.correspondents,
+		bs.documents,
+		bs.documentIndex,
+	)
+
 	bs.documentService = application.NewDocumentService(
 		bs.users,
+		bs.tags,
+		bs.documentClasses,
+		bs.correspondents,
+		bs.customFields,
 		bs.documents,
 		bs.documentArchive,
 		bs.documentIndex,
 		bs.documentRegistry,
+		bs.documentPreprocessor,
+		bs.documentTransformer,
+		bs.documentRasterizer,
+		bs.documentPageSplitter,
+		bs.documentPageRenderer,
+		bs.documentExporter,
 	)
+
+	bs.documentConsumer = application.NewDocumentConsumer(bs.config, bs.documentService)
+}
+
+// runCommand runs the given command instead of starting the server. The
+// server should not be running while backing up or restoring an instance.
+func runCommand(bs *bootstrapper, command string, args []string) {
+	if len(args) != 1 {
+		log.Fatalf(
+			"Usage: %s %s|%s <file>\n"+
+				"Backups contain all users and documents but not the search index, which is rebuilt on restore. "+
+				"Restoring requires a fresh instance without any users or documents.",
+			path.Base(os.Args[0]),
+			commandBackup,
+			commandRestore,
+		)
+	}
+
+	switch command {
+	case commandBackup:
+		backupInstance(bs, args[0])
+	case commandRestore:
+		restoreInstance(bs, args[0])
+	default:
+		log.Fatalf("Unknown command '%s'", command)
+	}
+}
+
+func backupInstance(bs *bootstrapper, fileName string) {
+	start := time.Now()
+	file, err := os.Create(fileName)
+	if err != nil {
+		log.Fatalf("Failed to create backup file: %v", err)
+	}
+
+	if err := bs.instanceBackup.Backup(file); err != nil {
+		file.Close()
+		os.Remove(fileName)
+		log.Fatalf("Backup failed: %v", err)
+	}
+
+	if err := file.Close(); err != nil {
+		log.Fatalf("Failed to write backup file: %v", err)
+	}
+
+	log.Infof("Backup written to '%s' in %v; the search index is not included and is rebuilt on restore", fileName, time.Since(start))
+}
+
+func restoreInstance(bs *bootstrapper, fileName string) {
+	start := time.Now()
+	file, err := os.Open(fileName)
+	if err != nil {
+		log.Fatalf("Failed to open backup file: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		log.Fatalf("Failed to open backup file: %v", err)
+	}
+
+	if err := bs.instanceBackup.Restore(file, info.Size()); err != nil {
+		log.Fatalf("Restore failed: %v", err)
+	}
+
+	log.Infof("Backup '%s' restored in %v", fileName, time.Since(start))
 }
 
 func initializeServer(bs *bootstrapper) {
@@ -176,8 +330,20 @@ func registerRouters(bs *bootstrapper) {
 		// User routes
 		web.NewUserRouter(bs.userService),
 
+		// Tag routes
+		web.NewTagRouter(bs.tagService),
+
+		// Document class routes
+		web.NewDocumentClassRouter(bs.documentClassService),
+
+		// Custom field routes
+		web.NewCustomFieldRouter(bs.customFieldService),
+
+		// Correspondent routes
+		web.NewCorrespondentRouter(bs.correspondentService),
+
 		// Document routes
-		web.NewDocumentRouter(bs.documentService),
+		web.NewDocumentRouter(bs.documentService, bs.customFieldService),
 	)
 }
 
@@ -193,6 +359,16 @@ func initializeDocumentArchive(bs *bootstrapper) {
 	bs.documentArchive = documentArchive
 }
 
+func initializeLanguageDetector(bs *bootstrapper) {
+	languageDetector, err := infrastructure.NewStopWordLanguageDetector()
+
+	if err != nil {
+		log.Fatalf("Failed to initialize language detector: %v", err)
+	}
+
+	bs.languageDetector = languageDetector
+}
+
 func initializeDocumentIndex(bs *bootstrapper) {
 	documentIndex, err := infrastructure.NewBleveDocumentIndex(bs.config.Index.DocumentsPath, bs.documents)
 
@@ -203,6 +379,78 @@ func initializeDocumentIndex(bs *bootstrapper) {
 	bs.documentIndex = documentIndex
 }
 
+// startTrashPurge periodically purges all documents which have been in the
+// trash for longer than the configured retention period.
+func startTrashPurge(bs *bootstrapper) {
+	retentionPeriod := bs.config.Storage.TrashRetentionPeriod
+	purgeInterval := bs.config.Storage.TrashPurgeInterval
+
+	if purgeInterval <= 0 {
+		log.Warn("Trash purge interval not set; trashed documents will never be purged")
+		return
+	}
+
+	purge := func() {
+		count, err := bs.documentService.PurgeTrashedDocuments(time.Now().Add(-retentionPeriod))
+		if err != nil {
+			log.Errorf("Failed to purge trashed documents: %v", err)
+		}
+
+		if count > 0 {
+			log.Infof("Purged %d trashed documents", count)
+		}
+	}
+
+	go func() {
+		purge()
+		for range time.Tick(purgeInterval) {
+			purge()
+		}
+	}()
+}
+
+// startPerceptualHashBackfill computes the perceptual hashes missing for pages
+// preprocessed before perceptual hashes have been introduced.
+func startPerceptualHashBackfill(bs *bootstrapper) {
+	go func() {
+		count, err := bs.documentService.ComputeMissingPerceptualHashes()
+		if err != nil {
+			log.Errorf("Failed to compute missing perceptual hashes: %v", err)
+		}
+
+		if count > 0 {
+			log.Infof("Computed perceptual hashes of %d pages", count)
+		}
+	}()
+}
+
+// startOriginalFingerprintBackfill computes the content fingerprints missing
+// for originals uploaded before they have been used to recognize duplicates.
+func startOriginalFingerprintBackfill(bs *bootstrapper) {
+	go func() {
+		count, err := bs.documentService.ComputeMissingOriginalFingerprints()
+		if err != nil {
+			log.Errorf("Failed to compute missing original fingerprints: %v", err)
+		}
+
+		if count > 0 {
+			log.Infof("Computed fingerprints of %d originals", count)
+		}
+	}()
+}
+
+// startDocumentConsumer starts consuming files from the configured consume
+// path, if any.
+func startDocumentConsumer(bs *bootstrapper) {
+	if bs.config.Storage.ConsumePath == "" {
+		return
+	}
+
+	if err := bs.documentConsumer.Start(); err != nil {
+		log.Errorf("Failed to start document consumer: %v", err)
+	}
+}
+
 func ensureUserExists(bs *bootstrapper) {
 	_, count, err := bs.userService.GetUsers(domain.PageRequest{Offset: 0, Size: 1})
 
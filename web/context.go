@@ -39,6 +39,11 @@ type (
 		TotalCount int64       `json:"totalCount"`
 		Data       interface{} `json:"data"`
 	}
+
+	facetedPageResponse struct {
+		pageResponse
+		Facets interface{} `json:"facets"`
+	}
 )
 
 // IsAuthenticated returns a boolean value indicating whether
@@ -101,6 +106,27 @@ func (c *context) Page(
 	return c.JSON(status, response)
 }
 
+// FacetedPage sends a page response including the given facets.
+func (c *context) FacetedPage(
+	status int,
+	page pageRequest,
+	totalCount int64,
+	data []interface{},
+	facets []interface{},
+) error {
+	response := facetedPageResponse{
+		pageResponse: pageResponse{
+			Size:       len(data),
+			Offset:     page.Offset,
+			TotalCount: totalCount,
+			Data:       data,
+		},
+		Facets: facets,
+	}
+
+	return c.JSON(status, response)
+}
+
 // BindAndValidate binds and validates the given object from the current context.
 func (c *context) BindAndValidate(i interface{}) error {
 	if err := c.Bind(i); err != nil {
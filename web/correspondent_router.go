@@ -0,0 +1,138 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/concepts-system/go-paperless/application"
+)
+
+type correspondentRouter struct {
+	correspondentService application.CorrespondentService
+}
+
+// NewCorrespondentRouter creates a new router for correspondent management
+// using the given correspondent service.
+func NewCorrespondentRouter(correspondentService application.CorrespondentService) Router {
+	return &correspondentRouter{
+		correspondentService: correspondentService,
+	}
+}
+
+// DefineRoutes defines the routes for correspondent management.
+func (r *correspondentRouter) DefineRoutes(group *echo.Group, auth *AuthMiddleware) {
+	apiGroup := group.Group("/api", auth.RequireScope(application.TokenScopeAPI))
+
+	correspondentGroup := apiGroup.Group("/correspondents", auth.RequireAuthentication())
+	correspondentGroup.GET("", r.getCorrespondents)
+	correspondentGroup.POST("", r.createCorrespondent)
+	correspondentGroup.GET("/:id", r.getCorrespondent)
+	correspondentGroup.PUT("/:id", r.updateCorrespondent)
+	correspondentGroup.DELETE("/:id", r.deleteCorrespondent)
+}
+
+/* Handlers */
+
+func (r *correspondentRouter) getCorrespondents(ec echo.Context) error {
+	c, _ := ec.(*context)
+	pr := c.BindPaging()
+
+	correspondents, totalCount, err := r.correspondentService.GetUserCorrespondents(
+		*c.Username,
+		pr.ToDomainPageRequest(),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	serializer := correspondentListSerializer{c, correspondents}
+	return c.Page(http.StatusOK, pr, totalCount, serializer.Response())
+}
+
+func (r *correspondentRouter) createCorrespondent(ec echo.Context) error {
+	c, _ := ec.(*context)
+	validator := newCorrespondentValidator()
+
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	correspondent, err := r.correspondentService.CreateNewCorrespondent(*c.Username, &validator.correspondent)
+	if err != nil {
+		return err
+	}
+
+	serializer := correspondentSerializer{c, correspondent}
+	return c.JSON(http.StatusCreated, serializer.Response())
+}
+
+func (r *correspondentRouter) getCorrespondent(ec echo.Context) error {
+	c, _ := ec.(*context)
+	id, err := r.bindCorrespondentID(c)
+	if err != nil {
+		return err
+	}
+
+	correspondent, err := r.correspondentService.GetUserCorrespondentByID(*c.Username, id)
+	if err != nil {
+		return err
+	}
+
+	serializer := correspondentSerializer{c, correspondent}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *correspondentRouter) updateCorrespondent(ec echo.Context) error {
+	c, _ := ec.(*context)
+	id, err := r.bindCorrespondentID(c)
+	if err != nil {
+		return err
+	}
+
+	correspondent, err := r.correspondentService.GetUserCorrespondentByID(*c.Username, id)
+	if err != nil {
+		return err
+	}
+
+	validator := newCorrespondentValidatorOf(correspondent)
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	correspondent, err = r.correspondentService.UpdateUserCorrespondent(*c.Username, &validator.correspondent)
+	if err != nil {
+		return err
+	}
+
+	serializer := correspondentSerializer{c, correspondent}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *correspondentRouter) deleteCorrespondent(ec echo.Context) error {
+	c, _ := ec.(*context)
+	id, err := r.bindCorrespondentID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := r.correspondentService.DeleteUserCorrespondent(*c.Username, id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+/* Helper Methods */
+
+func (r *correspondentRouter) bindCorrespondentID(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err != nil || id <= 0 {
+		return 0, application.BadRequestError.New("Correspondent ID has to be a positive integer")
+	}
+
+	return uint(id), nil
+}
@@ -0,0 +1,68 @@
+package web
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/concepts-system/go-paperless/domain"
+)
+
+type correspondentResponse struct {
+	ID                     uint       `json:"id"`
+	Name                   string     `json:"name"`
+	DocumentCount          int64      `json:"documentCount"`
+	LastCorrespondenceDate *time.Time `json:"lastCorrespondenceDate,omitempty"`
+}
+
+type correspondentReferenceResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type (
+	correspondentSerializer struct {
+		C echo.Context
+		*domain.Correspondent
+	}
+
+	correspondentListSerializer struct {
+		C              echo.Context
+		Correspondents []domain.Correspondent
+	}
+)
+
+// Response returns the API response for a correspondent.
+func (s correspondentSerializer) Response() correspondentResponse {
+	return correspondentResponse{
+		ID:                     uint(s.ID),
+		Name:                   string(s.Name),
+		DocumentCount:          int64(s.DocumentCount),
+		LastCorrespondenceDate: s.LastCorrespondenceDate,
+	}
+}
+
+// ReferenceResponse returns the API response for a correspondent referenced
+// by another resource.
+func (s correspondentSerializer) ReferenceResponse() *correspondentReferenceResponse {
+	if s.Correspondent == nil {
+		return nil
+	}
+
+	return &correspondentReferenceResponse{
+		ID:   uint(s.ID),
+		Name: string(s.Name),
+	}
+}
+
+// Response returns the API response for a list of correspondents.
+func (s correspondentListSerializer) Response() []interface{} {
+	response := make([]interface{}, len(s.Correspondents))
+
+	for i, correspondent := range s.Correspondents {
+		serializer := correspondentSerializer{s.C, &correspondent}
+		response[i] = serializer.Response()
+	}
+
+	return response
+}
@@ -0,0 +1,34 @@
+package web
+
+import (
+	"github.com/concepts-system/go-paperless/domain"
+)
+
+type correspondentValidator struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+
+	correspondent domain.Correspondent
+}
+
+// Bind binds the given request to a correspondent model.
+func (v *correspondentValidator) Bind(c *context) error {
+	if err := c.BindAndValidate(v); err != nil {
+		return err
+	}
+
+	v.correspondent.Name = domain.Name(v.Name)
+
+	return nil
+}
+
+func newCorrespondentValidator() *correspondentValidator {
+	return &correspondentValidator{}
+}
+
+func newCorrespondentValidatorOf(correspondent *domain.Correspondent) *correspondentValidator {
+	validator := newCorrespondentValidator()
+	validator.correspondent = *correspondent
+	validator.Name = string(correspondent.Name)
+
+	return validator
+}
@@ -0,0 +1,134 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/concepts-system/go-paperless/application"
+)
+
+type customFieldRouter struct {
+	customFieldService application.CustomFieldService
+}
+
+// NewCustomFieldRouter creates a new router for custom field management using the given custom field
+// service.
+func NewCustomFieldRouter(customFieldService application.CustomFieldService) Router {
+	return &customFieldRouter{
+		customFieldService: customFieldService,
+	}
+}
+
+// DefineRoutes defines the routes for custom field management.
+func (r *customFieldRouter) DefineRoutes(group *echo.Group, auth *AuthMiddleware) {
+	apiGroup := group.Group("/api", auth.RequireScope(application.TokenScopeAPI))
+
+	customFieldGroup := apiGroup.Group("/fields", auth.RequireAuthentication())
+	customFieldGroup.GET("", r.getCustomFields)
+	customFieldGroup.POST("", r.createCustomField)
+	customFieldGroup.GET("/:id", r.getCustomField)
+	customFieldGroup.PUT("/:id", r.updateCustomField)
+	customFieldGroup.DELETE("/:id", r.deleteCustomField)
+}
+
+/* Handlers */
+
+func (r *customFieldRouter) getCustomFields(ec echo.Context) error {
+	c, _ := ec.(*context)
+	pr := c.BindPaging()
+
+	customFields, totalCount, err := r.customFieldService.GetUserCustomFields(*c.Username, pr.ToDomainPageRequest())
+	if err != nil {
+		return err
+	}
+
+	serializer := customFieldListSerializer{c, customFields}
+	return c.Page(http.StatusOK, pr, totalCount, serializer.Response())
+}
+
+func (r *customFieldRouter) createCustomField(ec echo.Context) error {
+	c, _ := ec.(*context)
+	validator := newCustomFieldValidator()
+
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	customField, err := r.customFieldService.CreateNewCustomField(*c.Username, &validator.customField)
+	if err != nil {
+		return err
+	}
+
+	serializer := customFieldSerializer{c, customField}
+	return c.JSON(http.StatusCreated, serializer.Response())
+}
+
+func (r *customFieldRouter) getCustomField(ec echo.Context) error {
+	c, _ := ec.(*context)
+	id, err := r.bindCustomFieldID(c)
+	if err != nil {
+		return err
+	}
+
+	customField, err := r.customFieldService.GetUserCustomFieldByID(*c.Username, id)
+	if err != nil {
+		return err
+	}
+
+	serializer := customFieldSerializer{c, customField}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *customFieldRouter) updateCustomField(ec echo.Context) error {
+	c, _ := ec.(*context)
+	id, err := r.bindCustomFieldID(c)
+	if err != nil {
+		return err
+	}
+
+	customField, err := r.customFieldService.GetUserCustomFieldByID(*c.Username, id)
+	if err != nil {
+		return err
+	}
+
+	validator := newCustomFieldValidatorOf(customField)
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	customField, err = r.customFieldService.UpdateUserCustomField(*c.Username, &validator.customField)
+	if err != nil {
+		return err
+	}
+
+	serializer := customFieldSerializer{c, customField}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *customFieldRouter) deleteCustomField(ec echo.Context) error {
+	c, _ := ec.(*context)
+	id, err := r.bindCustomFieldID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := r.customFieldService.DeleteUserCustomField(*c.Username, id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+/* Helper Methods */
+
+func (r *customFieldRouter) bindCustomFieldID(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err != nil || id <= 0 {
+		return 0, application.BadRequestError.New("Custom field ID has to be a positive integer")
+	}
+
+	return uint(id), nil
+}
@@ -0,0 +1,98 @@
+package web
+
+import (
+	"encoding/json"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/concepts-system/go-paperless/domain"
+)
+
+type customFieldResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type customFieldValueResponse struct {
+	FieldID uint        `json:"fieldId"`
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Value   interface{} `json:"value"`
+}
+
+type (
+	customFieldSerializer struct {
+		C echo.Context
+		*domain.CustomField
+	}
+
+	customFieldListSerializer struct {
+		C            echo.Context
+		CustomFields []domain.CustomField
+	}
+
+	customFieldValueListSerializer struct {
+		C      echo.Context
+		Values []domain.CustomFieldValue
+	}
+)
+
+// Response returns the API response for a custom field.
+func (s customFieldSerializer) Response() customFieldResponse {
+	return customFieldResponse{
+		ID:   uint(s.ID),
+		Name: string(s.Name),
+		Type: string(s.Type),
+	}
+}
+
+// Response returns the API response for a list of custom fields.
+func (s customFieldListSerializer) Response() []interface{} {
+	response := make([]interface{}, len(s.CustomFields))
+
+	for i, customField := range s.CustomFields {
+		serializer := customFieldSerializer{s.C, &customField}
+		response[i] = serializer.Response()
+	}
+
+	return response
+}
+
+// responses returns the typed API responses for a list of custom field
+// values, e.g. for embedding them into other responses.
+func (s customFieldValueListSerializer) responses() []customFieldValueResponse {
+	responses := make([]customFieldValueResponse, len(s.Values))
+
+	for i, value := range s.Values {
+		responses[i] = customFieldValueResponse{
+			FieldID: uint(value.Field.ID),
+			Name:    string(value.Field.Name),
+			Type:    string(value.Field.Type),
+			Value:   s.typedValue(value),
+		}
+	}
+
+	return responses
+}
+
+/* Helper Methods */
+
+// typedValue returns the given value in its JSON representation. Integers and
+// monetary amounts are given as numbers keeping their decimal places, whereas
+// dates and values not matching their field's type are given as text.
+func (s customFieldValueListSerializer) typedValue(value domain.CustomFieldValue) interface{} {
+	typedValue, err := value.TypedValue()
+	if err != nil {
+		return value.Value
+	}
+
+	switch value.Field.Type {
+	case domain.CustomFieldTypeInteger, domain.CustomFieldTypeMonetary:
+		return json.Number(value.Value)
+	case domain.CustomFieldTypeBoolean:
+		return typedValue
+	default:
+		return value.Value
+	}
+}
@@ -0,0 +1,174 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/concepts-system/go-paperless/application"
+	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
+)
+
+const (
+	// maxCustomFieldStringLength defines the maximum number of characters of
+	// string values of custom fields.
+	maxCustomFieldStringLength = 1024
+
+	// maxCustomFieldURLLength defines the maximum number of characters of URL
+	// values of custom fields.
+	maxCustomFieldURLLength = 2048
+)
+
+// monetaryAmountPattern matches monetary amounts given as JSON numbers with at
+// most two decimal places.
+var monetaryAmountPattern = regexp.MustCompile(`^(-?)(\d+)(?:\.(\d{1,2}))?$`)
+
+// customFieldNamePattern matches names of custom fields. As the names are
+// used as field paths within the document index, they are restricted to
+// identifiers, e.g. excluding dots, spaces and query syntax.
+var customFieldNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+type customFieldValidator struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+	Type string `json:"type" validate:"required,oneof=STRING INTEGER MONETARY DATE BOOLEAN URL"`
+
+	customField domain.CustomField
+}
+
+// Bind binds the given request to a custom field model.
+func (v *customFieldValidator) Bind(c *context) error {
+	if err := c.BindAndValidate(v); err != nil {
+		return err
+	}
+
+	if !customFieldNamePattern.MatchString(v.Name) {
+		err := application.BadRequestError.New("Validation failed")
+		return errors.AddContext(err, "name", "identifier")
+	}
+
+	v.customField.Name = domain.Name(v.Name)
+	v.customField.Type = domain.CustomFieldType(v.Type)
+
+	return nil
+}
+
+func newCustomFieldValidator() *customFieldValidator {
+	return &customFieldValidator{}
+}
+
+func newCustomFieldValidatorOf(customField *domain.CustomField) *customFieldValidator {
+	validator := newCustomFieldValidator()
+	validator.customField = *customField
+	validator.Name = string(customField.Name)
+	validator.Type = string(customField.Type)
+
+	return validator
+}
+
+/* Helper Functions */
+
+// parseCustomFieldValue validates the given JSON value against the given
+// custom field type and returns its canonical text representation.
+func parseCustomFieldValue(fieldType domain.CustomFieldType, value json.RawMessage) (string, bool) {
+	value = bytes.TrimSpace(value)
+	if bytes.Equal(value, []byte("null")) {
+		return "", false
+	}
+
+	switch fieldType {
+	case domain.CustomFieldTypeString:
+		return parseCustomFieldString(value)
+	case domain.CustomFieldTypeInteger:
+		return parseCustomFieldInteger(value)
+	case domain.CustomFieldTypeMonetary:
+		return parseCustomFieldMonetary(value)
+	case domain.CustomFieldTypeDate:
+		return parseCustomFieldDate(value)
+	case domain.CustomFieldTypeBoolean:
+		return parseCustomFieldBoolean(value)
+	case domain.CustomFieldTypeURL:
+		return parseCustomFieldURL(value)
+	default:
+		return "", false
+	}
+}
+
+func parseCustomFieldString(value json.RawMessage) (string, bool) {
+	var text string
+	if err := json.Unmarshal(value, &text); err != nil {
+		return "", false
+	}
+
+	return text, utf8.RuneCountInString(text) <= maxCustomFieldStringLength
+}
+
+func parseCustomFieldInteger(value json.RawMessage) (string, bool) {
+	integer, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return "", false
+	}
+
+	return strconv.FormatInt(integer, 10), true
+}
+
+func parseCustomFieldMonetary(value json.RawMessage) (string, bool) {
+	match := monetaryAmountPattern.FindStringSubmatch(string(value))
+	if match == nil {
+		return "", false
+	}
+
+	sign, units, cents := match[1], match[2], match[3]
+	integer, err := strconv.ParseInt(units, 10, 64)
+	if err != nil {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s%d.%s", sign, integer, (cents + "00")[:2]), true
+}
+
+func parseCustomFieldDate(value json.RawMessage) (string, bool) {
+	var text string
+	if err := json.Unmarshal(value, &text); err != nil {
+		return "", false
+	}
+
+	date, err := time.Parse(domain.CustomFieldDateFormat, text)
+	if err != nil {
+		return "", false
+	}
+
+	return date.Format(domain.CustomFieldDateFormat), true
+}
+
+func parseCustomFieldBoolean(value json.RawMessage) (string, bool) {
+	var boolean bool
+	if err := json.Unmarshal(value, &boolean); err != nil {
+		return "", false
+	}
+
+	return strconv.FormatBool(boolean), true
+}
+
+func parseCustomFieldURL(value json.RawMessage) (string, bool) {
+	var text string
+	if err := json.Unmarshal(value, &text); err != nil {
+		return "", false
+	}
+
+	if len(text) > maxCustomFieldURLLength {
+		return "", false
+	}
+
+	parsedURL, err := url.ParseRequestURI(text)
+	if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return "", false
+	}
+
+	return text, true
+}
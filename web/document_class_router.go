@@ -0,0 +1,138 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/concepts-system/go-paperless/application"
+)
+
+type documentClassRouter struct {
+	documentClassService application.DocumentClassService
+}
+
+// NewDocumentClassRouter creates a new router for document class management
+// using the given document class service.
+func NewDocumentClassRouter(documentClassService application.DocumentClassService) Router {
+	return &documentClassRouter{
+		documentClassService: documentClassService,
+	}
+}
+
+// DefineRoutes defines the routes for document class management.
+func (r *documentClassRouter) DefineRoutes(group *echo.Group, auth *AuthMiddleware) {
+	apiGroup := group.Group("/api", auth.RequireScope(application.TokenScopeAPI))
+
+	documentClassGroup := apiGroup.Group("/classes", auth.RequireAuthentication())
+	documentClassGroup.GET("", r.getDocumentClasses)
+	documentClassGroup.POST("", r.createDocumentClass)
+	documentClassGroup.GET("/:id", r.getDocumentClass)
+	documentClassGroup.PUT("/:id", r.updateDocumentClass)
+	documentClassGroup.DELETE("/:id", r.deleteDocumentClass)
+}
+
+/* Handlers */
+
+func (r *documentClassRouter) getDocumentClasses(ec echo.Context) error {
+	c, _ := ec.(*context)
+	pr := c.BindPaging()
+
+	documentClasses, totalCount, err := r.documentClassService.GetUserDocumentClasses(
+		*c.Username,
+		pr.ToDomainPageRequest(),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	serializer := documentClassListSerializer{c, documentClasses}
+	return c.Page(http.StatusOK, pr, totalCount, serializer.Response())
+}
+
+func (r *documentClassRouter) createDocumentClass(ec echo.Context) error {
+	c, _ := ec.(*context)
+	validator := newDocumentClassValidator()
+
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	documentClass, err := r.documentClassService.CreateNewDocumentClass(*c.Username, &validator.documentClass)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentClassSerializer{c, documentClass}
+	return c.JSON(http.StatusCreated, serializer.Response())
+}
+
+func (r *documentClassRouter) getDocumentClass(ec echo.Context) error {
+	c, _ := ec.(*context)
+	id, err := r.bindDocumentClassID(c)
+	if err != nil {
+		return err
+	}
+
+	documentClass, err := r.documentClassService.GetUserDocumentClassByID(*c.Username, id)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentClassSerializer{c, documentClass}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *documentClassRouter) updateDocumentClass(ec echo.Context) error {
+	c, _ := ec.(*context)
+	id, err := r.bindDocumentClassID(c)
+	if err != nil {
+		return err
+	}
+
+	documentClass, err := r.documentClassService.GetUserDocumentClassByID(*c.Username, id)
+	if err != nil {
+		return err
+	}
+
+	validator := newDocumentClassValidatorOf(documentClass)
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	documentClass, err = r.documentClassService.UpdateUserDocumentClass(*c.Username, &validator.documentClass)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentClassSerializer{c, documentClass}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *documentClassRouter) deleteDocumentClass(ec echo.Context) error {
+	c, _ := ec.(*context)
+	id, err := r.bindDocumentClassID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := r.documentClassService.DeleteUserDocumentClass(*c.Username, id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+/* Helper Methods */
+
+func (r *documentClassRouter) bindDocumentClassID(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err != nil || id <= 0 {
+		return 0, application.BadRequestError.New("Document class ID has to be a positive integer")
+	}
+
+	return uint(id), nil
+}
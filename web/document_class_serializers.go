@@ -0,0 +1,55 @@
+package web
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/concepts-system/go-paperless/domain"
+)
+
+type documentClassResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type (
+	documentClassSerializer struct {
+		C echo.Context
+		*domain.DocumentClass
+	}
+
+	documentClassListSerializer struct {
+		C               echo.Context
+		DocumentClasses []domain.DocumentClass
+	}
+)
+
+// Response returns the API response for a document class.
+func (s documentClassSerializer) Response() documentClassResponse {
+	return documentClassResponse{
+		ID:   uint(s.ID),
+		Name: string(s.Name),
+	}
+}
+
+// OptionalResponse returns the API response for a document class referenced
+// by another resource, which is nil in case no document class is given.
+func (s documentClassSerializer) OptionalResponse() *documentClassResponse {
+	if s.DocumentClass == nil {
+		return nil
+	}
+
+	response := s.Response()
+	return &response
+}
+
+// Response returns the API response for a list of document classes.
+func (s documentClassListSerializer) Response() []interface{} {
+	response := make([]interface{}, len(s.DocumentClasses))
+
+	for i, documentClass := range s.DocumentClasses {
+		serializer := documentClassSerializer{s.C, &documentClass}
+		response[i] = serializer.Response()
+	}
+
+	return response
+}
@@ -0,0 +1,34 @@
+package web
+
+import (
+	"github.com/concepts-system/go-paperless/domain"
+)
+
+type documentClassValidator struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+
+	documentClass domain.DocumentClass
+}
+
+// Bind binds the given request to a document class model.
+func (v *documentClassValidator) Bind(c *context) error {
+	if err := c.BindAndValidate(v); err != nil {
+		return err
+	}
+
+	v.documentClass.Name = domain.Name(v.Name)
+
+	return nil
+}
+
+func newDocumentClassValidator() *documentClassValidator {
+	return &documentClassValidator{}
+}
+
+func newDocumentClassValidatorOf(documentClass *domain.DocumentClass) *documentClassValidator {
+	validator := newDocumentClassValidator()
+	validator.documentClass = *documentClass
+	validator.Name = string(documentClass.Name)
+
+	return validator
+}
@@ -7,11 +7,48 @@ import (
 )
 
 type documentPageResponse struct {
-	PageNumber  uint   `json:"pageNumber,omitempty"`
-	State       string `json:"state"`
-	Fingerprint string `json:"fingerprint"`
-	Type        string `json:"type"`
-	Text        string `json:"text,omitempty"`
+	PageNumber  uint                          `json:"pageNumber,omitempty"`
+	State       string                        `json:"state"`
+	Fingerprint string                        `json:"fingerprint"`
+	Type        string                        `json:"type"`
+	Text        string                        `json:"text,omitempty"`
+	Original    *documentPageOriginalResponse `json:"original,omitempty"`
+}
+
+type documentPageOriginalResponse struct {
+	ContentType string `json:"contentType"`
+	FileName    string `json:"fileName"`
+}
+
+type documentPageDuplicatesResponse struct {
+	Fingerprint string                          `json:"fingerprint"`
+	Pages       []documentPageReferenceResponse `json:"pages"`
+}
+
+type documentSimilarityResponse struct {
+	Pages     []similarPagesResponse     `json:"pages"`
+	Documents []similarDocumentsResponse `json:"documents"`
+}
+
+type similarPagesResponse struct {
+	Pages      [2]documentPageReferenceResponse `json:"pages"`
+	Similarity float64                          `json:"similarity"`
+}
+
+type similarDocumentsResponse struct {
+	Documents  [2]documentReferenceResponse `json:"documents"`
+	Similarity float64                      `json:"similarity"`
+}
+
+type documentReferenceResponse struct {
+	DocumentNumber uint   `json:"documentNumber"`
+	Title          string `json:"title"`
+}
+
+type documentPageReferenceResponse struct {
+	DocumentNumber uint   `json:"documentNumber"`
+	PageNumber     uint   `json:"pageNumber"`
+	Title          string `json:"title"`
 }
 
 type (
@@ -25,6 +62,17 @@ type (
 		C     echo.Context
 		Pages []domain.DocumentPage
 	}
+
+	documentPageDuplicatesListSerializer struct {
+		C          echo.Context
+		Duplicates []domain.PageDuplicates
+	}
+
+	documentSimilaritySerializer struct {
+		C                echo.Context
+		SimilarPages     []domain.SimilarPages
+		SimilarDocuments []domain.SimilarDocuments
+	}
 )
 
 // Response returns the API response for a document page.
@@ -35,12 +83,22 @@ func (s documentPageSerializer) Response() documentPageResponse {
 		text = string(s.Text)
 	}
 
+	var original *documentPageOriginalResponse
+
+	if s.Original != nil {
+		original = &documentPageOriginalResponse{
+			ContentType: s.Original.ContentType,
+			FileName:    s.Original.FileName,
+		}
+	}
+
 	return documentPageResponse{
 		PageNumber:  uint(s.PageNumber),
 		Fingerprint: string(s.Fingerprint),
 		State:       string(s.State),
 		Type:        string(s.Type),
 		Text:        text,
+		Original:    original,
 	}
 }
 
@@ -54,3 +112,64 @@ func (s documentPageListSerializer) Response() []interface{} {
 
 	return response
 }
+
+// Response returns the API response for a list of duplicate document pages.
+func (s documentPageDuplicatesListSerializer) Response() []interface{} {
+	response := make([]interface{}, len(s.Duplicates))
+
+	for i, duplicates := range s.Duplicates {
+		pages := make([]documentPageReferenceResponse, len(duplicates.Pages))
+		for j, page := range duplicates.Pages {
+			pages[j] = newDocumentPageReferenceResponse(page)
+		}
+
+		response[i] = documentPageDuplicatesResponse{
+			Fingerprint: string(duplicates.Fingerprint),
+			Pages:       pages,
+		}
+	}
+
+	return response
+}
+
+// Response returns the API response for pairs of similar pages and documents.
+func (s documentSimilaritySerializer) Response() documentSimilarityResponse {
+	response := documentSimilarityResponse{
+		Pages:     make([]similarPagesResponse, len(s.SimilarPages)),
+		Documents: make([]similarDocumentsResponse, len(s.SimilarDocuments)),
+	}
+
+	for i, similar := range s.SimilarPages {
+		response.Pages[i] = similarPagesResponse{
+			Pages: [2]documentPageReferenceResponse{
+				newDocumentPageReferenceResponse(similar.Page),
+				newDocumentPageReferenceResponse(similar.Other),
+			},
+			Similarity: similar.Similarity,
+		}
+	}
+
+	for i, similar := range s.SimilarDocuments {
+		response.Documents[i] = similarDocumentsResponse{
+			Documents: [2]documentReferenceResponse{
+				{uint(similar.Document.DocumentNumber), string(similar.Document.Title)},
+				{uint(similar.Other.DocumentNumber), string(similar.Other.Title)},
+			},
+			Similarity: similar.Similarity,
+		}
+	}
+
+	return response
+}
+
+/* Helper Functions */
+
+func newDocumentPageReferenceResponse(page domain.DocumentPage) documentPageReferenceResponse {
+	response := documentPageReferenceResponse{PageNumber: uint(page.PageNumber)}
+	if page.Document != nil {
+		response.DocumentNumber = uint(page.Document.DocumentNumber)
+		response.Title = string(page.Document.Title)
+	}
+
+	return response
+}
@@ -0,0 +1,81 @@
+package web
+
+import (
+	"strings"
+
+	"github.com/concepts-system/go-paperless/domain"
+)
+
+type documentPageContentValidator struct {
+	Width  int    `query:"width" validate:"min=0,max=10000"`
+	Format string `query:"format" validate:"omitempty,oneof=png jpeg"`
+
+	rendition *domain.PageRendition
+}
+
+type documentPageOrderValidator struct {
+	PageNumbers []uint `json:"pageNumbers" validate:"required,min=1,dive,min=1"`
+}
+
+type documentPageRotationValidator struct {
+	Degrees int `json:"degrees" validate:"required,oneof=90 180 270"`
+}
+
+type documentPageMoveValidator struct {
+	PageNumbers          []uint `json:"pageNumbers" validate:"required,min=1,dive,min=1"`
+	TargetDocumentNumber uint   `json:"targetDocumentNumber" validate:"required,min=1"`
+}
+
+func newDocumentPageContentValidator() *documentPageContentValidator {
+	return &documentPageContentValidator{}
+}
+
+func newDocumentPageOrderValidator() *documentPageOrderValidator {
+	return &documentPageOrderValidator{}
+}
+
+func newDocumentPageRotationValidator() *documentPageRotationValidator {
+	return &documentPageRotationValidator{}
+}
+
+func newDocumentPageMoveValidator() *documentPageMoveValidator {
+	return &documentPageMoveValidator{}
+}
+
+// Bind binds the given request to a page rendition. The rendition is left
+// empty in case the original content is requested.
+func (v *documentPageContentValidator) Bind(c *context) error {
+	if err := c.BindAndValidate(v); err != nil {
+		return err
+	}
+
+	if v.Width == 0 && v.Format == "" {
+		return nil
+	}
+
+	v.rendition = &domain.PageRendition{
+		Width:  v.Width,
+		Format: domain.RenditionFormatPNG,
+	}
+
+	if v.Format != "" {
+		v.rendition.Format = domain.RenditionFormat(strings.ToUpper(v.Format))
+	}
+
+	return nil
+}
+
+// Bind binds the given request to a new page order.
+func (v *documentPageOrderValidator) Bind(c *context) error {
+	return c.BindAndValidate(v)
+}
+
+// Bind binds the given request to a page rotation.
+func (v *documentPageRotationValidator) Bind(c *context) error {
+	return c.BindAndValidate(v)
+}
+
+// Bind binds the given request to a page move.
+func (v *documentPageMoveValidator) Bind(c *context) error {
+	return c.BindAndValidate(v)
+}
@@ -2,9 +2,11 @@ package web
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/concepts-system/go-paperless/application"
 	"github.com/concepts-system/go-paperless/domain"
@@ -13,17 +15,25 @@ import (
 
 const (
 	pagesFormKey = "pages[]"
+	pageFormKey  = "page"
+
+	exportMimeType = "application/zip"
 )
 
 type documentRouter struct {
-	documentService application.DocumentService
+	documentService    application.DocumentService
+	customFieldService application.CustomFieldService
 }
 
 // NewDocumentRouter creates a new router for document management using the given
-// document service.
-func NewDocumentRouter(documentService application.DocumentService) Router {
+// document and custom field services.
+func NewDocumentRouter(
+	documentService application.DocumentService,
+	customFieldService application.CustomFieldService,
+) Router {
 	return &documentRouter{
-		documentService: documentService,
+		documentService:    documentService,
+		customFieldService: customFieldService,
 	}
 }
 
@@ -34,19 +44,37 @@ func (r *documentRouter) DefineRoutes(group *echo.Group, auth *AuthMiddleware) {
 	documentGroup := apiGroup.Group("/documents", auth.RequireAuthentication())
 	documentGroup.GET("", r.getDocuments)
 	documentGroup.GET("/search", r.searchDocuments)
+	documentGroup.GET("/search/suggest", r.suggestSearchTerms)
+	documentGroup.GET("/export", r.exportDocuments)
+	documentGroup.GET("/duplicates", r.getDuplicatePages)
+	documentGroup.GET("/duplicates/similar", r.getSimilarPages)
 	documentGroup.POST("", r.createDocument)
 	documentGroup.GET("/:id", r.getDocument)
-	// documentGroup.PUT("/:id", updateDocument)
-	// documentGroup.DELETE("/:id", deleteDocument)
-	// documentGroup.GET("/:id/content", getDocumentContent)
+	documentGroup.PUT("/:id", r.updateDocument)
+	documentGroup.DELETE("/:id", r.deleteDocument)
+	documentGroup.GET("/:id/content", r.getDocumentContent)
+	documentGroup.GET("/:id/export", r.exportDocument)
+	documentGroup.POST("/:id/split", r.splitDocument)
+	documentGroup.POST("/:id/merge", r.mergeDocuments)
+	documentGroup.PUT("/:id/tags", r.setDocumentTags)
+	documentGroup.PUT("/:id/fields", r.setDocumentCustomFields)
+
+	trashGroup := documentGroup.Group("/trash")
+	trashGroup.GET("", r.getTrashedDocuments)
+	trashGroup.POST("/:id/restore", r.restoreDocument)
+	trashGroup.DELETE("/:id", r.purgeDocument)
 
 	pageGroup := documentGroup.Group("/:id/pages")
 	pageGroup.GET("", r.getDocumentPages)
 	pageGroup.POST("/content", r.addPagesToDocument)
+	pageGroup.PUT("/order", r.reorderDocumentPages)
+	pageGroup.POST("/move", r.moveDocumentPages)
 	pageGroup.GET("/:pageNumber", r.getDocumentPage)
-	// pageGroup.DELETE("/:pageNumber", deleteDocumentPage)
+	pageGroup.DELETE("/:pageNumber", r.deleteDocumentPage)
 	pageGroup.GET("/:pageNumber/content", r.getDocumentPageContent)
-	// pageGroup.PUT("/:pageNumber/content", updatePageContent)
+	pageGroup.GET("/:pageNumber/original", r.getDocumentPageOriginal)
+	pageGroup.PUT("/:pageNumber/content", r.replaceDocumentPageContent)
+	pageGroup.POST("/:pageNumber/rotation", r.rotateDocumentPage)
 }
 
 /* Handlers */
@@ -55,8 +83,14 @@ func (r *documentRouter) getDocuments(ec echo.Context) error {
 	c, _ := ec.(*context)
 	pr := c.BindPaging()
 
+	filter := newDocumentFilterValidator()
+	if err := filter.Bind(c); err != nil {
+		return err
+	}
+
 	documents, totalCount, err := r.documentService.GetUserDocuments(
 		*c.Username,
+		filter.filter,
 		pr.ToDomainPageRequest(),
 	)
 
@@ -72,18 +106,52 @@ func (r *documentRouter) searchDocuments(ec echo.Context) error {
 	c, _ := ec.(*context)
 	pr := c.BindPaging()
 
-	results, totalCount, err := r.documentService.SearchUserDocuments(
+	search := newDocumentSearchValidator()
+	if err := search.Bind(c); err != nil {
+		return err
+	}
+
+	filter := newDocumentFilterValidator()
+	if err := filter.Bind(c); err != nil {
+		return err
+	}
+
+	response, err := r.documentService.SearchUserDocuments(
 		*c.Username,
-		c.QueryParam("query"),
-		pr.ToDomainPageRequest(),
+		search.SearchRequest(filter.filter, pr.ToDomainPageRequest()),
 	)
 
 	if err != nil {
 		return err
 	}
 
-	serializer := documentSearchResultListSerializer{c, results}
-	return c.Page(http.StatusOK, pr, totalCount, serializer.Response())
+	serializer := documentSearchResultListSerializer{c, response.Results}
+	facetSerializer := documentFacetResultListSerializer{c, response.Facets}
+
+	return c.FacetedPage(
+		http.StatusOK,
+		pr,
+		int64(response.TotalCount),
+		serializer.Response(),
+		facetSerializer.Response(),
+	)
+}
+
+func (r *documentRouter) suggestSearchTerms(ec echo.Context) error {
+	c, _ := ec.(*context)
+	validator := newDocumentSuggestionValidator()
+
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	suggestions, err := r.documentService.SuggestUserSearchTerms(*c.Username, validator.Query, validator.Size)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentSearchSuggestionsSerializer{c, suggestions}
+	return c.JSON(http.StatusOK, serializer.Response())
 }
 
 func (r *documentRouter) createDocument(ec echo.Context) error {
@@ -119,6 +187,292 @@ func (r *documentRouter) getDocument(ec echo.Context) error {
 	return c.JSON(http.StatusOK, serializer.Response())
 }
 
+func (r *documentRouter) updateDocument(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	document, err := r.documentService.GetUserDocumentByDocumentNumber(*c.Username, documentNumber)
+	if err != nil {
+		return err
+	}
+
+	validator := newDocumentValidatorOf(document)
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	document, err = r.documentService.UpdateUserDocument(*c.Username, &validator.document)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentSerializer{c, document}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *documentRouter) deleteDocument(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	if err := r.documentService.DeleteUserDocument(*c.Username, documentNumber); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (r *documentRouter) exportDocuments(ec echo.Context) error {
+	c, _ := ec.(*context)
+	validator := newDocumentExportValidator()
+
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	content, err := r.documentService.ExportUserDocuments(*c.Username, validator.DocumentNumbers)
+	if err != nil {
+		return err
+	}
+
+	return c.BinaryAttachment(
+		exportMimeType,
+		fmt.Sprintf("Export - %s.zip", time.Now().Format("2006-01-02")),
+		-1,
+		content,
+	)
+}
+
+func (r *documentRouter) getDuplicatePages(ec echo.Context) error {
+	c, _ := ec.(*context)
+
+	duplicates, err := r.documentService.GetUserDuplicatePages(*c.Username)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentPageDuplicatesListSerializer{c, duplicates}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *documentRouter) getSimilarPages(ec echo.Context) error {
+	c, _ := ec.(*context)
+	validator := newDocumentSimilarityValidator()
+
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	similarPages, similarDocuments, err := r.documentService.GetUserSimilarPages(*c.Username, validator.Threshold)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentSimilaritySerializer{c, similarPages, similarDocuments}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *documentRouter) exportDocument(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	document, err := r.documentService.GetUserDocumentByDocumentNumber(*c.Username, documentNumber)
+	if err != nil {
+		return err
+	}
+
+	content, err := r.documentService.ExportUserDocuments(*c.Username, []uint{documentNumber})
+	if err != nil {
+		return err
+	}
+
+	title := string(document.Title)
+	if strings.TrimSpace(title) == "" {
+		title = fmt.Sprint(documentNumber)
+	}
+
+	return c.BinaryAttachment(exportMimeType, fmt.Sprintf("%s.zip", title), -1, content)
+}
+
+func (r *documentRouter) getTrashedDocuments(ec echo.Context) error {
+	c, _ := ec.(*context)
+	pr := c.BindPaging()
+
+	documents, totalCount, err := r.documentService.GetUserTrashedDocuments(
+		*c.Username,
+		pr.ToDomainPageRequest(),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	serializer := documentListSerializer{c, documents}
+	return c.Page(http.StatusOK, pr, totalCount, serializer.Response())
+}
+
+func (r *documentRouter) restoreDocument(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	document, err := r.documentService.RestoreUserDocument(*c.Username, documentNumber)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentSerializer{c, document}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *documentRouter) purgeDocument(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	if err := r.documentService.PurgeUserDocument(*c.Username, documentNumber); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (r *documentRouter) splitDocument(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	validator := newDocumentSplitValidator()
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	document, err := r.documentService.SplitUserDocument(*c.Username, documentNumber, validator.PageNumber)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentSerializer{c, document}
+	return c.JSON(http.StatusCreated, serializer.Response())
+}
+
+func (r *documentRouter) setDocumentTags(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	validator := newDocumentTagsValidator()
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	document, err := r.documentService.SetUserDocumentTags(*c.Username, documentNumber, validator.TagIDs)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentSerializer{c, document}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *documentRouter) setDocumentCustomFields(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	validator := newDocumentCustomFieldsValidator()
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	customFields, err := r.customFieldService.GetUserCustomFieldsByIDs(*c.Username, validator.FieldIDs())
+	if err != nil {
+		return err
+	}
+
+	if err := validator.BindValues(customFields); err != nil {
+		return err
+	}
+
+	document, err := r.documentService.SetUserDocumentCustomFields(*c.Username, documentNumber, validator.values)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentSerializer{c, document}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *documentRouter) mergeDocuments(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	validator := newDocumentMergeValidator()
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	document, err := r.documentService.MergeUserDocuments(*c.Username, documentNumber, validator.DocumentNumbers)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentSerializer{c, document}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *documentRouter) getDocumentContent(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	document, err := r.documentService.GetUserDocumentByDocumentNumber(*c.Username, documentNumber)
+	if err != nil {
+		return err
+	}
+
+	content, err := r.documentService.GetUserDocumentContent(*c.Username, documentNumber)
+	if err != nil {
+		return err
+	}
+
+	extension, mimeType := r.getDocumentContentFileInfos(document)
+	title := string(document.Title)
+	if strings.TrimSpace(title) == "" {
+		title = fmt.Sprint(documentNumber)
+	}
+
+	return c.BinaryAttachment(
+		mimeType,
+		fmt.Sprintf("%s.%s", title, extension),
+		-1,
+		content,
+	)
+}
+
 func (r *documentRouter) getDocumentPages(ec echo.Context) error {
 	c, _ := ec.(*context)
 	documentNumber, err := r.bindDocumentNumber(c)
@@ -169,7 +523,7 @@ func (r *documentRouter) addPagesToDocument(ec echo.Context) error {
 		return application.BadRequestError.Newf("Expecting valid multipart form with '%s' containing at least one file", pagesFormKey)
 	}
 
-	files := form.File[pagesFormKey]
+	files := application.NewMultipartUploads(form.File[pagesFormKey])
 	pages, err := r.documentService.AddPagesToUserDocument(*c.Username, documentNumber, files)
 	if err != nil {
 		return err
@@ -179,6 +533,25 @@ func (r *documentRouter) addPagesToDocument(ec echo.Context) error {
 	return c.JSON(http.StatusCreated, serializer.Response())
 }
 
+func (r *documentRouter) deleteDocumentPage(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	pageNumber, err := r.bindPageNumber(c)
+	if err != nil {
+		return err
+	}
+
+	if err := r.documentService.DeleteUserDocumentPage(*c.Username, documentNumber, pageNumber); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 func (r *documentRouter) getDocumentPageContent(ec echo.Context) error {
 	c, _ := ec.(*context)
 	documentNumber, err := r.bindDocumentNumber(c)
@@ -191,17 +564,36 @@ func (r *documentRouter) getDocumentPageContent(ec echo.Context) error {
 		return err
 	}
 
+	validator := newDocumentPageContentValidator()
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
 	page, err := r.documentService.GetUserDocumentPageByDocumentNumberAndPageNumber(*c.Username, documentNumber, pageNumber)
 	if err != nil {
 		return err
 	}
 
-	content, err := r.documentService.GetUserDocumentPageContent(*c.Username, documentNumber, pageNumber)
+	var content io.ReadCloser
+	var extension, mimeType string
+	if validator.rendition == nil {
+		content, err = r.documentService.GetUserDocumentPageContent(*c.Username, documentNumber, pageNumber)
+		extension, mimeType = r.getPageContentFileInfos(page)
+	} else {
+		content, err = r.documentService.GetUserDocumentPageRendition(
+			*c.Username,
+			documentNumber,
+			pageNumber,
+			validator.rendition.Width,
+			validator.rendition.Format,
+		)
+		extension, mimeType = r.getRenditionFileInfos(validator.rendition)
+	}
+
 	if err != nil {
 		return err
 	}
 
-	extension, mimeType := r.getPageContentFileInfos(page)
 	title := string(page.Document.Title)
 	if strings.TrimSpace(title) == "" {
 		title = fmt.Sprint(documentNumber)
@@ -215,6 +607,132 @@ func (r *documentRouter) getDocumentPageContent(ec echo.Context) error {
 	)
 }
 
+func (r *documentRouter) getDocumentPageOriginal(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	pageNumber, err := r.bindPageNumber(c)
+	if err != nil {
+		return err
+	}
+
+	original, content, err := r.documentService.GetUserDocumentPageOriginal(*c.Username, documentNumber, pageNumber)
+	if err != nil {
+		return err
+	}
+
+	fileName, mimeType := r.getOriginalFileInfos(original)
+	return c.BinaryAttachment(mimeType, fileName, -1, content)
+}
+
+func (r *documentRouter) replaceDocumentPageContent(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	pageNumber, err := r.bindPageNumber(c)
+	if err != nil {
+		return err
+	}
+
+	file, err := c.FormFile(pageFormKey)
+	if err != nil {
+		return application.BadRequestError.Newf("Expecting valid multipart form with '%s' containing exactly one file", pageFormKey)
+	}
+
+	page, err := r.documentService.ReplaceUserDocumentPageContent(
+		*c.Username,
+		documentNumber,
+		pageNumber,
+		application.NewMultipartUpload(file),
+	)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentPageSerializer{c, false, page}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *documentRouter) reorderDocumentPages(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	validator := newDocumentPageOrderValidator()
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	pages, err := r.documentService.ReorderUserDocumentPages(*c.Username, documentNumber, validator.PageNumbers)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentPageListSerializer{c, pages}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *documentRouter) moveDocumentPages(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	validator := newDocumentPageMoveValidator()
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	pages, err := r.documentService.MoveUserDocumentPages(
+		*c.Username,
+		documentNumber,
+		validator.PageNumbers,
+		validator.TargetDocumentNumber,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	serializer := documentPageListSerializer{c, pages}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *documentRouter) rotateDocumentPage(ec echo.Context) error {
+	c, _ := ec.(*context)
+	documentNumber, err := r.bindDocumentNumber(c)
+	if err != nil {
+		return err
+	}
+
+	pageNumber, err := r.bindPageNumber(c)
+	if err != nil {
+		return err
+	}
+
+	validator := newDocumentPageRotationValidator()
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	page, err := r.documentService.RotateUserDocumentPage(*c.Username, documentNumber, pageNumber, validator.Degrees)
+	if err != nil {
+		return err
+	}
+
+	serializer := documentPageSerializer{c, false, page}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
 /* Helper Methods */
 
 func (r *documentRouter) bindDocumentNumber(c echo.Context) (uint, error) {
@@ -237,10 +755,44 @@ func (r *documentRouter) bindPageNumber(c echo.Context) (uint, error) {
 	return uint(id), nil
 }
 
+func (r *documentRouter) getDocumentContentFileInfos(document *domain.Document) (string, string) {
+	switch document.Type {
+	case domain.DocumentTypePDF:
+		return "pdf", "application/pdf"
+	default:
+		return "bin", "application/octet-stream"
+	}
+}
+
 func (r *documentRouter) getPageContentFileInfos(page *domain.DocumentPage) (string, string) {
 	switch page.Type {
 	case domain.PageTypeTIFF:
-		return ".tiff", "image/tiff"
+		return "tiff", "image/tiff"
+	default:
+		return "bin", "application/octet-stream"
+	}
+}
+
+func (r *documentRouter) getOriginalFileInfos(original *domain.PageOriginal) (string, string) {
+	fileName := original.FileName
+	if strings.TrimSpace(fileName) == "" {
+		fileName = string(original.Fingerprint)
+	}
+
+	mimeType := original.ContentType
+	if strings.TrimSpace(mimeType) == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	return fileName, mimeType
+}
+
+func (r *documentRouter) getRenditionFileInfos(rendition *domain.PageRendition) (string, string) {
+	switch rendition.Format {
+	case domain.RenditionFormatPNG:
+		return "png", "image/png"
+	case domain.RenditionFormatJPEG:
+		return "jpeg", "image/jpeg"
 	default:
 		return "bin", "application/octet-stream"
 	}
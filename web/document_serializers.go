@@ -9,19 +9,47 @@ import (
 )
 
 type documentResponse struct {
-	DocumentNumber uint       `json:"documentNumber,omitempty"`
-	Title          string     `json:"title"`
-	Date           *time.Time `json:"date,omitempty"`
-	State          string     `json:"state"`
-	Fingerprint    string     `json:"fingerprint,omitempty"`
-	Type           string     `json:"type,omitempty"`
-	PageCount      int        `json:"pageCount"`
-	CreatedAt      time.Time  `json:"createdAt"`
-	UpdatedAt      time.Time  `json:"updatedAt,omitempty"`
+	DocumentNumber uint          `json:"documentNumber,omitempty"`
+	Title          string        `json:"title"`
+	Date           *time.Time    `json:"date,omitempty"`
+	State          string        `json:"state"`
+	Fingerprint    string        `json:"fingerprint,omitempty"`
+	Type           string        `json:"type,omitempty"`
+	Language       string        `json:"language,omitempty"`
+	PageCount      int           `json:"pageCount"`
+	CreatedAt      time.Time     `json:"createdAt"`
+	UpdatedAt      time.Time     `json:"updatedAt,omitempty"`
+	DeletedAt      *time.Time    `json:"deletedAt,omitempty"`
+	Tags           []tagResponse `json:"tags"`
+
+	CustomFields  []customFieldValueResponse      `json:"customFields"`
+	Class         *documentClassResponse          `json:"class,omitempty"`
+	Correspondent *correspondentReferenceResponse `json:"correspondent,omitempty"`
 }
 
 type documentSearchResultResponse struct {
-	Document documentResponse `json:"document"`
+	Document       documentResponse `json:"document"`
+	Score          float64          `json:"score"`
+	TitleFragments []string         `json:"titleFragments,omitempty"`
+	TextFragments  []string         `json:"textFragments,omitempty"`
+	PageNumbers    []uint           `json:"pageNumbers"`
+}
+
+type documentFacetResultResponse struct {
+	Name    string                      `json:"name"`
+	Total   int                         `json:"total"`
+	Missing int                         `json:"missing"`
+	Terms   []documentFacetTermResponse `json:"terms"`
+}
+
+type documentFacetTermResponse struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+type documentSearchSuggestionsResponse struct {
+	Completions []string `json:"completions"`
+	Correction  string   `json:"correction,omitempty"`
 }
 
 type (
@@ -44,6 +72,21 @@ type (
 		C             echo.Context
 		SearchResults []domain.DocumentSearchResult
 	}
+
+	documentSearchSuggestionsSerializer struct {
+		C echo.Context
+		*domain.DocumentSearchSuggestions
+	}
+
+	documentFacetResultSerializer struct {
+		C echo.Context
+		*domain.DocumentFacetResult
+	}
+
+	documentFacetResultListSerializer struct {
+		C            echo.Context
+		FacetResults []domain.DocumentFacetResult
+	}
 )
 
 // Response returns the API response for a document.
@@ -55,9 +98,15 @@ func (s documentSerializer) Response() documentResponse {
 		Fingerprint:    string(s.Fingerprint),
 		State:          string(s.State),
 		Type:           string(s.Type),
+		Language:       string(s.Language),
 		CreatedAt:      s.CreatedAt,
 		UpdatedAt:      s.UpdatedAt,
+		DeletedAt:      s.DeletedAt,
 		PageCount:      len(s.Pages),
+		Tags:           tagListSerializer{s.C, s.Tags}.responses(),
+		CustomFields:   customFieldValueListSerializer{s.C, s.CustomFields}.responses(),
+		Class:          documentClassSerializer{s.C, s.Class}.OptionalResponse(),
+		Correspondent:  correspondentSerializer{s.C, s.Correspondent}.ReferenceResponse(),
 	}
 }
 
@@ -75,8 +124,17 @@ func (s documentListSerializer) Response() []interface{} {
 
 // Response returns the API response for a document search result.
 func (s documentSearchResultSerializer) Response() documentSearchResultResponse {
+	pageNumbers := make([]uint, len(s.PageNumbers))
+	for i, pageNumber := range s.PageNumbers {
+		pageNumbers[i] = uint(pageNumber)
+	}
+
 	return documentSearchResultResponse{
-		Document: documentSerializer{s.C, s.Document}.Response(),
+		Document:       documentSerializer{s.C, s.Document}.Response(),
+		Score:          s.Score,
+		TitleFragments: s.TitleFragments,
+		TextFragments:  s.TextFragments,
+		PageNumbers:    pageNumbers,
 	}
 }
 
@@ -91,3 +149,41 @@ func (s documentSearchResultListSerializer) Response() []interface{} {
 
 	return response
 }
+
+// Response returns the API response for search suggestions.
+func (s documentSearchSuggestionsSerializer) Response() documentSearchSuggestionsResponse {
+	return documentSearchSuggestionsResponse{
+		Completions: s.Completions,
+		Correction:  s.Correction,
+	}
+}
+
+// Response returns the API response for a document facet result.
+func (s documentFacetResultSerializer) Response() documentFacetResultResponse {
+	terms := make([]documentFacetTermResponse, len(s.Terms))
+	for i, term := range s.Terms {
+		terms[i] = documentFacetTermResponse{
+			Term:  term.Term,
+			Count: term.Count,
+		}
+	}
+
+	return documentFacetResultResponse{
+		Name:    string(s.Facet),
+		Total:   s.Total,
+		Missing: s.Missing,
+		Terms:   terms,
+	}
+}
+
+// Response returns the API response for a list of document facet results.
+func (s documentFacetResultListSerializer) Response() []interface{} {
+	response := make([]interface{}, len(s.FacetResults))
+
+	for i, result := range s.FacetResults {
+		serializer := documentFacetResultSerializer{s.C, &result}
+		response[i] = serializer.Response()
+	}
+
+	return response
+}
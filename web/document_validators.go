@@ -1,14 +1,29 @@
 package web
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/concepts-system/go-paperless/application"
 	"github.com/concepts-system/go-paperless/domain"
+	"github.com/concepts-system/go-paperless/errors"
 )
 
+// defaultSuggestionCount defines the number of search term completions
+// returned unless requested otherwise.
+const defaultSuggestionCount = 10
+
+// defaultSimilarityThreshold defines the minimum similarity of pages
+// considered to look alike if not requested otherwise.
+const defaultSimilarityThreshold = 0.9
+
 type documentValidator struct {
-	Title string     `json:"title" validate:"required,min=1,max=255"`
-	Date  *time.Time `json:"date"`
+	Title           string     `json:"title" validate:"required,min=1,max=255"`
+	Date            *time.Time `json:"date"`
+	ClassID         *uint      `json:"classId" validate:"omitempty,min=1"`
+	CorrespondentID *uint      `json:"correspondentId" validate:"omitempty,min=1"`
 
 	document domain.Document
 }
@@ -21,18 +36,262 @@ func (v *documentValidator) Bind(c *context) error {
 
 	v.document.Title = domain.Text(v.Title)
 	v.document.Date = v.Date
+	v.document.Class = nil
+	v.document.Correspondent = nil
+
+	if v.ClassID != nil {
+		v.document.Class = &domain.DocumentClass{ID: domain.DocumentClassID(*v.ClassID)}
+	}
+
+	if v.CorrespondentID != nil {
+		v.document.Correspondent = &domain.Correspondent{ID: domain.CorrespondentID(*v.CorrespondentID)}
+	}
+
+	return nil
+}
+
+type documentSplitValidator struct {
+	PageNumber uint `json:"pageNumber" validate:"required,min=2"`
+}
+
+type documentMergeValidator struct {
+	DocumentNumbers []uint `json:"documentNumbers" validate:"required,min=1,dive,min=1"`
+}
+
+type documentExportValidator struct {
+	DocumentNumbers []uint `query:"documentNumbers" validate:"dive,min=1"`
+}
+
+type documentTagsValidator struct {
+	TagIDs []uint `json:"tagIds" validate:"dive,min=1"`
+}
+
+type documentCustomFieldsValidator struct {
+	CustomFields []documentCustomFieldValueValidator `json:"customFields" validate:"dive"`
+
+	values []domain.CustomFieldValue
+}
+
+type documentCustomFieldValueValidator struct {
+	FieldID uint            `json:"fieldId" validate:"required,min=1"`
+	Value   json.RawMessage `json:"value" validate:"required"`
+}
+
+type documentFilterValidator struct {
+	TagIDs          []uint `query:"tags" validate:"dive,min=1"`
+	ClassID         *uint  `query:"class" validate:"omitempty,min=1"`
+	CorrespondentID *uint  `query:"correspondent" validate:"omitempty,min=1"`
+
+	filter domain.DocumentFilter
+}
+
+type documentSearchValidator struct {
+	Query     string   `query:"query"`
+	Highlight string   `query:"highlight" validate:"omitempty,oneof=html text"`
+	Facets    []string `query:"facets" validate:"unique,dive,oneof=year month state pageCount tags class correspondent type"`
+}
+
+type documentSuggestionValidator struct {
+	Query string `query:"query" validate:"required,max=1024"`
+	Size  int    `query:"size" validate:"min=1,max=50"`
+}
+
+type documentSimilarityValidator struct {
+	Threshold float64 `query:"threshold" validate:"min=0.5,max=1"`
+}
+
+// Bind binds the given request to a document split.
+func (v *documentSplitValidator) Bind(c *context) error {
+	return c.BindAndValidate(v)
+}
+
+// Bind binds the given request to a document merge.
+func (v *documentMergeValidator) Bind(c *context) error {
+	return c.BindAndValidate(v)
+}
+
+// Bind binds the given request to a document export.
+func (v *documentExportValidator) Bind(c *context) error {
+	return c.BindAndValidate(v)
+}
+
+// Bind binds the given request to a document's tags.
+func (v *documentTagsValidator) Bind(c *context) error {
+	return c.BindAndValidate(v)
+}
+
+// Bind binds the given request to a set of custom field values. The values
+// have to be validated against the types of their fields using BindValues.
+func (v *documentCustomFieldsValidator) Bind(c *context) error {
+	return c.BindAndValidate(v)
+}
+
+// FieldIDs returns the IDs of the custom fields values are given for.
+func (v *documentCustomFieldsValidator) FieldIDs() []uint {
+	ids := make([]uint, len(v.CustomFields))
+	for i, customField := range v.CustomFields {
+		ids[i] = customField.FieldID
+	}
+
+	return ids
+}
+
+// BindValues validates the given values against the types of the given
+// custom fields and binds them in their canonical text representation.
+func (v *documentCustomFieldsValidator) BindValues(customFields []domain.CustomField) error {
+	customFieldsByID := make(map[uint]domain.CustomField, len(customFields))
+	for _, customField := range customFields {
+		customFieldsByID[uint(customField.ID)] = customField
+	}
+
+	var validationError error
+	v.values = make([]domain.CustomFieldValue, 0, len(v.CustomFields))
+
+	for i, value := range v.CustomFields {
+		customField, ok := customFieldsByID[value.FieldID]
+		if !ok {
+			err := application.NotFoundError.Newf("Custom field %d does not exist", value.FieldID)
+			return errors.AddContext(err, "customFields", "exists")
+		}
+
+		canonicalValue, ok := parseCustomFieldValue(customField.Type, value.Value)
+		if !ok {
+			if validationError == nil {
+				validationError = application.BadRequestError.New("Validation failed")
+			}
+
+			validationError = errors.AddContext(
+				validationError,
+				fmt.Sprintf("customFields[%d].value", i),
+				strings.ToLower(string(customField.Type)),
+			)
+
+			continue
+		}
+
+		v.values = append(v.values, domain.CustomFieldValue{
+			Field: customField,
+			Value: canonicalValue,
+		})
+	}
+
+	return validationError
+}
+
+// Bind binds the given request to a document filter.
+func (v *documentFilterValidator) Bind(c *context) error {
+	if err := c.BindAndValidate(v); err != nil {
+		return err
+	}
+
+	v.filter.TagIDs = make([]domain.TagID, len(v.TagIDs))
+	for i, tagID := range v.TagIDs {
+		v.filter.TagIDs[i] = domain.TagID(tagID)
+	}
+
+	if v.ClassID != nil {
+		classID := domain.DocumentClassID(*v.ClassID)
+		v.filter.ClassID = &classID
+	}
+
+	if v.CorrespondentID != nil {
+		correspondentID := domain.CorrespondentID(*v.CorrespondentID)
+		v.filter.CorrespondentID = &correspondentID
+	}
 
 	return nil
 }
 
+// Bind binds the given request to a search query.
+func (v *documentSearchValidator) Bind(c *context) error {
+	return c.BindAndValidate(v)
+}
+
+// SearchRequest returns the search request for the bound search query
+// restricted by the given filter and page request.
+func (v *documentSearchValidator) SearchRequest(
+	filter domain.DocumentFilter,
+	pr domain.PageRequest,
+) domain.DocumentSearchRequest {
+	facets := make([]domain.DocumentFacet, len(v.Facets))
+	for i, facet := range v.Facets {
+		facets[i] = domain.DocumentFacet(facet)
+	}
+
+	return domain.DocumentSearchRequest{
+		Query:     v.Query,
+		Filter:    filter,
+		Highlight: domain.SearchHighlightStyle(v.Highlight),
+		Facets:    facets,
+		Page:      pr,
+	}
+}
+
+// Bind binds the given request to a search suggestion query.
+func (v *documentSuggestionValidator) Bind(c *context) error {
+	return c.BindAndValidate(v)
+}
+
+// Bind binds the given request to a similarity query.
+func (v *documentSimilarityValidator) Bind(c *context) error {
+	return c.BindAndValidate(v)
+}
+
 func newDocumentValidator() *documentValidator {
 	return &documentValidator{}
 }
 
-// func newDocumentValidatorOf(document *domain.Document) *documentValidator {
-// 	validator := newDocumentValidator()
-// 	validator.Title = string(document.Title)
-// 	validator.Date = document.Date
+func newDocumentValidatorOf(document *domain.Document) *documentValidator {
+	validator := newDocumentValidator()
+	validator.document = *document
+	validator.Title = string(document.Title)
+	validator.Date = document.Date
+
+	if document.Class != nil {
+		classID := uint(document.Class.ID)
+		validator.ClassID = &classID
+	}
 
-// 	return validator
-// }
+	if document.Correspondent != nil {
+		correspondentID := uint(document.Correspondent.ID)
+		validator.CorrespondentID = &correspondentID
+	}
+
+	return validator
+}
+
+func newDocumentSplitValidator() *documentSplitValidator {
+	return &documentSplitValidator{}
+}
+
+func newDocumentMergeValidator() *documentMergeValidator {
+	return &documentMergeValidator{}
+}
+
+func newDocumentExportValidator() *documentExportValidator {
+	return &documentExportValidator{}
+}
+
+func newDocumentTagsValidator() *documentTagsValidator {
+	return &documentTagsValidator{}
+}
+
+func newDocumentCustomFieldsValidator() *documentCustomFieldsValidator {
+	return &documentCustomFieldsValidator{}
+}
+
+func newDocumentFilterValidator() *documentFilterValidator {
+	return &documentFilterValidator{}
+}
+
+func newDocumentSearchValidator() *documentSearchValidator {
+	return &documentSearchValidator{}
+}
+
+func newDocumentSuggestionValidator() *documentSuggestionValidator {
+	return &documentSuggestionValidator{Size: defaultSuggestionCount}
+}
+
+func newDocumentSimilarityValidator() *documentSimilarityValidator {
+	return &documentSimilarityValidator{Threshold: defaultSimilarityThreshold}
+}
@@ -0,0 +1,134 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/concepts-system/go-paperless/application"
+)
+
+type tagRouter struct {
+	tagService application.TagService
+}
+
+// NewTagRouter creates a new router for tag management using the given tag
+// service.
+func NewTagRouter(tagService application.TagService) Router {
+	return &tagRouter{
+		tagService: tagService,
+	}
+}
+
+// DefineRoutes defines the routes for tag management.
+func (r *tagRouter) DefineRoutes(group *echo.Group, auth *AuthMiddleware) {
+	apiGroup := group.Group("/api", auth.RequireScope(application.TokenScopeAPI))
+
+	tagGroup := apiGroup.Group("/tags", auth.RequireAuthentication())
+	tagGroup.GET("", r.getTags)
+	tagGroup.POST("", r.createTag)
+	tagGroup.GET("/:id", r.getTag)
+	tagGroup.PUT("/:id", r.updateTag)
+	tagGroup.DELETE("/:id", r.deleteTag)
+}
+
+/* Handlers */
+
+func (r *tagRouter) getTags(ec echo.Context) error {
+	c, _ := ec.(*context)
+	pr := c.BindPaging()
+
+	tags, totalCount, err := r.tagService.GetUserTags(*c.Username, pr.ToDomainPageRequest())
+	if err != nil {
+		return err
+	}
+
+	serializer := tagListSerializer{c, tags}
+	return c.Page(http.StatusOK, pr, totalCount, serializer.Response())
+}
+
+func (r *tagRouter) createTag(ec echo.Context) error {
+	c, _ := ec.(*context)
+	validator := newTagValidator()
+
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	tag, err := r.tagService.CreateNewTag(*c.Username, &validator.tag)
+	if err != nil {
+		return err
+	}
+
+	serializer := tagSerializer{c, tag}
+	return c.JSON(http.StatusCreated, serializer.Response())
+}
+
+func (r *tagRouter) getTag(ec echo.Context) error {
+	c, _ := ec.(*context)
+	id, err := r.bindTagID(c)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.tagService.GetUserTagByID(*c.Username, id)
+	if err != nil {
+		return err
+	}
+
+	serializer := tagSerializer{c, tag}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *tagRouter) updateTag(ec echo.Context) error {
+	c, _ := ec.(*context)
+	id, err := r.bindTagID(c)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.tagService.GetUserTagByID(*c.Username, id)
+	if err != nil {
+		return err
+	}
+
+	validator := newTagValidatorOf(tag)
+	if err := validator.Bind(c); err != nil {
+		return err
+	}
+
+	tag, err = r.tagService.UpdateUserTag(*c.Username, &validator.tag)
+	if err != nil {
+		return err
+	}
+
+	serializer := tagSerializer{c, tag}
+	return c.JSON(http.StatusOK, serializer.Response())
+}
+
+func (r *tagRouter) deleteTag(ec echo.Context) error {
+	c, _ := ec.(*context)
+	id, err := r.bindTagID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := r.tagService.DeleteUserTag(*c.Username, id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+/* Helper Methods */
+
+func (r *tagRouter) bindTagID(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	if err != nil || id <= 0 {
+		return 0, application.BadRequestError.New("Tag ID has to be a positive integer")
+	}
+
+	return uint(id), nil
+}
@@ -0,0 +1,58 @@
+package web
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/concepts-system/go-paperless/domain"
+)
+
+type tagResponse struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+type (
+	tagSerializer struct {
+		C echo.Context
+		*domain.Tag
+	}
+
+	tagListSerializer struct {
+		C    echo.Context
+		Tags []domain.Tag
+	}
+)
+
+// Response returns the API response for a tag.
+func (s tagSerializer) Response() tagResponse {
+	return tagResponse{
+		ID:    uint(s.ID),
+		Name:  string(s.Name),
+		Color: string(s.Color),
+	}
+}
+
+// Response returns the API response for a list of tags.
+func (s tagListSerializer) Response() []interface{} {
+	response := make([]interface{}, len(s.Tags))
+
+	for i, tag := range s.responses() {
+		response[i] = tag
+	}
+
+	return response
+}
+
+// responses returns the typed API responses for a list of tags, e.g. for
+// embedding them into other responses.
+func (s tagListSerializer) responses() []tagResponse {
+	responses := make([]tagResponse, len(s.Tags))
+
+	for i, tag := range s.Tags {
+		serializer := tagSerializer{s.C, &tag}
+		responses[i] = serializer.Response()
+	}
+
+	return responses
+}
@@ -0,0 +1,37 @@
+package web
+
+import (
+	"github.com/concepts-system/go-paperless/domain"
+)
+
+type tagValidator struct {
+	Name  string `json:"name" validate:"required,min=1,max=255"`
+	Color string `json:"color" validate:"omitempty,hexcolor"`
+
+	tag domain.Tag
+}
+
+// Bind binds the given request to a tag model.
+func (v *tagValidator) Bind(c *context) error {
+	if err := c.BindAndValidate(v); err != nil {
+		return err
+	}
+
+	v.tag.Name = domain.Name(v.Name)
+	v.tag.Color = domain.Color(v.Color)
+
+	return nil
+}
+
+func newTagValidator() *tagValidator {
+	return &tagValidator{}
+}
+
+func newTagValidatorOf(tag *domain.Tag) *tagValidator {
+	validator := newTagValidator()
+	validator.tag = *tag
+	validator.Name = string(tag.Name)
+	validator.Color = string(tag.Color)
+
+	return validator
+}